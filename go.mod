@@ -0,0 +1,3 @@
+module sourcegraph.com/sourcegraph/go-sourcegraph
+
+go 1.18
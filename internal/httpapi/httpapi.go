@@ -0,0 +1,178 @@
+// Package httpapi contains the HTTP handler helpers that are shared
+// by the packages that serve the API (server and sourcegraphtest):
+// writing errors the way the Sourcegraph API does, and decoding
+// request bodies, querystring options, and route variables.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gorilla/schema"
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// NotImplementedHandler responds with HTTP 501 Not Implemented to
+// requests that match a route of Router that has no handler. Other
+// requests are served by Router.
+type NotImplementedHandler struct{ Router *mux.Router }
+
+func (h NotImplementedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var match mux.RouteMatch
+	if h.Router.Match(r, &match) && match.Handler == nil {
+		name := ""
+		if match.Route != nil {
+			name = match.Route.GetName()
+		}
+		WriteError(w, http.StatusNotImplemented, "route not implemented: "+name)
+		return
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
+// HandlerFunc is an HTTP handler func that returns an error, which is
+// written to the response as an API error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		WriteError(w, ErrorHTTPStatusCode(err), ErrorMessage(err))
+	}
+}
+
+// ErrorHTTPStatusCode returns the HTTP status code that the API
+// responds with for err.
+//
+// If err is (or wraps) an *sourcegraph.ErrorResponse from another API
+// server, as it is when proxying, the other server's status code is
+// used.
+func ErrorHTTPStatusCode(err error) int {
+	var errResp *sourcegraph.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+
+	var renamed sourcegraph.ErrRenamed
+	var userRenamed sourcegraph.ErrUserRenamed
+	var redirect sourcegraph.ErrRedirect
+	switch {
+	case errors.As(err, &renamed), errors.As(err, &userRenamed), errors.As(err, &redirect):
+		return http.StatusMovedPermanently
+	case errors.Is(err, sourcegraph.ErrNotExist), errors.Is(err, sourcegraph.ErrBuildNotFound), errors.Is(err, sourcegraph.ErrUserNotExist), errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sourcegraph.ErrForbidden):
+		return http.StatusForbidden
+	}
+	var badRequest *BadRequestError
+	var invalidOptions *sourcegraph.InvalidOptionsError
+	if errors.As(err, &badRequest) || errors.As(err, &invalidOptions) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorMessage returns the message of the error response for err.
+// It is err's message, except for errors from another API server,
+// whose original message is used (so that the client recognizes it;
+// see sourcegraph.CheckResponse).
+func ErrorMessage(err error) string {
+	var errResp *sourcegraph.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Message
+	}
+	return err.Error()
+}
+
+// ErrNotFound is returned by handlers when the requested object
+// doesn't exist and there is no more specific error.
+var ErrNotFound = errors.New("not found")
+
+// BadRequestError indicates that the request was malformed.
+type BadRequestError struct{ Err error }
+
+func (e *BadRequestError) Error() string { return e.Err.Error() }
+
+// WriteError writes an API error response with the given status code
+// and message.
+func WriteError(w http.ResponseWriter, statusCode int, msg string) {
+	w.Header().Set("content-type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(&sourcegraph.ErrorResponse{Message: msg})
+}
+
+// ReadJSON decodes r's JSON body into v.
+func ReadJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &BadRequestError{err}
+	}
+	return nil
+}
+
+var schemaDecoder = schema.NewDecoder()
+
+func init() {
+	schemaDecoder.IgnoreUnknownKeys(true)
+}
+
+// DecodeOptions decodes r's querystring into opt, which is a pointer
+// to an options struct. It undoes the querystring encoding that the
+// client performs (with github.com/google/go-querystring): nested
+// struct fields are named "A[B]", and the values of fields with the
+// "comma" url tag option are joined by commas.
+func DecodeOptions(r *http.Request, opt interface{}) error {
+	q := r.URL.Query()
+	for k, v := range q {
+		if strings.Contains(k, "[") {
+			delete(q, k)
+			q[strings.NewReplacer("][", ".", "[", ".", "]", "").Replace(k)] = v
+		}
+	}
+	if err := schemaDecoder.Decode(opt, q); err != nil {
+		return &BadRequestError{err}
+	}
+	splitCommaValues(reflect.ValueOf(opt))
+	return nil
+}
+
+// splitCommaValues splits the comma-joined values of the []string
+// fields (with the "comma" url tag option) of the struct that v
+// points to, and of its embedded and nested structs.
+func splitCommaValues(v reflect.Value) {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue // unexported
+		}
+		switch {
+		case fv.Kind() == reflect.Struct || (fv.Kind() == reflect.Ptr && !fv.IsNil()):
+			splitCommaValues(fv)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String && hasTagOption(f.Tag.Get("url"), "comma"):
+			var vals []string
+			for j := 0; j < fv.Len(); j++ {
+				vals = append(vals, strings.Split(fv.Index(j).String(), ",")...)
+			}
+			fv.Set(reflect.ValueOf(vals).Convert(fv.Type()))
+		}
+	}
+}
+
+// hasTagOption reports whether the struct tag value tag (such as
+// "name,omitempty,comma") has the option opt.
+func hasTagOption(tag, opt string) bool {
+	opts := strings.Split(tag, ",")
+	for _, o := range opts[1:] {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type testNested struct {
+	Y    int
+	Tags []string `url:",comma"`
+}
+
+type testOptions struct {
+	Langs  []string `url:",comma,omitempty"`
+	Kinds  []string `url:",omitempty"`
+	Nested testNested
+	sourcegraph.ListOptions
+}
+
+func TestDecodeOptions(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/?Langs=a,b&Langs=c&Kinds=d,e&Nested[Y]=1&Nested[Tags]=f,g&PerPage=2", nil)
+	var opt testOptions
+	if err := DecodeOptions(r, &opt); err != nil {
+		t.Fatal(err)
+	}
+	want := testOptions{
+		Langs:       []string{"a", "b", "c"},
+		Kinds:       []string{"d,e"},
+		Nested:      testNested{Y: 1, Tags: []string{"f", "g"}},
+		ListOptions: sourcegraph.ListOptions{PerPage: 2},
+	}
+	if !reflect.DeepEqual(opt, want) {
+		t.Errorf("got %+v, want %+v", opt, want)
+	}
+}
@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"strconv"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// This file contains functions that decode specs from route
+// variables. They return decoding errors as *BadRequestError values.
+// Where the sourcegraph package has an Unmarshal or Parse function
+// for a spec, they use it.
+
+func RepoSpecFromVars(vars map[string]string) (sourcegraph.RepoSpec, error) {
+	repo, err := sourcegraph.UnmarshalRepoSpec(vars)
+	if err != nil {
+		return repo, &BadRequestError{err}
+	}
+	return repo, nil
+}
+
+func RepoRevSpecFromVars(vars map[string]string) (sourcegraph.RepoRevSpec, error) {
+	repoRev, err := sourcegraph.UnmarshalRepoRevSpec(vars)
+	if err != nil {
+		return repoRev, &BadRequestError{err}
+	}
+	return repoRev, nil
+}
+
+func UserSpecFromVars(vars map[string]string) (sourcegraph.UserSpec, error) {
+	user, err := sourcegraph.ParseUserSpec(vars["UserSpec"])
+	if err != nil {
+		return user, &BadRequestError{err}
+	}
+	return user, nil
+}
+
+func OrgSpecFromVars(vars map[string]string) (sourcegraph.OrgSpec, error) {
+	org, err := sourcegraph.ParseOrgSpec(vars["OrgSpec"])
+	if err != nil {
+		return org, &BadRequestError{err}
+	}
+	return org, nil
+}
+
+func PersonSpecFromVars(vars map[string]string) (sourcegraph.PersonSpec, error) {
+	person, err := sourcegraph.ParsePersonSpec(vars["PersonSpec"])
+	if err != nil {
+		return person, &BadRequestError{err}
+	}
+	return person, nil
+}
+
+func BuildSpecFromVars(vars map[string]string) (sourcegraph.BuildSpec, error) {
+	bid, err := strconv.ParseInt(vars["BID"], 10, 64)
+	if err != nil {
+		return sourcegraph.BuildSpec{}, &BadRequestError{err}
+	}
+	return sourcegraph.BuildSpec{BID: bid}, nil
+}
+
+func TaskSpecFromVars(vars map[string]string) (sourcegraph.TaskSpec, error) {
+	build, err := BuildSpecFromVars(vars)
+	if err != nil {
+		return sourcegraph.TaskSpec{}, err
+	}
+	taskID, err := strconv.ParseInt(vars["TaskID"], 10, 64)
+	if err != nil {
+		return sourcegraph.TaskSpec{}, &BadRequestError{err}
+	}
+	return sourcegraph.TaskSpec{BuildSpec: build, TaskID: taskID}, nil
+}
+
+func DefSpecFromVars(vars map[string]string) (sourcegraph.DefSpec, error) {
+	def, err := sourcegraph.UnmarshalDefSpec(vars)
+	if err != nil {
+		return def, &BadRequestError{err}
+	}
+	return def, nil
+}
+
+func TreeEntrySpecFromVars(vars map[string]string) (sourcegraph.TreeEntrySpec, error) {
+	entry, err := sourcegraph.UnmarshalTreeEntrySpec(vars)
+	if err != nil {
+		return entry, &BadRequestError{err}
+	}
+	return entry, nil
+}
+
+func IssueSpecFromVars(vars map[string]string) (sourcegraph.IssueSpec, error) {
+	issue, err := sourcegraph.UnmarshalIssueSpec(vars)
+	if err != nil {
+		return issue, &BadRequestError{err}
+	}
+	return issue, nil
+}
+
+func PullRequestSpecFromVars(vars map[string]string) (sourcegraph.PullRequestSpec, error) {
+	pull, err := sourcegraph.UnmarshalPullRequestSpec(vars)
+	if err != nil {
+		return pull, &BadRequestError{err}
+	}
+	return pull, nil
+}
+
+func DeltaSpecFromVars(vars map[string]string) (sourcegraph.DeltaSpec, error) {
+	ds, err := sourcegraph.UnmarshalDeltaSpec(vars)
+	if err != nil {
+		return ds, &BadRequestError{err}
+	}
+	return ds, nil
+}
+
+// CommentIDFromVars returns the "CommentID" route variable of issue
+// and pull request comment routes.
+func CommentIDFromVars(vars map[string]string) (int, error) {
+	id, err := strconv.Atoi(vars["CommentID"])
+	if err != nil {
+		return 0, &BadRequestError{err}
+	}
+	return id, nil
+}
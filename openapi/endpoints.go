@@ -0,0 +1,174 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// An Endpoint documents an operation: a route (and HTTP method) of
+// the API router, along with the Go types of its querystring
+// options, request body, and response body.
+type Endpoint struct {
+	Route  string // route name (in package router)
+	Method string // HTTP method
+
+	// ID is the operation ID, which is the name of the API client
+	// method that calls the route (e.g., "Repos.Get"). The part
+	// before the "." is used as the operation's tag.
+	ID string
+
+	Summary    string
+	Deprecated bool
+
+	// Params, Body, and Result are values (usually zero values) of
+	// the types of the operation's querystring options, request
+	// body, and response body, respectively, or nil if the operation
+	// has none.
+	Params interface{}
+	Body   interface{}
+	Result interface{}
+}
+
+// Endpoints documents all of the operations in the API. Every route
+// in package router must have at least one endpoint.
+var Endpoints = []Endpoint{
+	// Builds
+	{Route: router.Builds, Method: "GET", ID: "Builds.List", Summary: "List builds", Params: sourcegraph.BuildListOptions{}, Result: []*sourcegraph.Build{}},
+	{Route: router.BuildDequeueNext, Method: "POST", ID: "Builds.DequeueNext", Summary: "Dequeue the next queued build", Result: sourcegraph.Build{}},
+	{Route: router.Build, Method: "GET", ID: "Builds.Get", Summary: "Get a build", Params: sourcegraph.BuildGetOptions{}, Result: sourcegraph.Build{}},
+	{Route: router.BuildUpdate, Method: "PUT", ID: "Builds.Update", Summary: "Update a build", Body: sourcegraph.BuildUpdate{}, Result: sourcegraph.Build{}},
+	{Route: router.BuildLog, Method: "GET", ID: "Builds.GetLog", Summary: "Get a build's log", Params: sourcegraph.BuildGetLogOptions{}, Result: sourcegraph.LogEntries{}},
+	{Route: router.BuildTasks, Method: "GET", ID: "Builds.ListBuildTasks", Summary: "List a build's tasks", Params: sourcegraph.BuildTaskListOptions{}, Result: []*sourcegraph.BuildTask{}},
+	{Route: router.BuildTasksCreate, Method: "POST", ID: "Builds.CreateTasks", Summary: "Create tasks for a build", Body: []*sourcegraph.BuildTask{}, Result: []*sourcegraph.BuildTask{}},
+	{Route: router.BuildTaskUpdate, Method: "PUT", ID: "Builds.UpdateTask", Summary: "Update a build task", Body: sourcegraph.TaskUpdate{}, Result: sourcegraph.BuildTask{}},
+	{Route: router.BuildTaskLog, Method: "GET", ID: "Builds.GetTaskLog", Summary: "Get a build task's log", Params: sourcegraph.BuildGetLogOptions{}, Result: sourcegraph.LogEntries{}},
+	{Route: router.RepoBuildsCreate, Method: "POST", ID: "Builds.Create", Summary: "Create a build of a repository revision", Body: sourcegraph.BuildCreateOptions{}, Result: sourcegraph.Build{}},
+
+	// Orgs
+	{Route: router.Org, Method: "GET", ID: "Orgs.Get", Summary: "Get an organization", Result: sourcegraph.Org{}},
+	{Route: router.OrgMembers, Method: "GET", ID: "Orgs.ListMembers", Summary: "List an organization's members", Params: sourcegraph.OrgListMembersOptions{}, Result: []*sourcegraph.User{}},
+	{Route: router.OrgSettings, Method: "GET", ID: "Orgs.GetSettings", Summary: "Get an organization's settings", Result: sourcegraph.OrgSettings{}},
+	{Route: router.OrgSettingsUpdate, Method: "PUT", ID: "Orgs.UpdateSettings", Summary: "Update an organization's settings", Body: sourcegraph.OrgSettings{}},
+
+	// Users
+	{Route: router.Users, Method: "GET", ID: "Users.List", Summary: "List users", Params: sourcegraph.UsersListOptions{}, Result: []*sourcegraph.User{}},
+	{Route: router.User, Method: "GET", ID: "Users.Get", Summary: "Get a user", Params: sourcegraph.UserGetOptions{}, Result: sourcegraph.User{}},
+	{Route: router.UserOrgs, Method: "GET", ID: "Users.ListOrgs", Summary: "List a user's organizations", Params: sourcegraph.UsersListOrgsOptions{}, Result: []*sourcegraph.Org{}},
+	{Route: router.UserAuthors, Method: "GET", ID: "Users.ListAuthors", Summary: "List the authors of code that a user uses", Params: sourcegraph.UsersListAuthorsOptions{}, Result: []*sourcegraph.AugmentedPersonUsageByClient{}},
+	{Route: router.UserClients, Method: "GET", ID: "Users.ListClients", Summary: "List the users of a user's code", Params: sourcegraph.UsersListClientsOptions{}, Result: []*sourcegraph.AugmentedPersonUsageOfAuthor{}},
+	{Route: router.UserEmails, Method: "GET", ID: "Users.ListEmails", Summary: "List a user's email addresses", Result: []*sourcegraph.EmailAddr{}},
+	{Route: router.UserFromGitHub, Method: "GET", ID: "Users.GetOrCreateFromGitHub", Summary: "Get (or create) the user for a GitHub user", Params: sourcegraph.UserGetOptions{}, Result: sourcegraph.User{}},
+	{Route: router.UserRepoContributions, Method: "GET", ID: "Repos.ListByContributor", Summary: "List repositories that a user has contributed to", Params: sourcegraph.RepoListByContributorOptions{}, Result: []*sourcegraph.AugmentedRepoContribution{}},
+	{Route: router.UserRepoDependencies, Method: "GET", ID: "Repos.ListByClient", Summary: "List repositories whose code a user uses", Params: sourcegraph.RepoListByClientOptions{}, Result: []*sourcegraph.AugmentedRepoUsageByClient{}},
+	{Route: router.UserRepoDependents, Method: "GET", ID: "Repos.ListByRefdAuthor", Summary: "List repositories that use a user's code", Params: sourcegraph.RepoListByRefdAuthorOptions{}, Result: []*sourcegraph.AugmentedRepoUsageOfAuthor{}},
+	{Route: router.UserRefreshProfile, Method: "PUT", ID: "Users.RefreshProfile", Summary: "Refresh a user's profile from external sources"},
+	{Route: router.UserSettings, Method: "GET", ID: "Users.GetSettings", Summary: "Get a user's settings", Result: sourcegraph.UserSettings{}},
+	{Route: router.UserSettingsUpdate, Method: "PUT", ID: "Users.UpdateSettings", Summary: "Update a user's settings", Body: sourcegraph.UserSettings{}},
+	{Route: router.UserComputeStats, Method: "PUT", ID: "Users.ComputeStats", Summary: "Recompute a user's statistics"},
+
+	// People
+	{Route: router.Person, Method: "GET", ID: "People.Get", Summary: "Get a person", Result: sourcegraph.Person{}},
+
+	// Pull requests
+	{Route: router.RepoPullRequests, Method: "GET", ID: "PullRequests.ListByRepo", Summary: "List a repository's pull requests", Params: sourcegraph.PullRequestListOptions{}, Result: []*sourcegraph.PullRequest{}},
+	{Route: router.RepoPullRequest, Method: "GET", ID: "PullRequests.Get", Summary: "Get a pull request", Params: sourcegraph.PullRequestGetOptions{}, Result: sourcegraph.PullRequest{}},
+	{Route: router.RepoPullRequestMerge, Method: "PUT", ID: "PullRequests.Merge", Summary: "Merge a pull request", Body: sourcegraph.PullRequestMergeRequest{}, Result: sourcegraph.PullRequestMergeResult{}},
+	{Route: router.RepoPullRequestComments, Method: "GET", ID: "PullRequests.ListComments", Summary: "List a pull request's comments", Params: sourcegraph.PullRequestListCommentsOptions{}, Result: []*sourcegraph.PullRequestComment{}},
+	{Route: router.RepoPullRequestCommentsCreate, Method: "POST", ID: "PullRequests.CreateComment", Summary: "Comment on a pull request", Body: sourcegraph.PullRequestComment{}, Result: sourcegraph.PullRequestComment{}},
+	{Route: router.RepoPullRequestCommentsEdit, Method: "PATCH", ID: "PullRequests.EditComment", Summary: "Edit a pull request comment", Body: sourcegraph.PullRequestComment{}, Result: sourcegraph.PullRequestComment{}},
+	{Route: router.RepoPullRequestCommentsDelete, Method: "DELETE", ID: "PullRequests.DeleteComment", Summary: "Delete a pull request comment"},
+
+	// Issues
+	{Route: router.RepoIssues, Method: "GET", ID: "Issues.ListByRepo", Summary: "List a repository's issues", Params: sourcegraph.IssueListOptions{}, Result: []*sourcegraph.Issue{}},
+	{Route: router.RepoIssue, Method: "GET", ID: "Issues.Get", Summary: "Get an issue", Params: sourcegraph.IssueGetOptions{}, Result: sourcegraph.Issue{}},
+	{Route: router.RepoIssueComments, Method: "GET", ID: "Issues.ListComments", Summary: "List an issue's comments", Params: sourcegraph.IssueListCommentsOptions{}, Result: []*sourcegraph.IssueComment{}},
+	{Route: router.RepoIssueCommentsCreate, Method: "POST", ID: "Issues.CreateComment", Summary: "Comment on an issue", Body: sourcegraph.IssueComment{}, Result: sourcegraph.IssueComment{}},
+	{Route: router.RepoIssueCommentsEdit, Method: "PATCH", ID: "Issues.EditComment", Summary: "Edit an issue comment", Body: sourcegraph.IssueComment{}, Result: sourcegraph.IssueComment{}},
+	{Route: router.RepoIssueCommentsDelete, Method: "DELETE", ID: "Issues.DeleteComment", Summary: "Delete an issue comment"},
+
+	// Repos
+	{Route: router.Repos, Method: "GET", ID: "Repos.List", Summary: "List repositories", Params: sourcegraph.RepoListOptions{}, Result: []*sourcegraph.Repo{}},
+	{Route: router.ReposCreate, Method: "POST", ID: "Repos.Create", Summary: "Create a repository", Body: sourcegraph.NewRepoSpec{}, Result: sourcegraph.Repo{}},
+	{Route: router.Repo, Method: "GET", ID: "Repos.Get", Summary: "Get a repository", Params: sourcegraph.RepoGetOptions{}, Result: sourcegraph.Repo{}},
+	{Route: router.ReposGetOrCreate, Method: "PUT", ID: "Repos.GetOrCreate", Summary: "Get (or create) a repository", Params: sourcegraph.RepoGetOptions{}, Result: sourcegraph.Repo{}},
+	{Route: router.RepoAuthors, Method: "GET", ID: "Repos.ListAuthors", Summary: "List the authors of a repository's code", Params: sourcegraph.RepoListAuthorsOptions{}, Result: []*sourcegraph.AugmentedRepoAuthor{}},
+	{Route: router.RepoClients, Method: "GET", ID: "Repos.ListClients", Summary: "List the users of a repository's code", Params: sourcegraph.RepoListClientsOptions{}, Result: []*sourcegraph.AugmentedRepoClient{}},
+	{Route: router.RepoDependents, Method: "GET", ID: "Repos.ListDependents", Summary: "List repositories that depend on a repository", Params: sourcegraph.RepoListDependentsOptions{}, Result: []*sourcegraph.AugmentedRepoDependent{}},
+	{Route: router.RepoDependencies, Method: "GET", ID: "Repos.ListDependencies", Summary: "List a repository's dependencies", Params: sourcegraph.RepoListDependenciesOptions{}, Result: []*sourcegraph.AugmentedRepoDependency{}},
+	{Route: router.RepoBadge, Method: "GET", ID: "Repos.GetBadge", Summary: "Get a repository badge image", Result: binary{}},
+	{Route: router.RepoBadges, Method: "GET", ID: "Repos.ListBadges", Summary: "List a repository's badges", Result: []*sourcegraph.Badge{}},
+	{Route: router.RepoCounter, Method: "GET", ID: "Repos.GetCounter", Summary: "Get a repository counter image", Result: binary{}},
+	{Route: router.RepoCounters, Method: "GET", ID: "Repos.ListCounters", Summary: "List a repository's counters", Result: []*sourcegraph.Counter{}},
+	{Route: router.RepoReadme, Method: "GET", ID: "Repos.GetReadme", Summary: "Get a repository's README", Result: vcsclient.TreeEntry{}},
+	{Route: router.RepoRefreshProfile, Method: "PUT", ID: "Repos.RefreshProfile", Summary: "Refresh a repository's profile from its external host"},
+	{Route: router.RepoRefreshVCSData, Method: "PUT", ID: "Repos.RefreshVCSData", Summary: "Fetch new VCS data for a repository"},
+	{Route: router.RepoComputeStats, Method: "PUT", ID: "Repos.ComputeStats", Summary: "Recompute a repository's statistics"},
+	{Route: router.RepoSettings, Method: "GET", ID: "Repos.GetSettings", Summary: "Get a repository's settings", Result: sourcegraph.RepoSettings{}},
+	{Route: router.RepoSettingsUpdate, Method: "PUT", ID: "Repos.UpdateSettings", Summary: "Update a repository's settings", Body: sourcegraph.RepoSettings{}},
+	{Route: router.RepoStats, Method: "GET", ID: "Repos.GetStats", Summary: "Get a repository's statistics", Result: sourcegraph.RepoStats{}},
+	{Route: router.RepoCombinedStatus, Method: "GET", ID: "Repos.GetCombinedStatus", Summary: "Get the combined status of a repository revision", Result: sourcegraph.CombinedStatus{}},
+	{Route: router.RepoStatusCreate, Method: "POST", ID: "Repos.CreateStatus", Summary: "Create a status for a repository revision", Body: sourcegraph.RepoStatus{}, Result: sourcegraph.RepoStatus{}},
+	{Route: router.RepoBuild, Method: "GET", ID: "Repos.GetBuild", Summary: "Get the build for a repository revision", Params: sourcegraph.RepoGetBuildOptions{}, Result: sourcegraph.RepoBuildInfo{}},
+	{Route: router.RepoCommits, Method: "GET", ID: "Repos.ListCommits", Summary: "List a repository's commits", Params: sourcegraph.RepoListCommitsOptions{}, Result: []*sourcegraph.Commit{}},
+	{Route: router.RepoCommit, Method: "GET", ID: "Repos.GetCommit", Summary: "Get a commit", Params: sourcegraph.RepoGetCommitOptions{}, Result: sourcegraph.Commit{}},
+	{Route: router.RepoCompareCommits, Method: "GET", ID: "Repos.CompareCommits", Summary: "Compare two commits", Params: sourcegraph.RepoCompareCommitsOptions{}, Result: sourcegraph.CommitsComparison{}},
+	{Route: router.RepoBranches, Method: "GET", ID: "Repos.ListBranches", Summary: "List a repository's branches", Params: sourcegraph.RepoListBranchesOptions{}, Result: []*vcs.Branch{}},
+	{Route: router.RepoTags, Method: "GET", ID: "Repos.ListTags", Summary: "List a repository's tags", Params: sourcegraph.RepoListTagsOptions{}, Result: []*vcs.Tag{}},
+	{Route: router.RedirectOldRepoBadgesAndCounters, Method: "GET", ID: "Repos.RedirectOldBadgesAndCounters", Summary: "Redirect from an old badge or counter URL", Deprecated: true},
+
+	// Build data
+	{Route: router.RepoBuildDataEntry, Method: "GET", ID: "BuildData.Get", Summary: "Get a build data file (or directory listing)", Result: binary{}},
+	{Route: router.RepoBuildDataEntry, Method: "HEAD", ID: "BuildData.Stat", Summary: "Get information about a build data file"},
+	{Route: router.RepoBuildDataEntry, Method: "PUT", ID: "BuildData.Put", Summary: "Create or overwrite a build data file", Body: binary{}},
+	{Route: router.RepoBuildDataEntry, Method: "DELETE", ID: "BuildData.Delete", Summary: "Delete a build data file"},
+
+	// Repo tree
+	{Route: router.RepoTreeEntry, Method: "GET", ID: "RepoTree.Get", Summary: "Get a file or directory in a repository", Params: sourcegraph.RepoTreeGetOptions{}, Result: sourcegraph.TreeEntry{}},
+
+	// Search
+	{Route: router.Search, Method: "GET", ID: "Search.Search", Summary: "Search", Params: sourcegraph.SearchOptions{}, Result: sourcegraph.SearchResults{}},
+	{Route: router.SearchComplete, Method: "GET", ID: "Search.Complete", Summary: "Complete a search query", Params: sourcegraph.RawQuery{}, Result: sourcegraph.Completions{}},
+	{Route: router.SearchSuggestions, Method: "GET", ID: "Search.Suggest", Summary: "Suggest search queries", Params: sourcegraph.RawQuery{}, Result: []*sourcegraph.Suggestion{}},
+
+	// Snippets
+	{Route: router.Snippet, Method: "POST", ID: "Snippets.Annotate", Summary: "Link and highlight a snippet of code", Body: sourcegraph.SnippetRequestBody{}, Result: sourcegraph.FileData{}},
+	{Route: router.RepoTreeSourcebox, Method: "GET", ID: "Snippets.FileSourcebox", Summary: "Get an embeddable sourcebox for a file", Params: sourcegraph.SourceboxFileOptions{}, Result: sourcegraph.Sourcebox{}},
+	{Route: router.DefSourcebox, Method: "GET", ID: "Snippets.DefSourcebox", Summary: "Get an embeddable sourcebox for a def", Result: sourcegraph.Sourcebox{}},
+
+	// Defs
+	{Route: router.Defs, Method: "GET", ID: "Defs.List", Summary: "List defs", Params: sourcegraph.DefListOptions{}, Result: []*sourcegraph.Def{}},
+	{Route: router.Def, Method: "GET", ID: "Defs.Get", Summary: "Get a def", Params: sourcegraph.DefGetOptions{}, Result: sourcegraph.Def{}},
+	{Route: router.DefRefs, Method: "GET", ID: "Defs.ListRefs", Summary: "List references to a def", Params: sourcegraph.DefListRefsOptions{}, Result: []*sourcegraph.Ref{}},
+	{Route: router.DefExamples, Method: "GET", ID: "Defs.ListExamples", Summary: "List usage examples of a def", Params: sourcegraph.DefListExamplesOptions{}, Result: []*sourcegraph.Example{}},
+	{Route: router.DefAuthors, Method: "GET", ID: "Defs.ListAuthors", Summary: "List the authors of a def", Params: sourcegraph.DefListAuthorsOptions{}, Result: []*sourcegraph.AugmentedDefAuthor{}},
+	{Route: router.DefClients, Method: "GET", ID: "Defs.ListClients", Summary: "List the users of a def", Params: sourcegraph.DefListClientsOptions{}, Result: []*sourcegraph.AugmentedDefClient{}},
+	{Route: router.DefDependents, Method: "GET", ID: "Defs.ListDependents", Summary: "List repositories that use a def", Params: sourcegraph.DefListDependentsOptions{}, Result: []*sourcegraph.AugmentedDefDependent{}},
+	{Route: router.DefVersions, Method: "GET", ID: "Defs.ListVersions", Summary: "List the versions of a def in other commits", Params: sourcegraph.DefListVersionsOptions{}, Result: []*sourcegraph.Def{}},
+
+	// Deltas
+	{Route: router.Delta, Method: "GET", ID: "Deltas.Get", Summary: "Get a delta", Params: sourcegraph.DeltaGetOptions{}, Result: sourcegraph.Delta{}},
+	{Route: router.DeltaUnits, Method: "GET", ID: "Deltas.ListUnits", Summary: "List the source units changed in a delta", Params: sourcegraph.DeltaListUnitsOptions{}, Result: []*sourcegraph.UnitDelta{}},
+	{Route: router.DeltaDefs, Method: "GET", ID: "Deltas.ListDefs", Summary: "List the defs changed in a delta", Params: sourcegraph.DeltaListDefsOptions{}, Result: sourcegraph.DeltaDefs{}},
+	{Route: router.DeltaDependencies, Method: "GET", ID: "Deltas.ListDependencies", Summary: "List the dependencies changed in a delta", Params: sourcegraph.DeltaListDependenciesOptions{}, Result: sourcegraph.DeltaDependencies{}},
+	{Route: router.DeltaFiles, Method: "GET", ID: "Deltas.ListFiles", Summary: "List the files changed in a delta", Params: sourcegraph.DeltaListFilesOptions{}, Result: sourcegraph.DeltaFiles{}},
+	{Route: router.DeltaAffectedAuthors, Method: "GET", ID: "Deltas.ListAffectedAuthors", Summary: "List the authors of code affected by a delta", Params: sourcegraph.DeltaListAffectedAuthorsOptions{}, Result: []*sourcegraph.DeltaAffectedPerson{}},
+	{Route: router.DeltaAffectedClients, Method: "GET", ID: "Deltas.ListAffectedClients", Summary: "List the users of code affected by a delta", Params: sourcegraph.DeltaListAffectedClientsOptions{}, Result: []*sourcegraph.DeltaAffectedPerson{}},
+	{Route: router.DeltaAffectedDependents, Method: "GET", ID: "Deltas.ListAffectedDependents", Summary: "List repositories that depend on code affected by a delta", Params: sourcegraph.DeltaListAffectedDependentsOptions{}, Result: []*sourcegraph.DeltaAffectedRepo{}},
+	{Route: router.DeltaReviewers, Method: "GET", ID: "Deltas.ListReviewers", Summary: "List suggested reviewers of a delta", Params: sourcegraph.DeltaListReviewersOptions{}, Result: []*sourcegraph.DeltaReviewer{}},
+	{Route: router.DeltasIncoming, Method: "GET", ID: "Deltas.ListIncoming", Summary: "List deltas from other repositories that affect a repository", Params: sourcegraph.DeltaListIncomingOptions{}, Result: []*sourcegraph.Delta{}},
+
+	// Units
+	{Route: router.Units, Method: "GET", ID: "Units.List", Summary: "List source units", Params: sourcegraph.UnitListOptions{}, Result: []*unit.RepoSourceUnit{}},
+	{Route: router.Unit, Method: "GET", ID: "Units.Get", Summary: "Get a source unit", Result: unit.RepoSourceUnit{}},
+
+	// Markdown
+	{Route: router.Markdown, Method: "POST", ID: "Markdown.Render", Summary: "Render Markdown", Body: sourcegraph.MarkdownRequestBody{}, Result: sourcegraph.MarkdownData{}},
+
+	// External services
+	{Route: router.ExtGitHubReceiveWebhook, Method: "POST", ID: "Ext.ReceiveGitHubWebhook", Summary: "Receive a GitHub webhook event", Body: json.RawMessage{}},
+}
@@ -0,0 +1,658 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// Generate generates an OpenAPI document describing the operations in
+// Endpoints, using r (which is usually router.NewAPIRouter(nil)) to
+// determine each operation's path.
+func Generate(r *mux.Router) (*Document, error) {
+	g := &generator{
+		doc: &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:       "Sourcegraph API",
+				Description: "The Sourcegraph API. Operation IDs are the names of the corresponding methods of the Go API client (package sourcegraph).",
+				Version:     "1",
+			},
+			Servers: []Server{{URL: strings.TrimSuffix(sourcegraph.NewClient(nil).BaseURL.String(), "/")}},
+			Paths:   map[string]*PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{"Error": errorSchema},
+			},
+		},
+		names: map[reflect.Type]string{},
+	}
+	for _, e := range Endpoints {
+		if err := g.addEndpoint(r, e); err != nil {
+			return nil, fmt.Errorf("route %q (%s %s): %s", e.Route, e.Method, e.ID, err)
+		}
+	}
+	return g.doc, nil
+}
+
+type generator struct {
+	doc   *Document
+	names map[reflect.Type]string // component schema names of named struct types
+}
+
+func (g *generator) addEndpoint(r *mux.Router, e Endpoint) error {
+	rt := r.Get(e.Route)
+	if rt == nil {
+		return fmt.Errorf("no such route")
+	}
+	tmpl, names, err := pathTemplate(rt)
+	if err != nil {
+		return err
+	}
+
+	op := &Operation{
+		OperationID: e.ID,
+		Summary:     e.Summary,
+		Deprecated:  e.Deprecated,
+		Responses:   map[string]*Response{},
+		XRouteName:  e.Route,
+	}
+	if i := strings.Index(e.ID, "."); i != -1 {
+		op.Tags = []string{e.ID[:i]}
+	}
+
+	for _, name := range names {
+		p := pathParams[name]
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:        name,
+			In:          "path",
+			Description: p.description,
+			Required:    true,
+			Schema:      p.schema(),
+		})
+	}
+	if e.Params != nil {
+		t := reflect.TypeOf(e.Params)
+		if indirect(t).Kind() != reflect.Struct {
+			return fmt.Errorf("Params type %s is not a struct", t)
+		}
+		g.addQueryParams(&op.Parameters, indirect(t), "")
+	}
+
+	if e.Body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: g.content(reflect.TypeOf(e.Body))}
+	}
+
+	resp := &Response{Description: "OK"}
+	if e.Result != nil {
+		resp.Content = g.content(reflect.TypeOf(e.Result))
+	}
+	if e.Params != nil && hasListOptions(reflect.TypeOf(e.Params)) {
+		resp.Headers = map[string]*Header{
+			"X-Total-Count": {
+				Description: "The total number of results (across all pages), if known.",
+				Schema:      &Schema{Type: "integer"},
+			},
+		}
+	}
+	op.Responses["200"] = resp
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+
+	item := g.doc.Paths[tmpl]
+	if item == nil {
+		item = &PathItem{}
+		g.doc.Paths[tmpl] = item
+	}
+	method := strings.ToLower(e.Method)
+	if _, dup := (*item)[method]; dup {
+		return fmt.Errorf("duplicate operation for %s %s", e.Method, tmpl)
+	}
+	(*item)[method] = op
+	return nil
+}
+
+// A pathParam describes a route variable.
+type pathParam struct {
+	// sample is a unique value for the route variable that matches
+	// its pattern. It is substituted into the route's URL and then
+	// replaced with "{Name}" to produce the path template.
+	sample string
+
+	// alt is another value that matches the route variable's
+	// pattern and differs from sample in its first character. If
+	// empty, it is derived from sample.
+	alt string
+
+	description string
+
+	typ     string // "string" (default) or "integer"
+	pattern string // regexp that matches valid values (if any)
+}
+
+func (p pathParam) altSample() string {
+	if p.alt != "" {
+		return p.alt
+	}
+	return "-" + p.sample[1:]
+}
+
+func (p pathParam) schema() *Schema {
+	s := &Schema{Type: p.typ, Pattern: p.pattern}
+	if s.Type == "" {
+		s.Type = "string"
+	}
+	if s.Pattern != "" {
+		s.Pattern = "^(?:" + s.Pattern + ")$"
+	}
+	return s
+}
+
+// pathParams describes all of the route variables used by routes in
+// package router.
+var pathParams = map[string]pathParam{
+	"RepoSpec": {
+		sample:      "_RepoSpec_/_RepoSpec_",
+		description: `A repository URI (such as "github.com/alice/myrepo") or "R$" followed by a repository ID (such as "R$123").`,
+		pattern:     varPattern(router.RepoSpecPathPattern, "RepoSpec"),
+	},
+	"Rev": {
+		sample:      "_Rev_",
+		description: `A revision specifier (such as a branch name or commit ID), optionally followed by "===" and the full commit ID that it resolves to. In paths of the form "{RepoSpec}@{Rev}", "@{Rev}" may be omitted to use the repository's default branch.`,
+		pattern:     router.PathComponentNoLeadingDot,
+	},
+	"DeltaHeadRev": {
+		sample:      "_DeltaHeadRev_",
+		description: "The revision specifier of the head of the delta (the base is Rev).",
+		pattern:     router.PathComponentNoLeadingDot,
+	},
+	"Path": {
+		sample:      "_Path_",
+		description: `A file path (relative to the repository root) or def path. The root path (".") is written as an empty path, along with its leading "/".`,
+	},
+	"UnitType": {
+		sample:      "_UnitType_",
+		description: `The source unit type (such as "GoPackage").`,
+	},
+	"Unit": {
+		sample:      "_Unit_",
+		description: `The source unit name. The unit "." is written as an empty unit, along with its trailing "/".`,
+	},
+	"BID":            {sample: "_BID_", description: "The build ID.", typ: "integer"},
+	"TaskID":         {sample: "_TaskID_", description: "The build task ID.", typ: "integer"},
+	"Pull":           {sample: "_Pull_", description: "The pull request number.", typ: "integer"},
+	"Issue":          {sample: "_Issue_", description: "The issue number.", typ: "integer"},
+	"CommentID":      {sample: "_CommentID_", description: "The comment ID.", typ: "integer"},
+	"Badge":          {sample: "_Badge_", description: "The badge name."},
+	"Counter":        {sample: "_Counter_", description: "The counter name."},
+	"Format":         {sample: "_Format_", description: `The image format (such as "png" or "svg").`},
+	"OrgSpec":        {sample: "_OrgSpec_", description: `An organization's name or "$" followed by its user ID.`},
+	"GitHubUserSpec": {sample: "_GitHubUserSpec_", description: "The GitHub user's login."},
+	"UserSpec": {
+		sample:      "_UserSpec_",
+		description: `A user's login or "$" followed by the user's ID (such as "$123").`,
+		pattern:     varPattern(router.UserSpecPattern, "UserSpec"),
+	},
+	"PersonSpec": {
+		sample:      "_PersonSpec_",
+		description: `A person's email address, login, or "$" followed by the person's user ID.`,
+		pattern:     varPattern(router.PersonSpecPattern, "PersonSpec"),
+	},
+	"owner": {sample: "_owner_", description: "The GitHub repository owner."},
+	"repo":  {sample: "_repo_", description: "The GitHub repository name."},
+	"what":  {sample: "badges", alt: "counters", description: `Either "badges" or "counters".`},
+	"which": {sample: "_which_", description: "The badge or counter name."},
+}
+
+// pathTemplate returns the OpenAPI path template of rt (such as
+// "/repos/{RepoSpec}/.commits") and the names of the route variables
+// that appear in it, in order.
+//
+// Because routes may transform their variables when building URLs
+// (e.g., with PrepareRepoRevSpecRouteVars), the template is derived
+// by building a URL with sample variable values and then replacing
+// the samples with placeholders. A variable is used by the route if
+// changing its sample value changes the URL.
+func pathTemplate(rt *mux.Route) (string, []string, error) {
+	build := func(altName string) (string, error) {
+		pairs := make([]string, 0, 2*len(pathParams))
+		for name, p := range pathParams {
+			v := p.sample
+			if name == altName {
+				v = p.altSample()
+			}
+			pairs = append(pairs, name, v)
+		}
+		u, err := rt.URL(pairs...)
+		if err != nil {
+			return "", fmt.Errorf("building URL (does the route have a variable that isn't listed in pathParams?): %s", err)
+		}
+		return u.Path, nil
+	}
+
+	tmpl, err := build("")
+	if err != nil {
+		return "", nil, err
+	}
+
+	type occurrence struct {
+		name string
+		pos  int
+	}
+	var occs []occurrence
+	for name := range pathParams {
+		alt, err := build(name)
+		if err != nil {
+			return "", nil, err
+		}
+		if alt == tmpl {
+			continue // route doesn't use this variable
+		}
+		pos := 0
+		for pos < len(tmpl) && pos < len(alt) && tmpl[pos] == alt[pos] {
+			pos++
+		}
+		if !strings.HasPrefix(tmpl[pos:], pathParams[name].sample) {
+			return "", nil, fmt.Errorf("can't locate variable %q in URL %q", name, tmpl)
+		}
+		occs = append(occs, occurrence{name, pos})
+	}
+	sort.Slice(occs, func(i, j int) bool { return occs[i].pos < occs[j].pos })
+
+	// Replace from the end so that earlier positions remain valid.
+	names := make([]string, len(occs))
+	for i := len(occs) - 1; i >= 0; i-- {
+		o := occs[i]
+		names[i] = o.name
+		tmpl = tmpl[:o.pos] + "{" + o.name + "}" + tmpl[o.pos+len(pathParams[o.name].sample):]
+	}
+	return tmpl, names, nil
+}
+
+// varPattern returns the regexp of the named variable in a mux route
+// pattern (e.g., `[^@/]+` for "UserSpec" in `{UserSpec:[^@/]+}`).
+func varPattern(routePattern, name string) string {
+	start := strings.Index(routePattern, "{"+name+":")
+	if start == -1 {
+		return ""
+	}
+	start += len(name) + 2
+	depth := 1
+	for i := start; i < len(routePattern); i++ {
+		switch routePattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return routePattern[start:i]
+			}
+		}
+	}
+	return ""
+}
+
+var (
+	timeType             = reflect.TypeOf(time.Time{})
+	listOptionsType      = reflect.TypeOf(sourcegraph.ListOptions{})
+	queryEncoderType     = reflect.TypeOf((*query.Encoder)(nil)).Elem()
+	jsonMarshalerType    = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	binaryType           = reflect.TypeOf(binary{})
+	invalidSchemaNameRxp = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// binary is used as an Endpoint's Body or Result to indicate raw
+// (non-JSON) data.
+type binary struct{}
+
+// tokenSchema is the schema of a sourcegraph.Token, as marshaled by
+// sourcegraph.Tokens and sourcegraph.TokenError.
+var tokenSchema = &Schema{
+	Type:        "object",
+	Description: `A query token. Its Type property is the token type (such as "RepoToken"), and its other properties depend on the type.`,
+	Properties:  map[string]*Schema{"Type": {Type: "string"}},
+}
+
+var errorSchema = &Schema{
+	Type:       "object",
+	Properties: map[string]*Schema{"Message": {Type: "string"}},
+}
+
+// customSchemas are the schemas of types that implement
+// json.Marshaler.
+var customSchemas = map[reflect.Type]*Schema{
+	timeType:                             {Type: "string", Format: "date-time"},
+	reflect.TypeOf(json.RawMessage{}):    {},
+	reflect.TypeOf(db_common.NullTime{}): {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(db_common.NullInt{}):  {Type: "integer", Nullable: true},
+	reflect.TypeOf(sourcegraph.Tokens{}): {Type: "array", Items: tokenSchema},
+	reflect.TypeOf(sourcegraph.TokenError{}): {
+		Type: "object",
+		Properties: map[string]*Schema{
+			"Index":   {Type: "integer"},
+			"Token":   tokenSchema,
+			"Message": {Type: "string"},
+		},
+	},
+}
+
+// content returns the request or response body content of type t.
+func (g *generator) content(t reflect.Type) map[string]*MediaType {
+	if t == binaryType {
+		return map[string]*MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}}
+	}
+	return map[string]*MediaType{"application/json": {Schema: g.schema(t)}}
+}
+
+// schema returns the schema of the JSON encoding of type t. Named
+// struct types are added to the document's components and referred
+// to by reference.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if s, ok := customSchemas[t]; ok {
+		s2 := *s
+		return &s2
+	}
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+			return &Schema{Description: fmt.Sprintf("A JSON-encoded %s.", t)}
+		}
+		if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// int and uint are 64 bits wide on the platforms that the API
+		// runs on.
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t)}
+	}
+	// Interfaces (and other kinds, which aren't JSON-encodable) may
+	// hold any value.
+	return &Schema{}
+}
+
+// component returns the name of the component schema for the named
+// struct type t, adding it to the document if necessary.
+func (g *generator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	base := t.Name()
+	if pkg := path.Base(t.PkgPath()); pkg != "sourcegraph" {
+		base = pkg + "." + base
+	}
+	base = invalidSchemaNameRxp.ReplaceAllString(base, "_")
+	name := base
+	for i := 2; g.doc.Components.Schemas[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	// Reserve the name before generating the schema, in case the
+	// type refers to itself.
+	g.names[t] = name
+	g.doc.Components.Schemas[name] = &Schema{}
+	g.doc.Components.Schemas[name] = g.structSchema(t)
+	return name
+}
+
+// structSchema returns the schema of the JSON encoding of the struct
+// type t.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range jsonFields(t) {
+		if f.asString {
+			s.Properties[f.name] = &Schema{Type: "string"}
+		} else {
+			s.Properties[f.name] = g.schema(f.typ)
+		}
+	}
+	return s
+}
+
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	asString bool // whether the field has the ",string" json tag option
+	depth    int  // depth of embedding
+	tagged   bool // whether the field's name is given by its json tag
+}
+
+// jsonFields returns the fields of struct type t that are JSON-encoded,
+// following encoding/json's rules for field names and embedded
+// structs. When multiple fields have the same name, the least nested
+// one is used; if there are several at that depth, the one with a json
+// tag name is used, and if that doesn't settle it, none of them are.
+func jsonFields(t reflect.Type) []jsonField {
+	byName := map[string][]jsonField{}
+	var names []string
+	var walk func(t reflect.Type, depth int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, depth int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts := parseTag(tag)
+			ft := indirect(sf.Type)
+			if sf.Anonymous {
+				if name == "" && ft.Kind() == reflect.Struct {
+					walk(ft, depth+1, visited)
+					continue
+				}
+				if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+					continue
+				}
+			} else if sf.PkgPath != "" {
+				continue
+			}
+			tagged := name != ""
+			if !tagged {
+				name = sf.Name
+			}
+			if _, ok := byName[name]; !ok {
+				names = append(names, name)
+			}
+			byName[name] = append(byName[name], jsonField{name: name, typ: sf.Type, asString: opts.has("string"), depth: depth, tagged: tagged})
+		}
+	}
+	walk(t, 0, map[reflect.Type]bool{})
+
+	var fields []jsonField
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// dominantField returns the field (of those with the same name) that
+// encoding/json encodes, or false if the fields conflict and none of
+// them is encoded.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < depth {
+			depth = f.depth
+		}
+	}
+	var dominant, tagged []jsonField
+	for _, f := range fields {
+		if f.depth == depth {
+			dominant = append(dominant, f)
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	switch {
+	case len(dominant) == 1:
+		return dominant[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// addQueryParams appends the querystring parameters of the options
+// struct type t to params, following the encoding rules of
+// github.com/google/go-querystring/query (which the API client uses
+// to encode options).
+func (g *generator) addQueryParams(params *[]*Parameter, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag := sf.Tag.Get("url")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		ft := indirect(sf.Type)
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addQueryParams(params, ft, prefix)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if prefix != "" {
+			name = prefix + "[" + name + "]"
+		}
+
+		encodes := ft.Implements(queryEncoderType) || reflect.PtrTo(ft).Implements(queryEncoderType)
+		if ft.Kind() == reflect.Struct && ft != timeType && !encodes {
+			g.addQueryParams(params, ft, name)
+			continue
+		}
+
+		p := &Parameter{Name: name, In: "query", Schema: querySchema(ft)}
+		if encodes {
+			p.Schema = &Schema{Type: "string"}
+		}
+		if p.Schema.Type == "array" {
+			switch {
+			case opts.has("comma"):
+				no := false
+				p.Style, p.Explode = "form", &no
+			case opts.has("space"):
+				no := false
+				p.Style, p.Explode = "spaceDelimited", &no
+			}
+		}
+		*params = append(*params, p)
+	}
+}
+
+// querySchema returns the schema of a querystring parameter value of
+// type t.
+func querySchema(t reflect.Type) *Schema {
+	t = indirect(t)
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		return &Schema{Type: "array", Items: querySchema(t.Elem())}
+	}
+	return &Schema{Type: "string"}
+}
+
+// hasListOptions returns whether the options struct type t embeds
+// sourcegraph.ListOptions (and therefore is for a paginated list
+// operation).
+func hasListOptions(t reflect.Type) bool {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && (indirect(sf.Type) == listOptionsType || hasListOptions(sf.Type)) {
+			return true
+		}
+	}
+	return false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+type tagOptions []string
+
+func (o tagOptions) has(opt string) bool {
+	for _, s := range o {
+		if s == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag splits a url or json struct tag into its name and
+// options.
+func parseTag(tag string) (string, tagOptions) {
+	s := strings.Split(tag, ",")
+	return s[0], s[1:]
+}
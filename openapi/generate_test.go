@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// routeNames returns the names of all of the routes defined in
+// package router, by parsing its route name constants.
+func routeNames(t *testing.T) []string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "../router/api_router.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			for _, v := range spec.(*ast.ValueSpec).Values {
+				if lit, ok := v.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					name, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatal(err)
+					}
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		t.Fatal("found no route names")
+	}
+	return names
+}
+
+func TestGenerate_allRoutesDocumented(t *testing.T) {
+	r := router.NewAPIRouter(nil)
+	doc, err := Generate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[string]bool{}
+	for _, item := range doc.Paths {
+		for _, op := range *item {
+			documented[op.XRouteName] = true
+		}
+	}
+	for _, name := range routeNames(t) {
+		if r.Get(name) == nil {
+			t.Errorf("route %q is not in the router", name)
+		}
+		if !documented[name] {
+			t.Errorf("route %q has no documented operation (add it to Endpoints)", name)
+		}
+	}
+}
+
+// TestGenerate_matchesRouter checks that each documented operation's
+// path and method are matched by the router to the operation's route.
+func TestGenerate_matchesRouter(t *testing.T) {
+	r := router.NewAPIRouter(nil)
+	doc, err := Generate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]bool{}
+	for tmpl, item := range doc.Paths {
+		path := tmpl
+		for name, p := range pathParams {
+			path = strings.Replace(path, "{"+name+"}", p.sample, -1)
+		}
+		for method, op := range *item {
+			if ids[op.OperationID] {
+				t.Errorf("duplicate operation ID %q", op.OperationID)
+			}
+			ids[op.OperationID] = true
+
+			req, _ := http.NewRequest(strings.ToUpper(method), "http://example.com"+path, nil)
+			var m mux.RouteMatch
+			if !r.Match(req, &m) {
+				t.Errorf("%s %s (%s): no route matched", method, tmpl, op.OperationID)
+				continue
+			}
+			if got := m.Route.GetName(); got != op.XRouteName {
+				t.Errorf("%s %s (%s): matched route %q, want %q", method, tmpl, op.OperationID, got, op.XRouteName)
+			}
+		}
+	}
+}
+
+func TestGenerate_paths(t *testing.T) {
+	doc, err := Generate(router.NewAPIRouter(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		method     string
+		id         string
+		pathParams []string
+	}{
+		"/repos/{RepoSpec}":                                            {"get", "Repos.Get", []string{"RepoSpec"}},
+		"/repos/{RepoSpec}/.commits/{Rev}":                             {"get", "Repos.GetCommit", []string{"RepoSpec", "Rev"}},
+		"/repos/{RepoSpec}@{Rev}/.authors":                             {"get", "Repos.ListAuthors", []string{"RepoSpec", "Rev"}},
+		"/repos/{RepoSpec}@{Rev}/.tree/{Path}":                         {"get", "RepoTree.Get", []string{"RepoSpec", "Rev", "Path"}},
+		"/repos/{RepoSpec}@{Rev}/.defs/.{UnitType}/{Unit}/.def/{Path}": {"get", "Defs.Get", []string{"RepoSpec", "Rev", "UnitType", "Unit", "Path"}},
+		"/repos/{RepoSpec}/.deltas/{Rev}..{DeltaHeadRev}":              {"get", "Deltas.Get", []string{"RepoSpec", "Rev", "DeltaHeadRev"}},
+		"/builds/{BID}/tasks/{TaskID}/log":                             {"get", "Builds.GetTaskLog", []string{"BID", "TaskID"}},
+		"/repos/github.com/{owner}/{repo}/{what}/{which}.{Format}":     {"get", "Repos.RedirectOldBadgesAndCounters", []string{"owner", "repo", "what", "which", "Format"}},
+		"/markdown": {"post", "Markdown.Render", nil},
+	}
+	for tmpl, test := range tests {
+		item := doc.Paths[tmpl]
+		if item == nil {
+			t.Errorf("%s: no such path", tmpl)
+			continue
+		}
+		op := (*item)[test.method]
+		if op == nil {
+			t.Errorf("%s: no %s operation", tmpl, test.method)
+			continue
+		}
+		if op.OperationID != test.id {
+			t.Errorf("%s: got operation ID %q, want %q", tmpl, op.OperationID, test.id)
+		}
+		var params []string
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				params = append(params, p.Name)
+			}
+		}
+		if !reflect.DeepEqual(params, test.pathParams) {
+			t.Errorf("%s: got path params %v, want %v", tmpl, params, test.pathParams)
+		}
+	}
+}
+
+type testNested struct {
+	X int
+}
+
+type testPage struct {
+	PerPage int `url:",omitempty"`
+}
+
+type testOptions struct {
+	Query   string    `url:"q"`
+	Skip    string    `url:"-"`
+	Langs   []string  `url:",comma,omitempty"`
+	Kinds   []string  `url:",omitempty"`
+	Since   time.Time `url:",omitempty"`
+	Nested  testNested
+	Enabled bool
+	testPage
+	unexported int
+}
+
+func TestAddQueryParams(t *testing.T) {
+	var g generator
+	var params []*Parameter
+	g.addQueryParams(&params, reflect.TypeOf(testOptions{}), "")
+
+	no := false
+	want := []*Parameter{
+		{Name: "q", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "Langs", In: "query", Style: "form", Explode: &no, Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{Name: "Kinds", In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{Name: "Since", In: "query", Schema: &Schema{Type: "string", Format: "date-time"}},
+		{Name: "Nested[X]", In: "query", Schema: &Schema{Type: "integer"}},
+		{Name: "Enabled", In: "query", Schema: &Schema{Type: "boolean"}},
+		{Name: "PerPage", In: "query", Schema: &Schema{Type: "integer"}},
+	}
+	if !reflect.DeepEqual(params, want) {
+		got, _ := json.Marshal(params)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("got params\n%s\nwant\n%s", got, wantJSON)
+	}
+}
+
+type testEmbedded struct {
+	A string
+	B string
+}
+
+type testStruct struct {
+	testEmbedded
+	B        int    `json:"b"`
+	Renamed  string `json:"r,omitempty"`
+	Skipped  string `json:"-"`
+	Num      int64  `json:",string"`
+	Small    int16
+	Data     []byte
+	Children []*testStruct
+	Map      map[string]float64
+	Any      interface{}
+	Raw      json.RawMessage
+	When     time.Time
+	private  int
+}
+
+type testConflictA struct {
+	X      string
+	Y      string `json:"Y"`
+	Z      string
+	Shared testShared
+	testShared
+}
+
+type testConflictB struct {
+	X string
+	Y string
+	testShared
+}
+
+type testShared struct {
+	S string
+}
+
+type testConflict struct {
+	testConflictA
+	*testConflictB
+	Z int
+}
+
+func TestJSONFields(t *testing.T) {
+	// The fields must be the same as the keys that encoding/json
+	// encodes.
+	v := testConflict{testConflictB: &testConflictB{}}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for name := range m {
+		want = append(want, name)
+	}
+	sort.Strings(want)
+
+	var got []string
+	for _, f := range jsonFields(reflect.TypeOf(v)) {
+		got = append(got, f.name)
+	}
+	sort.Strings(got)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got fields %q, want %q", got, want)
+	}
+	if wantNames := []string{"Shared", "Y", "Z"}; !reflect.DeepEqual(got, wantNames) {
+		t.Errorf("got fields %q, want %q", got, wantNames)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	g := &generator{
+		doc:   &Document{Components: Components{Schemas: map[string]*Schema{}}},
+		names: map[reflect.Type]string{},
+	}
+	s := g.schema(reflect.TypeOf([]*testStruct{}))
+
+	want := &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/openapi.testStruct"}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got schema %+v, want %+v", s, want)
+	}
+
+	wantComponent := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"A":        {Type: "string"},
+			"B":        {Type: "string"},
+			"b":        {Type: "integer", Format: "int64"},
+			"r":        {Type: "string"},
+			"Num":      {Type: "string"},
+			"Small":    {Type: "integer", Format: "int32"},
+			"Data":     {Type: "string", Format: "byte"},
+			"Children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/openapi.testStruct"}},
+			"Map":      {Type: "object", AdditionalProperties: &Schema{Type: "number", Format: "double"}},
+			"Any":      {},
+			"Raw":      {},
+			"When":     {Type: "string", Format: "date-time"},
+		},
+	}
+	if got := g.doc.Components.Schemas["openapi.testStruct"]; !reflect.DeepEqual(got, wantComponent) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(wantComponent)
+		t.Errorf("got component\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+	if len(g.doc.Components.Schemas) != 1 {
+		t.Errorf("got %d components, want 1 (embedded structs should be flattened)", len(g.doc.Components.Schemas))
+	}
+}
@@ -0,0 +1,118 @@
+// Package openapi generates an OpenAPI 3 document that describes the
+// Sourcegraph API.
+//
+// The document is generated from the API router (package router),
+// which defines the path pattern of each route, and from the
+// Endpoints table, which associates each route with the Go types (in
+// package sourcegraph) of its querystring options, request body, and
+// response body. Schemas for those types are derived from their
+// struct fields and url and json tags, following the same rules that
+// the API client uses to encode requests and decode responses.
+//
+// To write the document:
+//
+//	doc, err := openapi.Generate(router.NewAPIRouter(nil))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	data, err := json.MarshalIndent(doc, "", "  ")
+package openapi
+
+// Version is the version of the OpenAPI specification that generated
+// documents conform to.
+const Version = "3.0.3"
+
+// A Document is an OpenAPI document (the root object).
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// A Server is a base URL of the API.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// A PathItem describes the operations available on a single path,
+// keyed by lowercase HTTP method (e.g., "get").
+type PathItem map[string]*Operation
+
+// An Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+
+	// XRouteName is the name of the route (in package router) that
+	// this operation is served by.
+	XRouteName string `json:"x-route-name"`
+}
+
+// A Parameter describes a single path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// A RequestBody describes a request body.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// A Response describes a single response from an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// A Header describes a response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// A MediaType describes the schema of a request or response body of
+// a given content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas, keyed by name.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// A Schema is a (subset of an) OpenAPI schema object.
+type Schema struct {
+	Ref string `json:"$ref,omitempty"`
+
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
@@ -66,6 +66,7 @@ const (
 	RepoBuildsCreate   = "repo.builds.create"
 	RepoBuildDataEntry = "repo.build-data.entry"
 	RepoTreeEntry      = "repo.tree.entry"
+	RepoTreeSourcebox  = "repo.tree.sourcebox"
 	RepoRefreshProfile = "repo.refresh-profile"
 	RepoRefreshVCSData = "repo.refresh-vcs-data"
 	RepoComputeStats   = "repo.compute-stats"
@@ -100,6 +101,7 @@ const (
 	DefClients    = "def.clients"
 	DefDependents = "def.dependents"
 	DefVersions   = "def.versions"
+	DefSourcebox  = "def.sourcebox"
 
 	Delta                   = "delta"
 	DeltaUnits              = "delta.units"
@@ -221,8 +223,11 @@ func NewAPIRouter(base *mux.Router) *mux.Router {
 	repo.Path("/.deltas-incoming").Methods("GET").Name(DeltasIncoming)
 
 	// See router_util/tree_route.go for an explanation of how we match tree
-	// entry routes.
+	// entry routes. The sourcebox route has its own prefix, because any
+	// suffix after the (unrestricted) tree entry path could also be
+	// the name of a tree entry.
 	repoRev.Path("/.tree" + TreeEntryPathPattern).PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Methods("GET").Name(RepoTreeEntry)
+	repoRev.Path("/.sourcebox" + TreeEntryPathPattern).PostMatchFunc(FixTreeEntryVars).BuildVarsFunc(PrepareTreeEntryRouteVars).Methods("GET").Name(RepoTreeSourcebox)
 
 	base.Path(`/people/` + PersonSpecPattern).Methods("GET").Name(Person)
 
@@ -269,6 +274,7 @@ func NewAPIRouter(base *mux.Router) *mux.Router {
 	def.Path("/.clients").Methods("GET").Name(DefClients)
 	def.Path("/.dependents").Methods("GET").Name(DefDependents)
 	def.Path("/.versions").Methods("GET").Name(DefVersions)
+	def.Path("/.sourcebox.json").Methods("GET").Name(DefSourcebox)
 
 	base.Path("/.units").Methods("GET").Name(Units)
 	unitPath := `/.units/{UnitType}/{Unit:.*}`
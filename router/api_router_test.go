@@ -144,6 +144,16 @@ func TestMatch(t *testing.T) {
 			wantRouteName: RepoTreeEntry,
 			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "Rev": "myrev/subrev", "Path": "my/file"},
 		},
+		{
+			path:          "/repos/repohost.com/foo@myrev/.sourcebox/my/file",
+			wantRouteName: RepoTreeSourcebox,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "Rev": "myrev", "Path": "my/file"},
+		},
+		{
+			path:          "/repos/repohost.com/foo@myrev/.tree/dir/.sourcebox.json",
+			wantRouteName: RepoTreeEntry,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "Rev": "myrev", "Path": "dir/.sourcebox.json"},
+		},
 
 		// Units
 		{
@@ -206,6 +216,31 @@ func TestMatch(t *testing.T) {
 			wantRouteName: Def,
 			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": "u1/u2", "Path": "p1/p2"},
 		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/u1/.def/a.def/b", // the first .def ends the unit
+			wantRouteName: Def,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": "u1", "Path": "a.def/b"},
+		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/.def/a%3Fb",
+			wantRouteName: Def,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": ".", "Path": "a?b"},
+		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/.def/a%253Fb", // literal "%3F"
+			wantRouteName: Def,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": ".", "Path": "a%3Fb"},
+		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/.def/a%2525b", // literal "%25"
+			wantRouteName: Def,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": ".", "Path": "a%25b"},
+		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/.def/100%/a%b", // other '%'s aren't escaped
+			wantRouteName: Def,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": ".", "Path": "100%/a%b"},
+		},
 
 		// Def sub-routes
 		{
@@ -223,6 +258,16 @@ func TestMatch(t *testing.T) {
 			wantRouteName: DefAuthors,
 			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": "u1/u2", "Path": "p1/p2"},
 		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/.def/a.def/b/.authors",
+			wantRouteName: DefAuthors,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": ".", "Path": "a.def/b"},
+		},
+		{
+			path:          "/repos/repohost.com/foo/.defs/.t/u1/.def/p1/p2/.sourcebox.json",
+			wantRouteName: DefSourcebox,
+			wantVars:      map[string]string{"RepoSpec": "repohost.com/foo", "UnitType": "t", "Unit": "u1", "Path": "p1/p2"},
+		},
 
 		// Deltas
 		{
@@ -11,12 +11,15 @@ import (
 //
 // We want the def routes to match the 2 following forms:
 //
-//   1. /.MyUnitType/.def/MyDef (i.e., Unit == ".")
-//   2. /.MyUnitType/MyUnitPath1/.def/MyDef (i.e., Unit == "MyUnitPath1")
+//  1. /.MyUnitType/.def/MyDef (i.e., Unit == ".")
+//  2. /.MyUnitType/MyUnitPath1/.def/MyDef (i.e., Unit == "MyUnitPath1")
 //
 // To achieve this, we use a non-picky regexp for rawUnit and then sort it
-// out in the FixDefUnitVars PostMatchFunc.
-var DefPathPattern = `.{UnitType}/{rawUnit:.*}.def{Path:(?:(?:/(?:[^/.][^/]*/)*(?:[^/.][^/]*))|)}`
+// out in the FixDefUnitVars PostMatchFunc. The rawUnit regexp is
+// non-greedy, so the first ".def" path component ends the unit. (If it
+// were greedy, a def path such as "a.def/b" would be split at the
+// last ".def" by the unanchored regexps of the def subroutes.)
+var DefPathPattern = `.{UnitType}/{rawUnit:(?:[^/]*/)*?}.def{Path:(?:(?:/(?:[^/.][^/]*/)*(?:[^/.][^/]*))|)}`
 
 // FixDefUnitVars is a mux.PostMatchFunc that cleans up the dummy rawUnit route
 // variable matched by DefPathPattern. See the docs for DefPathPattern for
@@ -59,12 +62,38 @@ func PrepareDefRouteVars(vars map[string]string) map[string]string {
 	return vars
 }
 
-// pathEscape is a limited version of url.QueryEscape that only escapes '?'.
+// pathUnescaper unescapes the escape sequences that pathEscape
+// produces.
+var pathUnescaper = strings.NewReplacer("%25", "%", "%3F", "?")
+
+// pathEscape is a limited version of url.QueryEscape that only escapes
+// '?', and '%' where it begins one of the escape sequences "%3F" and
+// "%25" (so that a literal "%3F" in a path is not unescaped to '?').
+//
+// Other '%' characters are left alone, so a path is encoded exactly
+// as it was before '%' was escaped unless it contains "%3F" or "%25".
+// (Paths containing "%3F" were unescaped incorrectly before, anyway.)
+// This keeps older clients and servers compatible for all other paths.
 func pathEscape(p string) string {
-	return strings.Replace(p, "?", "%3F", -1)
+	if !strings.ContainsAny(p, "?%") {
+		return p
+	}
+	var buf strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch {
+		case p[i] == '?':
+			buf.WriteString("%3F")
+		case strings.HasPrefix(p[i:], "%3F"), strings.HasPrefix(p[i:], "%25"):
+			buf.WriteString("%25")
+		default:
+			buf.WriteByte(p[i])
+		}
+	}
+	return buf.String()
 }
 
-// pathUnescape is a limited version of url.QueryEscape that only unescapes '?'.
+// pathUnescape is a limited version of url.QueryUnescape that only
+// unescapes the escape sequences that pathEscape produces.
 func pathUnescape(p string) string {
-	return strings.Replace(p, "%3F", "?", -1)
+	return pathUnescaper.Replace(p)
 }
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type builds struct{ s sourcegraph.BuildsService }
+
+func (h builds) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Builds:           h.serveBuilds,
+		router.Build:            h.serveBuild,
+		router.BuildUpdate:      h.serveBuildUpdate,
+		router.BuildDequeueNext: h.serveBuildDequeueNext,
+		router.RepoBuildsCreate: h.serveRepoBuildsCreate,
+		router.BuildTasks:       h.serveBuildTasks,
+		router.BuildTasksCreate: h.serveBuildTasksCreate,
+		router.BuildTaskUpdate:  h.serveBuildTaskUpdate,
+		router.BuildLog:         h.serveBuildLog,
+		router.BuildTaskLog:     h.serveBuildTaskLog,
+	}
+}
+
+func (h builds) serveBuilds(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.BuildListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	builds, resp, err := h.s.List(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, builds)
+}
+
+func (h builds) serveBuild(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	build, resp, err := h.s.Get(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, build)
+}
+
+func (h builds) serveBuildUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var info sourcegraph.BuildUpdate
+	if err := httpapi.ReadJSON(r, &info); err != nil {
+		return err
+	}
+	build, resp, err := h.s.Update(r.Context(), spec, info)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, build)
+}
+
+// serveBuildDequeueNext responds with HTTP 404 if there are no queued
+// builds, which the client reports as a nil build (and no error).
+func (h builds) serveBuildDequeueNext(w http.ResponseWriter, r *http.Request) error {
+	build, resp, err := h.s.DequeueNext(r.Context())
+	if err != nil {
+		return err
+	}
+	if build == nil {
+		return httpapi.ErrNotFound
+	}
+	return writeResult(w, resp, build)
+}
+
+func (h builds) serveRepoBuildsCreate(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildCreateOptions
+	if err := httpapi.ReadJSON(r, &opt); err != nil {
+		return err
+	}
+	build, resp, err := h.s.Create(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, build)
+}
+
+func (h builds) serveBuildTasks(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildTaskListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	tasks, resp, err := h.s.ListBuildTasks(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, tasks)
+}
+
+func (h builds) serveBuildTasksCreate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var tasks []*sourcegraph.BuildTask
+	if err := httpapi.ReadJSON(r, &tasks); err != nil {
+		return err
+	}
+	created, resp, err := h.s.CreateTasks(r.Context(), spec, tasks)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, created)
+}
+
+func (h builds) serveBuildTaskUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.TaskSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var info sourcegraph.TaskUpdate
+	if err := httpapi.ReadJSON(r, &info); err != nil {
+		return err
+	}
+	task, resp, err := h.s.UpdateTask(r.Context(), spec, info)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, task)
+}
+
+func (h builds) serveBuildLog(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildGetLogOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	entries, resp, err := h.s.GetLog(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, entries)
+}
+
+func (h builds) serveBuildTaskLog(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.TaskSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildGetLogOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	entries, resp, err := h.s.GetTaskLog(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, entries)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type defs struct{ s sourcegraph.DefsService }
+
+func (h defs) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Defs:          h.serveDefs,
+		router.Def:           h.serveDef,
+		router.DefRefs:       h.serveDefRefs,
+		router.DefExamples:   h.serveDefExamples,
+		router.DefAuthors:    h.serveDefAuthors,
+		router.DefClients:    h.serveDefClients,
+		router.DefDependents: h.serveDefDependents,
+		router.DefVersions:   h.serveDefVersions,
+	}
+}
+
+func (h defs) serveDefs(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.DefListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	defs, resp, err := h.s.List(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, defs)
+}
+
+func (h defs) serveDef(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	d, resp, err := h.s.Get(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, d)
+}
+
+func (h defs) serveDefRefs(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListRefsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	refs, resp, err := h.s.ListRefs(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, refs)
+}
+
+func (h defs) serveDefExamples(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListExamplesOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	examples, resp, err := h.s.ListExamples(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, examples)
+}
+
+func (h defs) serveDefAuthors(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListAuthorsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	authors, resp, err := h.s.ListAuthors(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, authors)
+}
+
+func (h defs) serveDefClients(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListClientsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	clients, resp, err := h.s.ListClients(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, clients)
+}
+
+func (h defs) serveDefDependents(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListDependentsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	dependents, resp, err := h.s.ListDependents(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, dependents)
+}
+
+func (h defs) serveDefVersions(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DefListVersionsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	versions, resp, err := h.s.ListVersions(r.Context(), def, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, versions)
+}
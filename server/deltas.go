@@ -0,0 +1,187 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type deltas struct{ s sourcegraph.DeltasService }
+
+func (h deltas) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Delta:                   h.serveDelta,
+		router.DeltaUnits:              h.serveDeltaUnits,
+		router.DeltaDefs:               h.serveDeltaDefs,
+		router.DeltaDependencies:       h.serveDeltaDependencies,
+		router.DeltaFiles:              h.serveDeltaFiles,
+		router.DeltaAffectedAuthors:    h.serveDeltaAffectedAuthors,
+		router.DeltaAffectedClients:    h.serveDeltaAffectedClients,
+		router.DeltaAffectedDependents: h.serveDeltaAffectedDependents,
+		router.DeltaReviewers:          h.serveDeltaReviewers,
+		router.DeltasIncoming:          h.serveDeltasIncoming,
+	}
+}
+
+func (h deltas) serveDelta(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	delta, resp, err := h.s.Get(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, delta)
+}
+
+func (h deltas) serveDeltaUnits(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListUnitsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	units, resp, err := h.s.ListUnits(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, units)
+}
+
+func (h deltas) serveDeltaDefs(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListDefsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	defs, resp, err := h.s.ListDefs(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, defs)
+}
+
+func (h deltas) serveDeltaDependencies(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListDependenciesOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	deps, resp, err := h.s.ListDependencies(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, deps)
+}
+
+func (h deltas) serveDeltaFiles(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListFilesOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	files, resp, err := h.s.ListFiles(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, files)
+}
+
+func (h deltas) serveDeltaAffectedAuthors(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListAffectedAuthorsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	authors, resp, err := h.s.ListAffectedAuthors(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, authors)
+}
+
+func (h deltas) serveDeltaAffectedClients(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListAffectedClientsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	clients, resp, err := h.s.ListAffectedClients(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, clients)
+}
+
+func (h deltas) serveDeltaAffectedDependents(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListAffectedDependentsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	dependents, resp, err := h.s.ListAffectedDependents(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, dependents)
+}
+
+func (h deltas) serveDeltaReviewers(w http.ResponseWriter, r *http.Request) error {
+	ds, err := httpapi.DeltaSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListReviewersOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	reviewers, resp, err := h.s.ListReviewers(r.Context(), ds, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, reviewers)
+}
+
+func (h deltas) serveDeltasIncoming(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.DeltaListIncomingOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	deltas, resp, err := h.s.ListIncoming(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, deltas)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type issues struct{ s sourcegraph.IssuesService }
+
+func (h issues) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.RepoIssues:              h.serveRepoIssues,
+		router.RepoIssue:               h.serveRepoIssue,
+		router.RepoIssueComments:       h.serveRepoIssueComments,
+		router.RepoIssueCommentsCreate: h.serveRepoIssueCommentsCreate,
+		router.RepoIssueCommentsEdit:   h.serveRepoIssueCommentsEdit,
+		router.RepoIssueCommentsDelete: h.serveRepoIssueCommentsDelete,
+	}
+}
+
+func (h issues) serveRepoIssues(w http.ResponseWriter, r *http.Request) error {
+	repo, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.IssueListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	issues, resp, err := h.s.ListByRepo(r.Context(), repo, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, issues)
+}
+
+func (h issues) serveRepoIssue(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.IssueSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.IssueGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	issue, resp, err := h.s.Get(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, issue)
+}
+
+func (h issues) serveRepoIssueComments(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.IssueSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.IssueListCommentsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	comments, resp, err := h.s.ListComments(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, comments)
+}
+
+func (h issues) serveRepoIssueCommentsCreate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.IssueSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var comment sourcegraph.IssueComment
+	if err := httpapi.ReadJSON(r, &comment); err != nil {
+		return err
+	}
+	created, resp, err := h.s.CreateComment(r.Context(), spec, &comment)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, created)
+}
+
+// serveRepoIssueCommentsEdit edits the comment whose ID is in the
+// route (which takes precedence over the ID in the request body).
+func (h issues) serveRepoIssueCommentsEdit(w http.ResponseWriter, r *http.Request) error {
+	vars := mux.Vars(r)
+	spec, err := httpapi.IssueSpecFromVars(vars)
+	if err != nil {
+		return err
+	}
+	id, err := httpapi.CommentIDFromVars(vars)
+	if err != nil {
+		return err
+	}
+	var comment sourcegraph.IssueComment
+	if err := httpapi.ReadJSON(r, &comment); err != nil {
+		return err
+	}
+	comment.ID = &id
+	edited, resp, err := h.s.EditComment(r.Context(), spec, &comment)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, edited)
+}
+
+func (h issues) serveRepoIssueCommentsDelete(w http.ResponseWriter, r *http.Request) error {
+	vars := mux.Vars(r)
+	spec, err := httpapi.IssueSpecFromVars(vars)
+	if err != nil {
+		return err
+	}
+	id, err := httpapi.CommentIDFromVars(vars)
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.DeleteComment(r.Context(), spec, id)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type markdown struct{ s sourcegraph.MarkdownService }
+
+func (h markdown) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Markdown: h.serveMarkdown,
+	}
+}
+
+func (h markdown) serveMarkdown(w http.ResponseWriter, r *http.Request) error {
+	var body sourcegraph.MarkdownRequestBody
+	if err := httpapi.ReadJSON(r, &body); err != nil {
+		return err
+	}
+	data, resp, err := h.s.Render(r.Context(), body.Markdown, body.MarkdownOpt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, data)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type orgs struct{ s sourcegraph.OrgsService }
+
+func (h orgs) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Org:               h.serveOrg,
+		router.OrgMembers:        h.serveOrgMembers,
+		router.OrgSettings:       h.serveOrgSettings,
+		router.OrgSettingsUpdate: h.serveOrgSettingsUpdate,
+	}
+}
+
+func (h orgs) serveOrg(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.OrgSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	org, resp, err := h.s.Get(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, org)
+}
+
+func (h orgs) serveOrgMembers(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.OrgSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.OrgListMembersOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	members, resp, err := h.s.ListMembers(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, members)
+}
+
+func (h orgs) serveOrgSettings(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.OrgSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	settings, resp, err := h.s.GetSettings(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, settings)
+}
+
+func (h orgs) serveOrgSettingsUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.OrgSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var settings sourcegraph.OrgSettings
+	if err := httpapi.ReadJSON(r, &settings); err != nil {
+		return err
+	}
+	resp, err := h.s.UpdateSettings(r.Context(), spec, settings)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type people struct{ s sourcegraph.PeopleService }
+
+func (h people) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Person: h.servePerson,
+	}
+}
+
+func (h people) servePerson(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PersonSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	person, resp, err := h.s.Get(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, person)
+}
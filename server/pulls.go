@@ -0,0 +1,137 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type pullRequests struct {
+	s sourcegraph.PullRequestsService
+}
+
+func (h pullRequests) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.RepoPullRequests:              h.serveRepoPullRequests,
+		router.RepoPullRequest:               h.serveRepoPullRequest,
+		router.RepoPullRequestMerge:          h.serveRepoPullRequestMerge,
+		router.RepoPullRequestComments:       h.serveRepoPullRequestComments,
+		router.RepoPullRequestCommentsCreate: h.serveRepoPullRequestCommentsCreate,
+		router.RepoPullRequestCommentsEdit:   h.serveRepoPullRequestCommentsEdit,
+		router.RepoPullRequestCommentsDelete: h.serveRepoPullRequestCommentsDelete,
+	}
+}
+
+func (h pullRequests) serveRepoPullRequests(w http.ResponseWriter, r *http.Request) error {
+	repo, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.PullRequestListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	pulls, resp, err := h.s.ListByRepo(r.Context(), repo, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, pulls)
+}
+
+func (h pullRequests) serveRepoPullRequest(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PullRequestSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.PullRequestGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	pull, resp, err := h.s.Get(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, pull)
+}
+
+func (h pullRequests) serveRepoPullRequestMerge(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PullRequestSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var mergeRequest sourcegraph.PullRequestMergeRequest
+	if err := httpapi.ReadJSON(r, &mergeRequest); err != nil {
+		return err
+	}
+	result, resp, err := h.s.Merge(r.Context(), spec, &mergeRequest)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, result)
+}
+
+func (h pullRequests) serveRepoPullRequestComments(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PullRequestSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.PullRequestListCommentsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	comments, resp, err := h.s.ListComments(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, comments)
+}
+
+func (h pullRequests) serveRepoPullRequestCommentsCreate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PullRequestSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var comment sourcegraph.PullRequestComment
+	if err := httpapi.ReadJSON(r, &comment); err != nil {
+		return err
+	}
+	created, resp, err := h.s.CreateComment(r.Context(), spec, &comment)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, created)
+}
+
+// serveRepoPullRequestCommentsEdit edits the comment whose ID is in
+// the route (which takes precedence over the ID in the request body).
+func (h pullRequests) serveRepoPullRequestCommentsEdit(w http.ResponseWriter, r *http.Request) error {
+	spec, err := sourcegraph.UnmarshalPullRequestCommentSpec(mux.Vars(r))
+	if err != nil {
+		return &httpapi.BadRequestError{Err: err}
+	}
+	var comment sourcegraph.PullRequestComment
+	if err := httpapi.ReadJSON(r, &comment); err != nil {
+		return err
+	}
+	comment.ID = &spec.Comment
+	edited, resp, err := h.s.EditComment(r.Context(), spec.Pull, &comment)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, edited)
+}
+
+func (h pullRequests) serveRepoPullRequestCommentsDelete(w http.ResponseWriter, r *http.Request) error {
+	spec, err := sourcegraph.UnmarshalPullRequestCommentSpec(mux.Vars(r))
+	if err != nil {
+		return &httpapi.BadRequestError{Err: err}
+	}
+	resp, err := h.s.DeleteComment(r.Context(), spec.Pull, spec.Comment)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type repoTree struct{ s sourcegraph.RepoTreeService }
+
+func (h repoTree) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.RepoTreeEntry: h.serveRepoTreeEntry,
+	}
+}
+
+func (h repoTree) serveRepoTreeEntry(w http.ResponseWriter, r *http.Request) error {
+	entry, err := httpapi.TreeEntrySpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoTreeGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	e, resp, err := h.s.Get(r.Context(), entry, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, e)
+}
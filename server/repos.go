@@ -0,0 +1,450 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type repos struct{ s sourcegraph.ReposService }
+
+func (h repos) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Repos:              h.serveRepos,
+		router.ReposCreate:        h.serveReposCreate,
+		router.Repo:               h.serveRepo,
+		router.ReposGetOrCreate:   h.serveReposGetOrCreate,
+		router.RepoStats:          h.serveRepoStats,
+		router.RepoComputeStats:   h.serveRepoComputeStats,
+		router.RepoStatusCreate:   h.serveRepoStatusCreate,
+		router.RepoCombinedStatus: h.serveRepoCombinedStatus,
+		router.RepoSettings:       h.serveRepoSettings,
+		router.RepoSettingsUpdate: h.serveRepoSettingsUpdate,
+		router.RepoRefreshProfile: h.serveRepoRefreshProfile,
+		router.RepoRefreshVCSData: h.serveRepoRefreshVCSData,
+		router.RepoBuild:          h.serveRepoBuild,
+		router.RepoReadme:         h.serveRepoReadme,
+		router.RepoCommits:        h.serveRepoCommits,
+		router.RepoCommit:         h.serveRepoCommit,
+		router.RepoCompareCommits: h.serveRepoCompareCommits,
+		router.RepoBranches:       h.serveRepoBranches,
+		router.RepoTags:           h.serveRepoTags,
+		router.RepoBadges:         h.serveRepoBadges,
+		router.RepoCounters:       h.serveRepoCounters,
+		router.RepoAuthors:        h.serveRepoAuthors,
+		router.RepoClients:        h.serveRepoClients,
+		router.RepoDependencies:   h.serveRepoDependencies,
+		router.RepoDependents:     h.serveRepoDependents,
+
+		router.UserRepoContributions: h.serveUserRepoContributions,
+		router.UserRepoDependencies:  h.serveUserRepoDependencies,
+		router.UserRepoDependents:    h.serveUserRepoDependents,
+	}
+}
+
+func (h repos) serveRepos(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.RepoListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repos, resp, err := h.s.List(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repos)
+}
+
+func (h repos) serveReposCreate(w http.ResponseWriter, r *http.Request) error {
+	var newRepoSpec sourcegraph.NewRepoSpec
+	if err := httpapi.ReadJSON(r, &newRepoSpec); err != nil {
+		return err
+	}
+	repo, resp, err := h.s.Create(r.Context(), newRepoSpec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repo)
+}
+
+func (h repos) serveRepo(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repo, resp, err := h.s.Get(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repo)
+}
+
+func (h repos) serveReposGetOrCreate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repo, resp, err := h.s.GetOrCreate(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repo)
+}
+
+func (h repos) serveRepoStats(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	stats, resp, err := h.s.GetStats(r.Context(), repoRev)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, stats)
+}
+
+func (h repos) serveRepoComputeStats(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.ComputeStats(r.Context(), repoRev)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h repos) serveRepoStatusCreate(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var st sourcegraph.RepoStatus
+	if err := httpapi.ReadJSON(r, &st); err != nil {
+		return err
+	}
+	created, resp, err := h.s.CreateStatus(r.Context(), repoRev, st)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, created)
+}
+
+func (h repos) serveRepoCombinedStatus(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	status, resp, err := h.s.GetCombinedStatus(r.Context(), repoRev)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, status)
+}
+
+func (h repos) serveRepoSettings(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	settings, resp, err := h.s.GetSettings(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, settings)
+}
+
+func (h repos) serveRepoSettingsUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var settings sourcegraph.RepoSettings
+	if err := httpapi.ReadJSON(r, &settings); err != nil {
+		return err
+	}
+	resp, err := h.s.UpdateSettings(r.Context(), spec, settings)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h repos) serveRepoRefreshProfile(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.RefreshProfile(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h repos) serveRepoRefreshVCSData(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.RefreshVCSData(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h repos) serveRepoBuild(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoGetBuildOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	info, resp, err := h.s.GetBuild(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, info)
+}
+
+func (h repos) serveRepoReadme(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	readme, resp, err := h.s.GetReadme(r.Context(), repoRev)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, readme)
+}
+
+func (h repos) serveRepoCommits(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListCommitsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	commits, resp, err := h.s.ListCommits(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, commits)
+}
+
+func (h repos) serveRepoCommit(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoGetCommitOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	commit, resp, err := h.s.GetCommit(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, commit)
+}
+
+func (h repos) serveRepoCompareCommits(w http.ResponseWriter, r *http.Request) error {
+	head, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoCompareCommitsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	cmp, resp, err := h.s.CompareCommits(r.Context(), head, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, cmp)
+}
+
+func (h repos) serveRepoBranches(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListBranchesOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	branches, resp, err := h.s.ListBranches(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, branches)
+}
+
+func (h repos) serveRepoTags(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListTagsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	tags, resp, err := h.s.ListTags(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, tags)
+}
+
+func (h repos) serveRepoBadges(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	badges, resp, err := h.s.ListBadges(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, badges)
+}
+
+func (h repos) serveRepoCounters(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	counters, resp, err := h.s.ListCounters(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, counters)
+}
+
+func (h repos) serveRepoAuthors(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListAuthorsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	authors, resp, err := h.s.ListAuthors(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, authors)
+}
+
+func (h repos) serveRepoClients(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListClientsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	clients, resp, err := h.s.ListClients(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, clients)
+}
+
+func (h repos) serveRepoDependencies(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListDependenciesOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	deps, resp, err := h.s.ListDependencies(r.Context(), repoRev, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, deps)
+}
+
+func (h repos) serveRepoDependents(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListDependentsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	dependents, resp, err := h.s.ListDependents(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, dependents)
+}
+
+func (h repos) serveUserRepoContributions(w http.ResponseWriter, r *http.Request) error {
+	user, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListByContributorOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repos, resp, err := h.s.ListByContributor(r.Context(), user, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repos)
+}
+
+func (h repos) serveUserRepoDependencies(w http.ResponseWriter, r *http.Request) error {
+	user, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListByClientOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repos, resp, err := h.s.ListByClient(r.Context(), user, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repos)
+}
+
+func (h repos) serveUserRepoDependents(w http.ResponseWriter, r *http.Request) error {
+	user, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.RepoListByRefdAuthorOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	repos, resp, err := h.s.ListByRefdAuthor(r.Context(), user, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, repos)
+}
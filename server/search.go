@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type search struct{ s sourcegraph.SearchService }
+
+func (h search) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Search:            h.serveSearch,
+		router.SearchComplete:    h.serveSearchComplete,
+		router.SearchSuggestions: h.serveSearchSuggestions,
+	}
+}
+
+func (h search) serveSearch(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.SearchOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	results, resp, err := h.s.Search(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, results)
+}
+
+func (h search) serveSearchComplete(w http.ResponseWriter, r *http.Request) error {
+	var q sourcegraph.RawQuery
+	if err := httpapi.DecodeOptions(r, &q); err != nil {
+		return err
+	}
+	comps, resp, err := h.s.Complete(r.Context(), q)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, comps)
+}
+
+func (h search) serveSearchSuggestions(w http.ResponseWriter, r *http.Request) error {
+	var q sourcegraph.RawQuery
+	if err := httpapi.DecodeOptions(r, &q); err != nil {
+		return err
+	}
+	suggs, resp, err := h.s.Suggest(r.Context(), q)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, suggs)
+}
@@ -0,0 +1,169 @@
+// Package server serves implementations of the sourcegraph package's
+// service interfaces over HTTP, using the API router's routes.
+//
+// A handler created by NewHandler decodes each request's route
+// variables, querystring options, and body into the arguments of the
+// corresponding service method, calls the method, and writes its
+// result the same way the Sourcegraph API does: as a JSON response
+// body, with the x-total-count header for lists, and with errors
+// written as JSON error responses that sourcegraph.CheckResponse
+// understands. So, an API client (sourcegraph.Client) that talks to
+// the handler sees the same results and typed errors as the service
+// implementations return.
+//
+// This makes it possible to run API-compatible servers backed by any
+// implementation of the services, and proxies (by serving the
+// services of another sourcegraph.Client; see ServicesFromClient).
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/auth"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// Services are the service implementations that a handler serves.
+// The routes of nil services respond with HTTP 501 Not Implemented.
+type Services struct {
+	Builds       sourcegraph.BuildsService
+	Defs         sourcegraph.DefsService
+	Deltas       sourcegraph.DeltasService
+	Issues       sourcegraph.IssuesService
+	Markdown     sourcegraph.MarkdownService
+	Orgs         sourcegraph.OrgsService
+	People       sourcegraph.PeopleService
+	PullRequests sourcegraph.PullRequestsService
+	Repos        sourcegraph.ReposService
+	RepoTree     sourcegraph.RepoTreeService
+	Search       sourcegraph.SearchService
+	Snippets     sourcegraph.SnippetsService
+	Units        sourcegraph.UnitsService
+	Users        sourcegraph.UsersService
+}
+
+// ServicesFromClient returns the services of c. Serving them makes a
+// proxy for the API server that c talks to.
+func ServicesFromClient(c *sourcegraph.Client) Services {
+	return Services{
+		Builds:       c.Builds,
+		Defs:         c.Defs,
+		Deltas:       c.Deltas,
+		Issues:       c.Issues,
+		Markdown:     c.Markdown,
+		Orgs:         c.Orgs,
+		People:       c.People,
+		PullRequests: c.PullRequests,
+		Repos:        c.Repos,
+		RepoTree:     c.RepoTree,
+		Search:       c.Search,
+		Snippets:     c.Snippets,
+		Units:        c.Units,
+		Users:        c.Users,
+	}
+}
+
+// NewHandler returns an HTTP handler that serves svc. It sets the
+// handlers of the routes in r (or, if r is nil, in a new API router)
+// that correspond to service methods.
+//
+// Routes that don't correspond to service methods (such as the build
+// data, badge, and GitHub webhook routes) are left alone, so that the
+// caller may set their handlers on r. Requests to routes that have
+// no handler (including the routes of nil services) fail with HTTP
+// 501 Not Implemented.
+func NewHandler(r *mux.Router, svc Services) http.Handler {
+	if r == nil {
+		r = router.NewAPIRouter(nil)
+	}
+	mount := func(routes map[string]httpapi.HandlerFunc) {
+		for name, h := range routes {
+			r.Get(name).Handler(h)
+		}
+	}
+	if svc.Builds != nil {
+		mount(builds{svc.Builds}.routes())
+	}
+	if svc.Defs != nil {
+		mount(defs{svc.Defs}.routes())
+	}
+	if svc.Deltas != nil {
+		mount(deltas{svc.Deltas}.routes())
+	}
+	if svc.Issues != nil {
+		mount(issues{svc.Issues}.routes())
+	}
+	if svc.Markdown != nil {
+		mount(markdown{svc.Markdown}.routes())
+	}
+	if svc.Orgs != nil {
+		mount(orgs{svc.Orgs}.routes())
+	}
+	if svc.People != nil {
+		mount(people{svc.People}.routes())
+	}
+	if svc.PullRequests != nil {
+		mount(pullRequests{svc.PullRequests}.routes())
+	}
+	if svc.Repos != nil {
+		mount(repos{svc.Repos}.routes())
+	}
+	if svc.RepoTree != nil {
+		mount(repoTree{svc.RepoTree}.routes())
+	}
+	if svc.Search != nil {
+		mount(search{svc.Search}.routes())
+	}
+	if svc.Snippets != nil {
+		mount(snippets{svc.Snippets}.routes())
+	}
+	if svc.Units != nil {
+		mount(units{svc.Units}.routes())
+	}
+	if svc.Users != nil {
+		mount(users{svc.Users}.routes())
+	}
+	return httpapi.NotImplementedHandler{Router: r}
+}
+
+// writeResult writes the headers of resp and then v, as JSON. It is
+// used to write the results of service methods.
+func writeResult(w http.ResponseWriter, resp sourcegraph.Response, v interface{}) error {
+	writeHeaders(w, resp)
+	w.Header().Set("content-type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(v)
+}
+
+// writeEmptyResult writes the headers of resp, and no body. It is
+// used to write the results of service methods that return only a
+// Response.
+func writeEmptyResult(w http.ResponseWriter, resp sourcegraph.Response) error {
+	writeHeaders(w, resp)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// writeHeaders sets the response headers that API clients read from
+// resp: the total count and, if resp is a response from another API
+// server, the tickets that it granted.
+func writeHeaders(w http.ResponseWriter, resp sourcegraph.Response) {
+	if resp == nil {
+		return
+	}
+	if hr, ok := resp.(*sourcegraph.HTTPResponse); ok {
+		if hr == nil || hr.Response == nil {
+			return
+		}
+		for _, t := range auth.GetSignedTicketStrings(hr.Header) {
+			w.Header().Add("authorization", auth.TicketAuthScheme+t)
+		}
+	}
+	if n := resp.TotalCount(); n >= 0 {
+		w.Header().Set("x-total-count", strconv.Itoa(n))
+	}
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-github/github"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/auth"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// newTestClient starts a server that serves svc, and returns a client
+// that talks to it.
+func newTestClient(t *testing.T, svc Services) *sourcegraph.Client {
+	s := httptest.NewServer(NewHandler(nil, svc))
+	t.Cleanup(s.Close)
+	return clientFor(s)
+}
+
+func clientFor(s *httptest.Server) *sourcegraph.Client {
+	c := sourcegraph.NewClient(nil)
+	c.BaseURL, _ = url.Parse(s.URL + "/")
+	return c
+}
+
+// totalCountResponse is a sourcegraph.Response with a total count.
+type totalCountResponse int
+
+func (n totalCountResponse) TotalCount() int { return int(n) }
+
+func TestHandler_repos(t *testing.T) {
+	ctx := context.Background()
+	want := &sourcegraph.Repo{RID: 1, URI: "r.com/x"}
+	c := newTestClient(t, Services{
+		Repos: &sourcegraph.MockReposService{
+			Get_: func(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoGetOptions) (*sourcegraph.Repo, sourcegraph.Response, error) {
+				if repo.URI != "r.com/x" || !opt.Stats {
+					t.Errorf("got repo %+v, opt %+v", repo, opt)
+				}
+				return want, nil, nil
+			},
+			List_: func(ctx context.Context, opt *sourcegraph.RepoListOptions) ([]*sourcegraph.Repo, sourcegraph.Response, error) {
+				if wantURIs := []string{"r.com/x", "r.com/y"}; !reflect.DeepEqual(opt.URIs, wantURIs) {
+					t.Errorf("got URIs %q, want %q", opt.URIs, wantURIs)
+				}
+				if opt.PerPage != 1 {
+					t.Errorf("got PerPage %d, want 1", opt.PerPage)
+				}
+				return []*sourcegraph.Repo{want}, totalCountResponse(2), nil
+			},
+			RefreshVCSData_: func(ctx context.Context, repo sourcegraph.RepoSpec) (sourcegraph.Response, error) {
+				return nil, nil
+			},
+		},
+	})
+
+	repo, _, err := c.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, &sourcegraph.RepoGetOptions{Stats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("got repo %+v, want %+v", repo, want)
+	}
+
+	repos, resp, err := c.Repos.List(ctx, &sourcegraph.RepoListOptions{URIs: []string{"r.com/x", "r.com/y"}, ListOptions: sourcegraph.ListOptions{PerPage: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(repos, []*sourcegraph.Repo{want}) {
+		t.Errorf("got repos %+v, want [%+v]", repos, want)
+	}
+	if n := resp.TotalCount(); n != 2 {
+		t.Errorf("got total count %d, want 2", n)
+	}
+
+	if _, err := c.Repos.RefreshVCSData(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandler_routeVars(t *testing.T) {
+	ctx := context.Background()
+	wantDef := sourcegraph.DefSpec{Repo: "r.com/x", CommitID: "c", UnitType: "t", Unit: "u/v", Path: "p/q"}
+	wantEntry := sourcegraph.TreeEntrySpec{
+		RepoRev: sourcegraph.RepoRevSpec{RepoSpec: sourcegraph.RepoSpec{URI: "r.com/x"}, Rev: "master", CommitID: "c"},
+		Path:    "a/b",
+	}
+	wantTask := sourcegraph.TaskSpec{BuildSpec: sourcegraph.BuildSpec{BID: 3}, TaskID: 4}
+	wantIssue := sourcegraph.IssueSpec{Repo: sourcegraph.RepoSpec{URI: "r.com/x"}, Number: 5}
+	var called int
+	c := newTestClient(t, Services{
+		Defs: &sourcegraph.MockDefsService{
+			Get_: func(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefGetOptions) (*sourcegraph.Def, sourcegraph.Response, error) {
+				called++
+				if def != wantDef {
+					t.Errorf("got def %+v, want %+v", def, wantDef)
+				}
+				return &sourcegraph.Def{}, nil, nil
+			},
+		},
+		RepoTree: &sourcegraph.MockRepoTreeService{
+			Get_: func(ctx context.Context, entry sourcegraph.TreeEntrySpec, opt *sourcegraph.RepoTreeGetOptions) (*sourcegraph.TreeEntry, sourcegraph.Response, error) {
+				called++
+				if entry != wantEntry {
+					t.Errorf("got entry %+v, want %+v", entry, wantEntry)
+				}
+				return &sourcegraph.TreeEntry{}, nil, nil
+			},
+		},
+		Builds: &sourcegraph.MockBuildsService{
+			UpdateTask_: func(ctx context.Context, task sourcegraph.TaskSpec, info sourcegraph.TaskUpdate) (*sourcegraph.BuildTask, sourcegraph.Response, error) {
+				called++
+				if task != wantTask {
+					t.Errorf("got task %+v, want %+v", task, wantTask)
+				}
+				return &sourcegraph.BuildTask{}, nil, nil
+			},
+		},
+		Issues: &sourcegraph.MockIssuesService{
+			EditComment_: func(ctx context.Context, issue sourcegraph.IssueSpec, comment *sourcegraph.IssueComment) (*sourcegraph.IssueComment, sourcegraph.Response, error) {
+				called++
+				if issue != wantIssue || comment.ID == nil || *comment.ID != 6 || *comment.Body != "b" {
+					t.Errorf("got issue %+v, comment %+v", issue, comment)
+				}
+				return comment, nil, nil
+			},
+		},
+	})
+
+	if _, _, err := c.Defs.Get(ctx, wantDef, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.RepoTree.Get(ctx, wantEntry, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Builds.UpdateTask(ctx, wantTask, sourcegraph.TaskUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+	comment := &sourcegraph.IssueComment{IssueComment: github.IssueComment{ID: github.Int(6), Body: github.String("b")}}
+	if _, _, err := c.Issues.EditComment(ctx, wantIssue, comment); err != nil {
+		t.Fatal(err)
+	}
+	if called != 4 {
+		t.Errorf("got %d calls, want 4", called)
+	}
+}
+
+func TestHandler_errors(t *testing.T) {
+	ctx := context.Background()
+	var getErr error
+	c := newTestClient(t, Services{
+		Repos: &sourcegraph.MockReposService{
+			Get_: func(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoGetOptions) (*sourcegraph.Repo, sourcegraph.Response, error) {
+				return nil, nil, getErr
+			},
+		},
+	})
+
+	tests := map[string]struct {
+		err        error
+		wantStatus int
+		check      func(error) bool
+	}{
+		"not exist": {
+			err:        sourcegraph.ErrNotExist,
+			wantStatus: http.StatusNotFound,
+			check:      func(err error) bool { return errors.Is(err, sourcegraph.ErrNotExist) },
+		},
+		"forbidden": {
+			err:        sourcegraph.ErrForbidden,
+			wantStatus: http.StatusForbidden,
+			check:      func(err error) bool { return errors.Is(err, sourcegraph.ErrForbidden) },
+		},
+		"renamed": {
+			err:        sourcegraph.ErrRenamed{OldURI: "r.com/x", NewURI: "r.com/y"},
+			wantStatus: http.StatusMovedPermanently,
+			check: func(err error) bool {
+				var renamed sourcegraph.ErrRenamed
+				return errors.As(err, &renamed) && renamed.NewURI == "r.com/y"
+			},
+		},
+		"other": {
+			err:        errors.New("x"),
+			wantStatus: http.StatusInternalServerError,
+			check:      func(err error) bool { return err != nil },
+		},
+	}
+	for label, test := range tests {
+		getErr = test.err
+		_, _, err := c.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+		if !test.check(err) {
+			t.Errorf("%s: got error %v", label, err)
+		}
+		if !sourcegraph.IsHTTPErrorCode(err, test.wantStatus) {
+			t.Errorf("%s: got error %v, want HTTP status %d", label, err, test.wantStatus)
+		}
+	}
+}
+
+func TestHandler_notImplemented(t *testing.T) {
+	c := newTestClient(t, Services{})
+	_, _, err := c.Repos.Get(context.Background(), sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotImplemented) {
+		t.Errorf("got error %v, want HTTP 501", err)
+	}
+}
+
+func TestHandler_dequeueNext(t *testing.T) {
+	var next *sourcegraph.Build
+	c := newTestClient(t, Services{
+		Builds: &sourcegraph.MockBuildsService{
+			DequeueNext_: func(ctx context.Context) (*sourcegraph.Build, sourcegraph.Response, error) {
+				return next, nil, nil
+			},
+		},
+	})
+
+	build, _, err := c.Builds.DequeueNext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build != nil {
+		t.Errorf("got build %+v, want nil (no queued builds)", build)
+	}
+
+	next = &sourcegraph.Build{BID: 1}
+	build, _, err = c.Builds.DequeueNext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build == nil || build.BID != 1 {
+		t.Errorf("got build %+v, want BID 1", build)
+	}
+}
+
+func TestHandler_proxy(t *testing.T) {
+	ctx := context.Background()
+	upstream := httptest.NewServer(NewHandler(nil, Services{
+		Repos: &sourcegraph.MockReposService{
+			Get_: func(ctx context.Context, repo sourcegraph.RepoSpec, opt *sourcegraph.RepoGetOptions) (*sourcegraph.Repo, sourcegraph.Response, error) {
+				return nil, nil, sourcegraph.ErrRenamed{OldURI: repo.URI, NewURI: "r.com/y"}
+			},
+		},
+	}))
+	defer upstream.Close()
+	// The upstream dequeue route grants a ticket, which the proxy
+	// must pass on.
+	ticketUpstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("authorization", auth.TicketAuthScheme+"t")
+		w.Write([]byte(`{"BID": 1}`))
+	}))
+	defer ticketUpstream.Close()
+
+	svc := ServicesFromClient(clientFor(upstream))
+	svc.Builds = clientFor(ticketUpstream).Builds
+	c := newTestClient(t, svc)
+
+	_, _, err := c.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	var renamed sourcegraph.ErrRenamed
+	if !errors.As(err, &renamed) || renamed.NewURI != "r.com/y" {
+		t.Errorf("got error %v, want ErrRenamed to r.com/y", err)
+	}
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusMovedPermanently) {
+		t.Errorf("got error %v, want HTTP 301", err)
+	}
+
+	build, resp, err := c.Builds.DequeueNext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build == nil || build.BID != 1 {
+		t.Errorf("got build %+v, want BID 1", build)
+	}
+	if tickets := auth.GetSignedTicketStrings(resp.(*sourcegraph.HTTPResponse).Header); !reflect.DeepEqual(tickets, []string{"t"}) {
+		t.Errorf("got tickets %q, want [t]", tickets)
+	}
+}
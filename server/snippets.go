@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type snippets struct{ s sourcegraph.SnippetsService }
+
+func (h snippets) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Snippet:           h.serveSnippet,
+		router.RepoTreeSourcebox: h.serveFileSourcebox,
+		router.DefSourcebox:      h.serveDefSourcebox,
+	}
+}
+
+func (h snippets) serveSnippet(w http.ResponseWriter, r *http.Request) error {
+	var snippet sourcegraph.SnippetRequestBody
+	if err := httpapi.ReadJSON(r, &snippet); err != nil {
+		return err
+	}
+	fileData, resp, err := h.s.Annotate(r.Context(), &snippet)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, fileData)
+}
+
+func (h snippets) serveFileSourcebox(w http.ResponseWriter, r *http.Request) error {
+	entry, err := httpapi.TreeEntrySpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.SourceboxFileOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	sourcebox, resp, err := h.s.FileSourcebox(r.Context(), entry, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, sourcebox)
+}
+
+func (h snippets) serveDefSourcebox(w http.ResponseWriter, r *http.Request) error {
+	def, err := httpapi.DefSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	sourcebox, resp, err := h.s.DefSourcebox(r.Context(), def)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, sourcebox)
+}
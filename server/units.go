@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type units struct{ s sourcegraph.UnitsService }
+
+func (h units) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Units: h.serveUnits,
+		router.Unit:  h.serveUnit,
+	}
+}
+
+func (h units) serveUnits(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.UnitListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	units, resp, err := h.s.List(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, units)
+}
+
+func (h units) serveUnit(w http.ResponseWriter, r *http.Request) error {
+	spec, err := sourcegraph.UnmarshalUnitSpec(mux.Vars(r))
+	if err != nil {
+		return &httpapi.BadRequestError{Err: err}
+	}
+	unit, resp, err := h.s.Get(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, unit)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+type users struct{ s sourcegraph.UsersService }
+
+func (h users) routes() map[string]httpapi.HandlerFunc {
+	return map[string]httpapi.HandlerFunc{
+		router.Users:              h.serveUsers,
+		router.User:               h.serveUser,
+		router.UserFromGitHub:     h.serveUserFromGitHub,
+		router.UserOrgs:           h.serveUserOrgs,
+		router.UserAuthors:        h.serveUserAuthors,
+		router.UserClients:        h.serveUserClients,
+		router.UserEmails:         h.serveUserEmails,
+		router.UserRefreshProfile: h.serveUserRefreshProfile,
+		router.UserComputeStats:   h.serveUserComputeStats,
+		router.UserSettings:       h.serveUserSettings,
+		router.UserSettingsUpdate: h.serveUserSettingsUpdate,
+	}
+}
+
+func (h users) serveUsers(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.UsersListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	users, resp, err := h.s.List(r.Context(), &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, users)
+}
+
+func (h users) serveUser(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.UserGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	user, resp, err := h.s.Get(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, user)
+}
+
+func (h users) serveUserFromGitHub(w http.ResponseWriter, r *http.Request) error {
+	spec := sourcegraph.GitHubUserSpec{Login: mux.Vars(r)["GitHubUserSpec"]}
+	var opt sourcegraph.UserGetOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	user, resp, err := h.s.GetOrCreateFromGitHub(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, user)
+}
+
+func (h users) serveUserOrgs(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.UsersListOrgsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	orgs, resp, err := h.s.ListOrgs(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, orgs)
+}
+
+func (h users) serveUserAuthors(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.UsersListAuthorsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	authors, resp, err := h.s.ListAuthors(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, authors)
+}
+
+func (h users) serveUserClients(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.UsersListClientsOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	clients, resp, err := h.s.ListClients(r.Context(), spec, &opt)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, clients)
+}
+
+func (h users) serveUserEmails(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	emails, resp, err := h.s.ListEmails(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, emails)
+}
+
+func (h users) serveUserRefreshProfile(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.RefreshProfile(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h users) serveUserComputeStats(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	resp, err := h.s.ComputeStats(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
+
+func (h users) serveUserSettings(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	settings, resp, err := h.s.GetSettings(r.Context(), spec)
+	if err != nil {
+		return err
+	}
+	return writeResult(w, resp, settings)
+}
+
+func (h users) serveUserSettingsUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var settings sourcegraph.UserSettings
+	if err := httpapi.ReadJSON(r, &settings); err != nil {
+		return err
+	}
+	resp, err := h.s.UpdateSettings(r.Context(), spec, settings)
+	if err != nil {
+		return err
+	}
+	return writeEmptyResult(w, resp)
+}
@@ -0,0 +1,197 @@
+package sourcegraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests that
+// batch helpers (such as GetManyDefs) make if
+// BatchOptions.Concurrency is not set.
+const DefaultBatchConcurrency = 8
+
+// DefaultBatchChunkSize is the number of repositories that
+// GetManyRepos fetches per request if BatchOptions.ChunkSize is not
+// set.
+const DefaultBatchChunkSize = 100
+
+// BatchOptions configures the batch helpers (GetManyDefs,
+// GetManyRepos, and GetManyUnits). A nil *BatchOptions uses the
+// defaults.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests that are made
+	// concurrently. If zero, DefaultBatchConcurrency is used.
+	Concurrency int
+
+	// ChunkSize is the maximum number of items that are fetched per
+	// request, for helpers that fetch several items per request
+	// (GetManyRepos). If zero, DefaultBatchChunkSize is used.
+	ChunkSize int
+}
+
+func (o *BatchOptions) concurrency() int {
+	if o == nil || o.Concurrency <= 0 {
+		return DefaultBatchConcurrency
+	}
+	return o.Concurrency
+}
+
+func (o *BatchOptions) chunkSize() int {
+	if o == nil || o.ChunkSize <= 0 {
+		return DefaultBatchChunkSize
+	}
+	return o.ChunkSize
+}
+
+// BatchErrors are the errors that occurred while fetching a batch of
+// items: the i'th element is the error (or nil) for the i'th item.
+type BatchErrors []error
+
+// Err returns the first non-nil error in e, or nil if there is none.
+func (e BatchErrors) Err() error {
+	for _, err := range e {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch calls get for each index in [0, n), with at most concurrency
+// concurrent calls, and returns the results in index order. If ctx is
+// done, the remaining indexes are not fetched, and their errors are
+// ctx's error.
+func batch[T any](ctx context.Context, n, concurrency int, get func(ctx context.Context, i int) (T, error)) ([]T, BatchErrors) {
+	results := make([]T, n)
+	errs := make(BatchErrors, n)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < n; j++ {
+				errs[j] = err
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = get(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// GetManyDefs fetches the defs specified by specs (with s.Get),
+// concurrently. The i'th def is for the i'th spec; if it couldn't be
+// fetched, it is nil and the i'th error is set. If ctx is canceled,
+// the defs that haven't been fetched yet are skipped, and their
+// errors are ctx's error.
+func GetManyDefs(ctx context.Context, s DefsService, specs []DefSpec, opt *DefGetOptions, bopt *BatchOptions) ([]*Def, BatchErrors) {
+	return batch(ctx, len(specs), bopt.concurrency(), func(ctx context.Context, i int) (*Def, error) {
+		def, _, err := s.Get(ctx, specs[i], opt)
+		return def, err
+	})
+}
+
+// GetManyUnits fetches the units specified by specs (with s.Get),
+// concurrently. Results and errors are returned as for GetManyDefs.
+//
+// To fetch all of the units in a repository revision, use s.List
+// (with UnitListOptions.RepoRevs) and a Pager instead.
+func GetManyUnits(ctx context.Context, s UnitsService, specs []UnitSpec, bopt *BatchOptions) ([]*unit.RepoSourceUnit, BatchErrors) {
+	return batch(ctx, len(specs), bopt.concurrency(), func(ctx context.Context, i int) (*unit.RepoSourceUnit, error) {
+		u, _, err := s.Get(ctx, specs[i])
+		return u, err
+	})
+}
+
+// GetManyRepos fetches the repositories specified by repos. It lists
+// them (with s.List and RepoListOptions.URIs) in chunks of up to
+// bopt's ChunkSize repositories, concurrently, and matches the listed
+// repositories to the specs by URI, ignoring case. Repositories that
+// aren't listed under the URI that was asked for (such as renamed
+// ones) are fetched individually with s.Get. The i'th repository is
+// for the i'th spec; if it couldn't be fetched, it is nil and the i'th
+// error is set. Results are returned as for GetManyDefs.
+//
+// Only the repositories' URIs are used to look them up, so specs
+// without a URI (with only a RID) are errors.
+func GetManyRepos(ctx context.Context, s ReposService, repos []RepoSpec, bopt *BatchOptions) ([]*Repo, BatchErrors) {
+	size := bopt.chunkSize()
+	nchunks := (len(repos) + size - 1) / size
+	chunks, chunkErrs := batch(ctx, nchunks, bopt.concurrency(), func(ctx context.Context, i int) ([]*Repo, error) {
+		end := (i + 1) * size
+		if end > len(repos) {
+			end = len(repos)
+		}
+		specs := repos[i*size : end]
+		var uris []string
+		for _, spec := range specs {
+			if spec.URI != "" {
+				uris = append(uris, spec.URI)
+			}
+		}
+		byURI := make(map[string]*Repo, len(uris))
+		if len(uris) > 0 {
+			opt := &RepoListOptions{URIs: uris, ListOptions: ListOptions{PerPage: len(uris)}}
+			list, _, err := s.List(ctx, opt)
+			if err != nil {
+				return nil, err
+			}
+			for _, repo := range list {
+				byURI[strings.ToLower(repo.URI)] = repo
+			}
+		}
+		results := make([]*Repo, len(specs))
+		for j, spec := range specs {
+			if spec.URI != "" {
+				results[j] = byURI[strings.ToLower(spec.URI)]
+			}
+		}
+		return results, nil
+	})
+
+	results := make([]*Repo, len(repos))
+	errs := make(BatchErrors, len(repos))
+	var unlisted []int
+	for i, spec := range repos {
+		c, j := i/size, i%size
+		switch {
+		case spec.URI == "":
+			errs[i] = fmt.Errorf("GetManyRepos: repos[%d] has no URI", i)
+		case chunkErrs[c] != nil:
+			errs[i] = chunkErrs[c]
+		case chunks[c][j] == nil:
+			unlisted = append(unlisted, i)
+		default:
+			results[i] = chunks[c][j]
+		}
+	}
+
+	got, getErrs := batch(ctx, len(unlisted), bopt.concurrency(), func(ctx context.Context, k int) (*Repo, error) {
+		repo, _, err := s.Get(ctx, repos[unlisted[k]], nil)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	})
+	for k, i := range unlisted {
+		results[i], errs[i] = got[k], getErrs[k]
+	}
+	return results, errs
+}
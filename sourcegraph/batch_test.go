@@ -0,0 +1,211 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/srclib/graph"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
+
+func TestGetManyDefs(t *testing.T) {
+	errBad := errors.New("bad")
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := &MockDefsService{
+		Get_: func(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+			started <- struct{}{}
+			<-release
+			mu.Lock()
+			active--
+			mu.Unlock()
+			if def.Path == "bad" {
+				return nil, nil, errBad
+			}
+			return &Def{Def: graph.Def{DefKey: graph.DefKey{Repo: def.Repo, Path: def.Path}}}, nil, nil
+		},
+	}
+
+	specs := []DefSpec{{Repo: "r", Path: "a"}, {Repo: "r", Path: "bad"}, {Repo: "r", Path: "c"}, {Repo: "r", Path: "d"}}
+	done := make(chan struct{})
+	var defs []*Def
+	var errs BatchErrors
+	go func() {
+		defs, errs = GetManyDefs(context.Background(), s, specs, nil, &BatchOptions{Concurrency: 2})
+		close(done)
+	}()
+	for range specs {
+		<-started
+		release <- struct{}{}
+	}
+	<-done
+
+	if maxActive > 2 {
+		t.Errorf("got %d concurrent calls, want at most 2", maxActive)
+	}
+	for i, spec := range specs {
+		if spec.Path == "bad" {
+			if defs[i] != nil || errs[i] != errBad {
+				t.Errorf("%d: got def %v and error %v, want nil and %v", i, defs[i], errs[i], errBad)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("%d: got error %v", i, errs[i])
+		} else if defs[i].Path != spec.Path {
+			t.Errorf("%d: got def path %q, want %q", i, defs[i].Path, spec.Path)
+		}
+	}
+	if err := errs.Err(); err != errBad {
+		t.Errorf("got first error %v, want %v", err, errBad)
+	}
+}
+
+func TestGetManyDefs_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var calls int
+	s := &MockDefsService{
+		Get_: func(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+			cancel()
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		},
+	}
+
+	specs := make([]DefSpec, 10)
+	_, errs := GetManyDefs(ctx, s, specs, nil, &BatchOptions{Concurrency: 1})
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+	for i, err := range errs {
+		if err != context.Canceled {
+			t.Errorf("%d: got error %v, want %v", i, err, context.Canceled)
+		}
+	}
+}
+
+func TestGetManyUnits(t *testing.T) {
+	s := &MockUnitsService{
+		Get_: func(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
+			if spec.Unit == "missing" {
+				return nil, nil, ErrNotExist
+			}
+			return &unit.RepoSourceUnit{Repo: spec.URI, UnitType: spec.UnitType, Unit: spec.Unit}, nil, nil
+		},
+	}
+
+	specs := []UnitSpec{
+		{RepoRevSpec: RepoRevSpec{RepoSpec: RepoSpec{URI: "r"}}, UnitType: "t", Unit: "u1"},
+		{RepoRevSpec: RepoRevSpec{RepoSpec: RepoSpec{URI: "r"}}, UnitType: "t", Unit: "missing"},
+		{RepoRevSpec: RepoRevSpec{RepoSpec: RepoSpec{URI: "r"}}, UnitType: "t", Unit: "u2"},
+	}
+	units, errs := GetManyUnits(context.Background(), s, specs, nil)
+	if units[0].Unit != "u1" || units[1] != nil || units[2].Unit != "u2" {
+		t.Errorf("got units %+v", units)
+	}
+	if want := (BatchErrors{nil, ErrNotExist, nil}); !reflect.DeepEqual(errs, want) {
+		t.Errorf("got errors %v, want %v", errs, want)
+	}
+}
+
+func TestGetManyRepos(t *testing.T) {
+	errBad := errors.New("bad")
+	var mu sync.Mutex
+	var calls [][]string
+	s := &MockReposService{
+		List_: func(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error) {
+			mu.Lock()
+			calls = append(calls, opt.URIs)
+			mu.Unlock()
+			if opt.PerPage != len(opt.URIs) {
+				t.Errorf("got PerPage %d, want %d", opt.PerPage, len(opt.URIs))
+			}
+			var repos []*Repo
+			for i := len(opt.URIs) - 1; i >= 0; i-- { // in a different order
+				switch uri := opt.URIs[i]; uri {
+				case "bad":
+					return nil, nil, errBad
+				case "missing":
+				default:
+					repos = append(repos, &Repo{URI: uri})
+				}
+			}
+			return repos, nil, nil
+		},
+		Get_: func(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+			return nil, nil, ErrNotExist
+		},
+	}
+
+	var specs []RepoSpec
+	for _, uri := range []string{"r0", "r1", "r2", "missing", "r4", "bad", "r6"} {
+		specs = append(specs, RepoSpec{URI: uri})
+	}
+	repos, errs := GetManyRepos(context.Background(), s, specs, &BatchOptions{ChunkSize: 2})
+
+	if len(calls) != 4 {
+		t.Errorf("got %d List calls (%v), want 4", len(calls), calls)
+	}
+	wantErrs := BatchErrors{nil, nil, nil, ErrNotExist, errBad, errBad, nil}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("got errors %v, want %v", errs, wantErrs)
+	}
+	for i, repo := range repos {
+		if wantErrs[i] != nil {
+			if repo != nil {
+				t.Errorf("%d: got repo %+v, want nil", i, repo)
+			}
+		} else if want := fmt.Sprintf("r%d", i); repo == nil || repo.URI != want {
+			t.Errorf("%d: got repo %+v, want %q", i, repo, want)
+		}
+	}
+}
+
+func TestGetManyRepos_unlisted(t *testing.T) {
+	var gets []RepoSpec
+	s := &MockReposService{
+		List_: func(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error) {
+			if want := []string{"r.com/Upper", "r.com/old"}; !reflect.DeepEqual(opt.URIs, want) {
+				t.Errorf("got URIs %q, want %q", opt.URIs, want)
+			}
+			// Listed under their canonical URIs.
+			return []*Repo{{URI: "r.com/upper"}, {URI: "r.com/new"}}, nil, nil
+		},
+		Get_: func(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+			gets = append(gets, repo)
+			return &Repo{URI: "r.com/new"}, nil, nil
+		},
+	}
+
+	specs := []RepoSpec{{URI: "r.com/Upper"}, {RID: 1}, {URI: "r.com/old"}}
+	repos, errs := GetManyRepos(context.Background(), s, specs, nil)
+
+	if repos[0] == nil || repos[0].URI != "r.com/upper" || errs[0] != nil {
+		t.Errorf("different case: got repo %+v, error %v, want r.com/upper", repos[0], errs[0])
+	}
+	if repos[1] != nil || errs[1] == nil {
+		t.Errorf("RID only: got repo %+v, error %v, want an error", repos[1], errs[1])
+	}
+	if repos[2] == nil || repos[2].URI != "r.com/new" || errs[2] != nil {
+		t.Errorf("renamed: got repo %+v, error %v, want r.com/new", repos[2], errs[2])
+	}
+	if want := []RepoSpec{{URI: "r.com/old"}}; !reflect.DeepEqual(gets, want) {
+		t.Errorf("got Get calls %+v, want %+v", gets, want)
+	}
+}
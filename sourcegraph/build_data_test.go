@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -30,7 +31,7 @@ func TestBuildDataService_GetBuildDataFile(t *testing.T) {
 		}
 	})
 
-	file, _, err := GetBuildDataFile(client.BuildData, BuildDataFileSpec{RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, Path: "a/b"})
+	file, _, err := GetBuildDataFile(context.Background(), client.BuildData, BuildDataFileSpec{RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, Path: "a/b"})
 	if err != nil {
 		t.Fatalf("GetBuildDataFile returned error: %v", err)
 	}
@@ -61,7 +62,7 @@ func TestBuildDataService_ListAll(t *testing.T) {
 	})
 	mux.Handle(pathPrefix+"/", http.StripPrefix(pathPrefix, rwvfs.HTTPHandler(fs, nil)))
 
-	fs, err := client.BuildData.FileSystem(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
+	fs, err := client.BuildData.FileSystem(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"})
 	if err != nil {
 		t.Fatal(err)
 	}
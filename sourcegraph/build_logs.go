@@ -0,0 +1,177 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// LogFollowOptions specifies options for following a build or task
+// log with FollowBuildLog or FollowTaskLog.
+type LogFollowOptions struct {
+	// MinID indicates that only log entries whose ID is greater than
+	// MinID should be read (see BuildGetLogOptions.MinID). If empty,
+	// the whole log is read.
+	MinID string
+
+	// MinInterval and MaxInterval bound the interval between polls
+	// for new log entries. Polls are always at least MinInterval
+	// apart. The interval doubles after each poll that yields no new
+	// entries (up to MaxInterval), and it is reset to MinInterval
+	// when new entries are found. If zero, 500ms and 10s are used,
+	// respectively.
+	MinInterval, MaxInterval time.Duration
+}
+
+// A LogReader reads a build or task log as it is written, like "tail
+// -f". Each log entry is read as a single line (terminated by "\n").
+// Reads block until new entries are available, and return io.EOF
+// after all entries have been read and the build has ended.
+//
+// A LogReader polls the API only while it is being read, so it need
+// not be closed if it is read until io.EOF. Close may be called
+// concurrently with Read, to interrupt it.
+type LogReader struct {
+	ctx     context.Context
+	cancel  context.CancelFunc // cancels ctx (when closed or done)
+	builds  BuildsService
+	build   BuildSpec
+	getLog  func(ctx context.Context, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	opt     LogFollowOptions
+	minID   string
+	buf     bytes.Buffer
+	polled  bool          // whether a poll has been made
+	idle    bool          // whether the last poll yielded no new entries
+	wait    time.Duration // interval before the next poll
+	done    bool          // whether the build has ended and all entries have been read
+	closed  int32         // set (atomically) to 1 by Close
+	lastErr error
+}
+
+// FollowBuildLog returns a LogReader that reads the build's log until
+// the build ends.
+func FollowBuildLog(ctx context.Context, builds BuildsService, build BuildSpec, opt *LogFollowOptions) *LogReader {
+	return newLogReader(ctx, builds, build, opt, func(ctx context.Context, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+		return builds.GetLog(ctx, build, opt)
+	})
+}
+
+// FollowTaskLog returns a LogReader that reads the task's log until
+// the task's build ends.
+func FollowTaskLog(ctx context.Context, builds BuildsService, task TaskSpec, opt *LogFollowOptions) *LogReader {
+	return newLogReader(ctx, builds, task.BuildSpec, opt, func(ctx context.Context, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+		return builds.GetTaskLog(ctx, task, opt)
+	})
+}
+
+func newLogReader(ctx context.Context, builds BuildsService, build BuildSpec, opt *LogFollowOptions, getLog func(context.Context, *BuildGetLogOptions) (*LogEntries, Response, error)) *LogReader {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &LogReader{ctx: ctx, cancel: cancel, builds: builds, build: build, getLog: getLog}
+	if opt != nil {
+		r.opt = *opt
+	}
+	if r.opt.MinInterval <= 0 {
+		r.opt.MinInterval = 500 * time.Millisecond
+	}
+	if r.opt.MaxInterval <= 0 {
+		r.opt.MaxInterval = 10 * time.Second
+	}
+	r.minID = r.opt.MinID
+	r.wait = r.opt.MinInterval
+	return r
+}
+
+// errLogReaderClosed is returned by reads from a closed LogReader.
+var errLogReaderClosed = errors.New("read from closed LogReader")
+
+// Read implements io.Reader.
+func (r *LogReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if atomic.LoadInt32(&r.closed) != 0 {
+			return 0, errLogReaderClosed
+		}
+		if r.lastErr != nil {
+			return 0, r.lastErr
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.poll(); err != nil {
+			r.lastErr = err
+		}
+		if r.done || r.lastErr != nil {
+			r.cancel() // release ctx's resources
+		}
+	}
+	return r.buf.Read(p)
+}
+
+// MinID returns the ID of the last log entry that was fetched. It
+// can be used as LogFollowOptions.MinID to resume following the log
+// later.
+func (r *LogReader) MinID() string { return r.minID }
+
+// Close stops following the log, interrupting a Read that is in
+// progress. Subsequent reads return an error.
+func (r *LogReader) Close() error {
+	atomic.StoreInt32(&r.closed, 1)
+	r.cancel()
+	return nil
+}
+
+// poll waits (if a poll has already been made) and then fetches new
+// log entries. If the build has ended, all remaining log entries are
+// fetched and r.done is set.
+func (r *LogReader) poll() error {
+	if r.polled {
+		t := time.NewTimer(r.wait)
+		select {
+		case <-r.ctx.Done():
+			t.Stop()
+			return r.ctx.Err()
+		case <-t.C:
+		}
+	}
+	r.polled = true
+
+	var ended bool
+	if r.idle {
+		// Check whether the build has ended before fetching entries,
+		// so that no entries written before the build ended are
+		// missed.
+		build, _, err := r.builds.Get(r.ctx, r.build, nil)
+		if err != nil {
+			return err
+		}
+		ended = build.EndedAt.Valid
+	}
+
+	entries, _, err := r.getLog(r.ctx, &BuildGetLogOptions{MinID: r.minID})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries.Entries {
+		r.buf.WriteString(e)
+		r.buf.WriteByte('\n')
+	}
+	if entries.MaxID != "" {
+		r.minID = entries.MaxID
+	}
+
+	if len(entries.Entries) > 0 {
+		r.idle, r.wait = false, r.opt.MinInterval
+	} else {
+		if r.idle {
+			r.wait *= 2
+			if r.wait > r.opt.MaxInterval {
+				r.wait = r.opt.MaxInterval
+			}
+		}
+		r.idle = true
+	}
+	r.done = ended
+	return nil
+}
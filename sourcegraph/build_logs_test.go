@@ -0,0 +1,144 @@
+package sourcegraph
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+)
+
+func TestFollowBuildLog(t *testing.T) {
+	lines := []string{"a", "b"}
+	var ended bool
+	var getCalls int
+	var minIDs []string
+	builds := MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			getCalls++
+			switch getCalls {
+			case 1:
+				lines = append(lines, "c")
+			case 2:
+				lines = append(lines, "d")
+				ended = true
+			}
+			return &Build{BID: build.BID, EndedAt: db_common.NullTime{Valid: ended}}, nil, nil
+		},
+		GetLog_: func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			minIDs = append(minIDs, opt.MinID)
+			minID, _ := strconv.Atoi(opt.MinID)
+			return &LogEntries{MaxID: strconv.Itoa(len(lines)), Entries: lines[minID:]}, nil, nil
+		},
+	}
+
+	r := FollowBuildLog(context.Background(), builds, BuildSpec{BID: 1}, &LogFollowOptions{MinInterval: time.Millisecond})
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nb\nc\nd\n"; string(data) != want {
+		t.Errorf("got log %q, want %q", data, want)
+	}
+	if want := []string{"", "2", "2", "3", "3"}; !reflect.DeepEqual(minIDs, want) {
+		t.Errorf("got MinIDs %v, want %v", minIDs, want)
+	}
+	if want := "4"; r.MinID() != want {
+		t.Errorf("got MinID %q, want %q", r.MinID(), want)
+	}
+}
+
+func TestFollowTaskLog_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	builds := MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			cancel()
+			return &Build{BID: build.BID}, nil, nil
+		},
+		GetTaskLog_: func(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return &LogEntries{}, nil, nil
+		},
+	}
+
+	r := FollowTaskLog(ctx, builds, TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, &LogFollowOptions{MinInterval: time.Millisecond})
+	if _, err := ioutil.ReadAll(r); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLogReader_backoff(t *testing.T) {
+	builds := MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			return &Build{BID: build.BID}, nil, nil
+		},
+		GetLog_: func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return &LogEntries{}, nil, nil
+		},
+	}
+
+	r := FollowBuildLog(context.Background(), builds, BuildSpec{BID: 1}, &LogFollowOptions{MinInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond})
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		if err := r.poll(); err != nil {
+			t.Fatal(err)
+		}
+		waits = append(waits, r.wait)
+	}
+	if want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("got waits %v, want %v", waits, want)
+	}
+}
+
+func TestLogReader_minInterval(t *testing.T) {
+	var n int
+	builds := MockBuildsService{
+		GetLog_: func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			n++ // a chatty log, with new entries for every poll
+			return &LogEntries{MaxID: strconv.Itoa(n), Entries: []string{strconv.Itoa(n)}}, nil, nil
+		},
+	}
+
+	const minInterval = 20 * time.Millisecond
+	r := FollowBuildLog(context.Background(), builds, BuildSpec{BID: 1}, &LogFollowOptions{MinInterval: minInterval})
+	start := time.Now()
+	buf := make([]byte, 2)
+	for i := 0; i < 3; i++ {
+		if _, err := r.Read(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*minInterval {
+		t.Errorf("3 polls took %s, want at least %s", elapsed, 2*minInterval)
+	}
+}
+
+func TestLogReader_Close(t *testing.T) {
+	builds := MockBuildsService{
+		Get_: func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+			return &Build{BID: build.BID}, nil, nil
+		},
+		GetLog_: func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+			return &LogEntries{}, nil, nil
+		},
+	}
+
+	r := FollowBuildLog(context.Background(), builds, BuildSpec{BID: 1}, &LogFollowOptions{MinInterval: time.Hour})
+	done := make(chan error)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let Read block waiting for the next poll
+	r.Close()
+	select {
+	case err := <-done:
+		if err != errLogReaderClosed {
+			t.Errorf("got error %v, want %v", err, errLogReaderClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close didn't interrupt Read")
+	}
+}
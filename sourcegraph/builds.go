@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,34 +17,34 @@ import (
 // Sourcegraph API.
 type BuildsService interface {
 	// Get fetches a build.
-	Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
+	Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
 
 	// List builds.
-	List(opt *BuildListOptions) ([]*Build, Response, error)
+	List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error)
 
 	// Create a new build. The build will run asynchronously (Create does not
 	// wait for it to return. To monitor the build's status, use Get.)
-	Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
+	Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
 
 	// Update updates information about a build and returns the build
 	// after the update has been applied.
-	Update(build BuildSpec, info BuildUpdate) (*Build, Response, error)
+	Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error)
 
 	// ListBuildTasks lists the tasks associated with a build.
-	ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
+	ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
 
 	// CreateTasks creates tasks associated with a build and returns
 	// them with their TID fields set.
-	CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
+	CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
 
 	// UpdateTask updates a task associated with a build.
-	UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
+	UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
 
 	// GetLog gets log entries associated with a build.
-	GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
 
 	// GetTaskLog gets log entries associated with a task.
-	GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
 
 	// DequeueNext returns the next queued build and marks it as
 	// having started (atomically). It is not considered an error if
@@ -54,7 +55,7 @@ type BuildsService interface {
 	// permissions to build and upload build data for the build's
 	// repository. Call auth.SignedTicketStrings on the response's
 	// HTTP response field to obtain the tickets.
-	DequeueNext() (*Build, Response, error)
+	DequeueNext(ctx context.Context) (*Build, Response, error)
 }
 
 type buildsService struct {
@@ -224,6 +225,12 @@ type BuildTask struct {
 
 	// Failure is whether this task's execution failed.
 	Failure bool `json:",omitempty"`
+
+	// Killed is true if this task's execution was ended before it
+	// finished on its own (for example, because its build's worker
+	// was stopped). If Killed is true, then Failure must also always
+	// be set to true.
+	Killed bool `json:",omitempty"`
 }
 
 // Build task ops.
@@ -310,7 +317,7 @@ var ErrBuildNotFound = errors.New("build not found")
 
 type BuildGetOptions struct{}
 
-func (s *buildsService) Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+func (s *buildsService) Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
 	url, err := s.client.URL(router.Build, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -322,7 +329,7 @@ func (s *buildsService) Get(build BuildSpec, opt *BuildGetOptions) (*Build, Resp
 	}
 
 	var build_ *Build
-	resp, err := s.client.Do(req, &build_)
+	resp, err := s.client.Do(ctx, req, &build_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -348,7 +355,7 @@ type BuildListOptions struct {
 	ListOptions
 }
 
-func (s *buildsService) List(opt *BuildListOptions) ([]*Build, Response, error) {
+func (s *buildsService) List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error) {
 	url, err := s.client.URL(router.Builds, nil, opt)
 	if err != nil {
 		return nil, nil, err
@@ -360,7 +367,7 @@ func (s *buildsService) List(opt *BuildListOptions) ([]*Build, Response, error)
 	}
 
 	var builds []*Build
-	resp, err := s.client.Do(req, &builds)
+	resp, err := s.client.Do(ctx, req, &builds)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -368,7 +375,7 @@ func (s *buildsService) List(opt *BuildListOptions) ([]*Build, Response, error)
 	return builds, resp, nil
 }
 
-func (s *buildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
+func (s *buildsService) Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
 	url, err := s.client.URL(router.RepoBuildsCreate, repoRev.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -380,7 +387,7 @@ func (s *buildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*B
 	}
 
 	var build *Build
-	resp, err := s.client.Do(req, &build)
+	resp, err := s.client.Do(ctx, req, &build)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -390,7 +397,7 @@ func (s *buildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*B
 
 type BuildTaskListOptions struct{ ListOptions }
 
-func (s *buildsService) ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
+func (s *buildsService) ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTasks, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -402,7 +409,7 @@ func (s *buildsService) ListBuildTasks(build BuildSpec, opt *BuildTaskListOption
 	}
 
 	var tasks []*BuildTask
-	resp, err := s.client.Do(req, &tasks)
+	resp, err := s.client.Do(ctx, req, &tasks)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -424,7 +431,7 @@ type BuildUpdate struct {
 	Priority    *int
 }
 
-func (s *buildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Response, error) {
+func (s *buildsService) Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error) {
 	url, err := s.client.URL(router.BuildUpdate, build.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -436,7 +443,7 @@ func (s *buildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Respo
 	}
 
 	var updated *Build
-	resp, err := s.client.Do(req, &updated)
+	resp, err := s.client.Do(ctx, req, &updated)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -444,7 +451,7 @@ func (s *buildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Respo
 	return updated, resp, nil
 }
 
-func (s *buildsService) CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
+func (s *buildsService) CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTasksCreate, build.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -456,7 +463,7 @@ func (s *buildsService) CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*Bui
 	}
 
 	var created []*BuildTask
-	resp, err := s.client.Do(req, &created)
+	resp, err := s.client.Do(ctx, req, &created)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -471,9 +478,10 @@ type TaskUpdate struct {
 	EndedAt   *time.Time
 	Success   *bool
 	Failure   *bool
+	Killed    *bool
 }
 
-func (s *buildsService) UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
+func (s *buildsService) UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
 	url, err := s.client.URL(router.BuildTaskUpdate, task.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -485,7 +493,7 @@ func (s *buildsService) UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask,
 	}
 
 	var updated *BuildTask
-	resp, err := s.client.Do(req, &updated)
+	resp, err := s.client.Do(ctx, req, &updated)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -499,7 +507,8 @@ type BuildGetLogOptions struct {
 	// is greater than MinID should be returned.
 	//
 	// To "tail -f" or watch a log for updates, set each subsequent request's
-	// MinID to the MaxID of the previous request.
+	// MinID to the MaxID of the previous request. (FollowBuildLog and
+	// FollowTaskLog do this.)
 	MinID string
 }
 
@@ -508,7 +517,7 @@ type LogEntries struct {
 	Entries []string
 }
 
-func (s *buildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+func (s *buildsService) GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
 	url, err := s.client.URL(router.BuildLog, build.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -520,7 +529,7 @@ func (s *buildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEn
 	}
 
 	var entries *LogEntries
-	resp, err := s.client.Do(req, &entries)
+	resp, err := s.client.Do(ctx, req, &entries)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -528,7 +537,7 @@ func (s *buildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEn
 	return entries, resp, nil
 }
 
-func (s *buildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+func (s *buildsService) GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
 	url, err := s.client.URL(router.BuildTaskLog, task.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -540,7 +549,7 @@ func (s *buildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*Log
 	}
 
 	var entries *LogEntries
-	resp, err := s.client.Do(req, &entries)
+	resp, err := s.client.Do(ctx, req, &entries)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -548,7 +557,7 @@ func (s *buildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*Log
 	return entries, resp, nil
 }
 
-func (s *buildsService) DequeueNext() (*Build, Response, error) {
+func (s *buildsService) DequeueNext(ctx context.Context) (*Build, Response, error) {
 	url, err := s.client.URL(router.BuildDequeueNext, nil, nil)
 	if err != nil {
 		return nil, nil, err
@@ -560,7 +569,7 @@ func (s *buildsService) DequeueNext() (*Build, Response, error) {
 	}
 
 	var build_ *Build
-	resp, err := s.client.Do(req, &build_)
+	resp, err := s.client.Do(ctx, req, &build_)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
 			return nil, resp, nil
@@ -1,52 +1,56 @@
 package sourcegraph
 
+import "context"
+
 type MockBuildsService struct {
-	Get_            func(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
-	List_           func(opt *BuildListOptions) ([]*Build, Response, error)
-	Create_         func(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
-	Update_         func(build BuildSpec, info BuildUpdate) (*Build, Response, error)
-	ListBuildTasks_ func(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
-	CreateTasks_    func(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
-	UpdateTask_     func(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
-	GetLog_         func(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
-	GetTaskLog_     func(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
-	DequeueNext_    func() (*Build, Response, error)
+	Get_            func(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error)
+	List_           func(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error)
+	Create_         func(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error)
+	Update_         func(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error)
+	ListBuildTasks_ func(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error)
+	CreateTasks_    func(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error)
+	UpdateTask_     func(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error)
+	GetLog_         func(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	GetTaskLog_     func(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error)
+	DequeueNext_    func(ctx context.Context) (*Build, Response, error)
 }
 
-func (s MockBuildsService) Get(build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
-	return s.Get_(build, opt)
+func (s MockBuildsService) Get(ctx context.Context, build BuildSpec, opt *BuildGetOptions) (*Build, Response, error) {
+	return s.Get_(ctx, build, opt)
 }
 
-func (s MockBuildsService) List(opt *BuildListOptions) ([]*Build, Response, error) {
-	return s.List_(opt)
+func (s MockBuildsService) List(ctx context.Context, opt *BuildListOptions) ([]*Build, Response, error) {
+	return s.List_(ctx, opt)
 }
 
-func (s MockBuildsService) Create(repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
-	return s.Create_(repoRev, opt)
+func (s MockBuildsService) Create(ctx context.Context, repoRev RepoRevSpec, opt *BuildCreateOptions) (*Build, Response, error) {
+	return s.Create_(ctx, repoRev, opt)
 }
 
-func (s MockBuildsService) Update(build BuildSpec, info BuildUpdate) (*Build, Response, error) {
-	return s.Update_(build, info)
+func (s MockBuildsService) Update(ctx context.Context, build BuildSpec, info BuildUpdate) (*Build, Response, error) {
+	return s.Update_(ctx, build, info)
 }
 
-func (s MockBuildsService) ListBuildTasks(build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
-	return s.ListBuildTasks_(build, opt)
+func (s MockBuildsService) ListBuildTasks(ctx context.Context, build BuildSpec, opt *BuildTaskListOptions) ([]*BuildTask, Response, error) {
+	return s.ListBuildTasks_(ctx, build, opt)
 }
 
-func (s MockBuildsService) CreateTasks(build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
-	return s.CreateTasks_(build, tasks)
+func (s MockBuildsService) CreateTasks(ctx context.Context, build BuildSpec, tasks []*BuildTask) ([]*BuildTask, Response, error) {
+	return s.CreateTasks_(ctx, build, tasks)
 }
 
-func (s MockBuildsService) UpdateTask(task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
-	return s.UpdateTask_(task, info)
+func (s MockBuildsService) UpdateTask(ctx context.Context, task TaskSpec, info TaskUpdate) (*BuildTask, Response, error) {
+	return s.UpdateTask_(ctx, task, info)
 }
 
-func (s MockBuildsService) GetLog(build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
-	return s.GetLog_(build, opt)
+func (s MockBuildsService) GetLog(ctx context.Context, build BuildSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+	return s.GetLog_(ctx, build, opt)
 }
 
-func (s MockBuildsService) GetTaskLog(task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
-	return s.GetTaskLog_(task, opt)
+func (s MockBuildsService) GetTaskLog(ctx context.Context, task TaskSpec, opt *BuildGetLogOptions) (*LogEntries, Response, error) {
+	return s.GetTaskLog_(ctx, task, opt)
 }
 
-func (s MockBuildsService) DequeueNext() (*Build, Response, error) { return s.DequeueNext_() }
+func (s MockBuildsService) DequeueNext(ctx context.Context) (*Build, Response, error) {
+	return s.DequeueNext_(ctx)
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -23,7 +24,7 @@ func TestBuildsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.Get(BuildSpec{BID: 1}, nil)
+	build, _, err := client.Builds.Get(context.Background(), BuildSpec{BID: 1}, nil)
 	if err != nil {
 		t.Errorf("Builds.Get returned error: %v", err)
 	}
@@ -52,7 +53,7 @@ func TestBuildsService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	builds, _, err := client.Builds.List(nil)
+	builds, _, err := client.Builds.List(context.Background(), nil)
 	if err != nil {
 		t.Errorf("Builds.List returned error: %v", err)
 	}
@@ -84,7 +85,7 @@ func TestBuildsService_Create(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build_, _, err := client.Builds.Create(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, config)
+	build_, _, err := client.Builds.Create(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "c"}, config)
 	if err != nil {
 		t.Errorf("Builds.Create returned error: %v", err)
 	}
@@ -116,7 +117,7 @@ func TestBuildsService_Update(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.Update(BuildSpec{BID: 123}, update)
+	build, _, err := client.Builds.Update(context.Background(), BuildSpec{BID: 123}, update)
 	if err != nil {
 		t.Errorf("Builds.Update returned error: %v", err)
 	}
@@ -143,12 +144,12 @@ func TestBuildsService_UpdateTask(t *testing.T) {
 	mux.HandleFunc(urlPath(t, router.BuildTaskUpdate, map[string]string{"BID": "123", "TaskID": "456"}), func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		testMethod(t, r, "PUT")
-		testBody(t, r, `{"StartedAt":null,"EndedAt":null,"Success":true,"Failure":null}`+"\n")
+		testBody(t, r, `{"StartedAt":null,"EndedAt":null,"Success":true,"Failure":null,"Killed":null}`+"\n")
 
 		writeJSON(w, want)
 	})
 
-	task, _, err := client.Builds.UpdateTask(TaskSpec{BuildSpec: BuildSpec{BID: 123}, TaskID: 456}, update)
+	task, _, err := client.Builds.UpdateTask(context.Background(), TaskSpec{BuildSpec: BuildSpec{BID: 123}, TaskID: 456}, update)
 	if err != nil {
 		t.Errorf("Builds.UpdateTask returned error: %v", err)
 	}
@@ -178,7 +179,7 @@ func TestBuildsService_CreateTasks(t *testing.T) {
 		writeJSON(w, create)
 	})
 
-	tasks, _, err := client.Builds.CreateTasks(BuildSpec{BID: 123}, create)
+	tasks, _, err := client.Builds.CreateTasks(context.Background(), BuildSpec{BID: 123}, create)
 	if err != nil {
 		t.Errorf("Builds.CreateTasks returned error: %v", err)
 	}
@@ -205,7 +206,7 @@ func TestBuildsService_GetLog(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	entries, _, err := client.Builds.GetLog(BuildSpec{BID: 1}, nil)
+	entries, _, err := client.Builds.GetLog(context.Background(), BuildSpec{BID: 1}, nil)
 	if err != nil {
 		t.Errorf("Builds.GetLog returned error: %v", err)
 	}
@@ -233,7 +234,7 @@ func TestBuildsService_GetTaskLog(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	entries, _, err := client.Builds.GetTaskLog(TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, nil)
+	entries, _, err := client.Builds.GetTaskLog(context.Background(), TaskSpec{BuildSpec: BuildSpec{BID: 1}, TaskID: 2}, nil)
 	if err != nil {
 		t.Errorf("Builds.GetTaskLog returned error: %v", err)
 	}
@@ -261,7 +262,7 @@ func TestBuildsService_DequeueNext(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	build, _, err := client.Builds.DequeueNext()
+	build, _, err := client.Builds.DequeueNext(context.Background())
 	if err != nil {
 		t.Errorf("Builds.DequeueNext returned error: %v", err)
 	}
@@ -287,7 +288,7 @@ func TestBuildsService_DequeueNext_emptyQueue(t *testing.T) {
 		w.WriteHeader(http.StatusNotFound)
 	})
 
-	build, _, err := client.Builds.DequeueNext()
+	build, _, err := client.Builds.DequeueNext(context.Background())
 	if err != nil {
 		t.Errorf("Builds.DequeueNext returned error: %v", err)
 	}
@@ -0,0 +1,290 @@
+package sourcegraph
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// A ResponseCache stores the HTTP responses that a Client caches (see
+// Client.Cache), keyed by request URL. Each response is stored in
+// HTTP/1.1 wire format. Implementations must be safe for concurrent
+// use. Because caching is only an optimization, they should not fail,
+// but should act as though a response was never stored instead.
+type ResponseCache interface {
+	// Get returns the response stored under key, if any.
+	Get(key string) (resp []byte, ok bool)
+
+	// Set stores resp under key.
+	Set(key string, resp []byte)
+
+	// Delete removes the response stored under key, if any.
+	Delete(key string)
+}
+
+// CacheStatus describes how a Client's cache (see Client.Cache) was
+// used to obtain a response.
+type CacheStatus string
+
+const (
+	// CacheMiss means that the response was fetched from the API
+	// (and stored in the cache, if it is cacheable).
+	CacheMiss CacheStatus = "miss"
+
+	// CacheHit means that the response was served from the cache
+	// without a request to the API, because it can't have changed.
+	CacheHit CacheStatus = "hit"
+
+	// CacheRevalidated means that the response was served from the
+	// cache after the API confirmed (with HTTP 304 Not Modified) that
+	// it hadn't changed.
+	CacheRevalidated CacheStatus = "revalidated"
+)
+
+// immutableRoutes are the routes whose responses are determined by
+// the commit that they specify. Responses from these routes for a
+// full commit ID never change.
+var immutableRoutes = map[string]bool{
+	router.RepoCommit:         true,
+	router.RepoTreeEntry:      true,
+	router.RepoBuildDataEntry: true,
+	router.Def:                true,
+	router.Unit:               true,
+}
+
+// sendCached sends req, using c.Cache (if set) for GET requests.
+func (c *Client) sendCached(req *http.Request) (*http.Response, CacheStatus, error) {
+	if c.Cache == nil || req.Method != "GET" || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		resp, err := c.send(req)
+		return resp, "", err
+	}
+
+	key := req.URL.String()
+	immutable := c.isImmutable(req)
+	cached := c.cachedResponse(key, req)
+	if cached != nil && immutable {
+		return cached, CacheHit, nil
+	}
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return resp, "", err
+	}
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		// Drain the body so the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		return cached, CacheRevalidated, nil
+	}
+
+	// The fresh response supersedes any cached one, so if it isn't
+	// stored, the stale one must not be served (or revalidated) later.
+	stored := false
+	if resp.StatusCode == http.StatusOK && isCacheable(resp, immutable) {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			c.Cache.Delete(key)
+			return nil, CacheMiss, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if b, err := httputil.DumpResponse(resp, true); err == nil {
+			c.Cache.Set(key, b)
+			stored = true
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if !stored {
+		c.Cache.Delete(key)
+	}
+	return resp, CacheMiss, nil
+}
+
+// cachedResponse returns the response to req stored in c.Cache under
+// key, or nil if there is none (or it can't be read).
+func (c *Client) cachedResponse(key string, req *http.Request) *http.Response {
+	b, ok := c.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		c.Cache.Delete(key)
+		return nil
+	}
+	return resp
+}
+
+// isImmutable returns whether the response to req never changes,
+// because req is for a full commit ID on one of immutableRoutes.
+// Requests for def stats are not immutable, because the stats change
+// as other repositories are built.
+func (c *Client) isImmutable(req *http.Request) bool {
+	name, vars := c.matchRoute(req)
+	if !immutableRoutes[name] || req.URL.Query().Get("Stats") != "" {
+		return false
+	}
+	repoRev, err := UnmarshalRepoRevSpec(vars)
+	if err != nil {
+		return false
+	}
+	commitID := repoRev.CommitID
+	if commitID == "" {
+		commitID = repoRev.Rev
+	}
+	return isAbsCommitID(commitID)
+}
+
+// isAbsCommitID returns whether s is a full (40-character hex) commit
+// ID.
+func isAbsCommitID(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isCacheable returns whether resp may be stored in a cache. It may
+// be if it is immutable or can be revalidated, unless the server
+// forbids storing it.
+func isCacheable(resp *http.Response, immutable bool) bool {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	return immutable || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// NewMemoryCache returns a ResponseCache that holds up to maxEntries
+// responses in memory, evicting the least recently used ones.
+func NewMemoryCache(maxEntries int) ResponseCache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		elems:      map[string]*list.Element{},
+	}
+}
+
+type memoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries *list.List // of *memoryCacheEntry, most recently used first
+	elems   map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key  string
+	resp []byte
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.elems[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).resp, true
+}
+
+func (c *memoryCache) Set(key string, resp []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elems[key]; ok {
+		e.Value.(*memoryCacheEntry).resp = resp
+		c.entries.MoveToFront(e)
+		return
+	}
+	c.elems[key] = c.entries.PushFront(&memoryCacheEntry{key: key, resp: resp})
+	for c.entries.Len() > c.maxEntries {
+		e := c.entries.Back()
+		c.entries.Remove(e)
+		delete(c.elems, e.Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (c *memoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elems[key]; ok {
+		c.entries.Remove(e)
+		delete(c.elems, key)
+	}
+}
+
+// NewDiskCache returns a ResponseCache that stores responses in
+// files in dir (which is created if it doesn't exist). Entries are
+// never evicted.
+func NewDiskCache(dir string) ResponseCache {
+	return diskCache(dir)
+}
+
+type diskCache string
+
+// path returns the path of the file that stores the response for
+// key.
+func (c diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(string(c), hex.EncodeToString(sum[:]))
+}
+
+func (c diskCache) Get(key string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func (c diskCache) Set(key string, resp []byte) {
+	if err := os.MkdirAll(string(c), 0700); err != nil {
+		return
+	}
+	// Write to a temporary file and rename it, so that readers never
+	// see a partially written response.
+	f, err := ioutil.TempFile(string(c), "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(resp)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func (c diskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
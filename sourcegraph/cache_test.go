@@ -0,0 +1,203 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestClient_Cache_immutable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	commitID := "0123456789012345678901234567890123456789"
+	var calls int
+	mux.HandleFunc(urlPath(t, router.RepoTreeEntry, map[string]string{"RepoSpec": "r.com/x", "Rev": "master===" + commitID, "Path": "p"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeJSON(w, &TreeEntry{})
+	})
+
+	client.Cache = NewMemoryCache(10)
+	entry := TreeEntrySpec{RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "master", CommitID: commitID}, Path: "p"}
+	for _, want := range []CacheStatus{CacheMiss, CacheHit, CacheHit} {
+		e, resp, err := client.RepoTree.Get(context.Background(), entry, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e == nil {
+			t.Error("got nil entry")
+		}
+		if status := resp.(*HTTPResponse).CacheStatus; status != want {
+			t.Errorf("got cache status %q, want %q", status, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestClient_Cache_revalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	etag := `"1"`
+	var calls, notModified int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		writeJSON(w, &Repo{URI: "r.com/x", Description: etag})
+	})
+
+	client.Cache = NewMemoryCache(10)
+	get := func(wantStatus CacheStatus, wantDescription string) {
+		repo, resp, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.Description != wantDescription {
+			t.Errorf("got repo description %q, want %q", repo.Description, wantDescription)
+		}
+		if status := resp.(*HTTPResponse).CacheStatus; status != wantStatus {
+			t.Errorf("got cache status %q, want %q", status, wantStatus)
+		}
+	}
+	get(CacheMiss, `"1"`)
+	get(CacheRevalidated, `"1"`)
+	etag = `"2"`
+	get(CacheMiss, `"2"`)
+	get(CacheRevalidated, `"2"`)
+	if calls != 4 || notModified != 2 {
+		t.Errorf("got %d calls (%d not modified), want 4 (2 not modified)", calls, notModified)
+	}
+}
+
+func TestClient_Cache_uncacheable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("got conditional request for uncacheable response")
+		}
+		if calls == 1 {
+			// Has a validator, but must not be stored.
+			w.Header().Set("ETag", `"1"`)
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	client.Cache = NewMemoryCache(10)
+	for i := 0; i < 3; i++ {
+		_, resp, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := resp.(*HTTPResponse).CacheStatus; status != CacheMiss {
+			t.Errorf("got cache status %q, want %q", status, CacheMiss)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestClient_Cache_evictStale(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var status int
+	var etag string
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		if etag != "" {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		}
+		if status != http.StatusOK {
+			http.Error(w, "not found", status)
+			return
+		}
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	cache := NewMemoryCache(10).(*memoryCache)
+	client.Cache = cache
+	get := func() {
+		_, resp, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+		if err != nil && !IsHTTPErrorCode(err, status) {
+			t.Fatal(err)
+		}
+		if s := resp.(*HTTPResponse).CacheStatus; s != CacheMiss {
+			t.Errorf("got cache status %q, want %q", s, CacheMiss)
+		}
+	}
+
+	// A fresh response that isn't stored replaces the cached one, so
+	// the cached one must be removed.
+	for _, fresh := range []struct {
+		status int
+		etag   string
+	}{
+		{http.StatusOK, ""},          // no validator
+		{http.StatusNotFound, ""},    // error
+		{http.StatusNotFound, `"2"`}, // error with validator
+	} {
+		status, etag = http.StatusOK, `"1"`
+		get()
+		if n := cache.entries.Len(); n != 1 {
+			t.Fatalf("got %d cached responses, want 1", n)
+		}
+		status, etag = fresh.status, fresh.etag
+		get()
+		if n := cache.entries.Len(); n != 0 {
+			t.Errorf("HTTP %d, ETag %q: got %d cached responses, want 0 (stale response not removed)", fresh.status, fresh.etag, n)
+		}
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Get("a")              // make b the least recently used
+	c.Set("c", []byte("3")) // evicts b
+	c.Set("a", []byte("4"))
+	c.Delete("c")
+
+	for key, want := range map[string]string{"a": "4", "b": "", "c": ""} {
+		b, ok := c.Get(key)
+		if ok != (want != "") || string(b) != want {
+			t.Errorf("%s: got %q (present: %v), want %q", key, b, ok, want)
+		}
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	c := NewDiskCache(t.TempDir() + "/cache")
+	if _, ok := c.Get("a"); ok {
+		t.Error("got response from empty cache")
+	}
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Set("a", []byte("3"))
+	c.Delete("b")
+
+	for key, want := range map[string]string{"a": "3", "b": ""} {
+		b, ok := c.Get(key)
+		if ok != (want != "") || string(b) != want {
+			t.Errorf("%s: got %q (present: %v), want %q", key, b, ok, want)
+		}
+	}
+}
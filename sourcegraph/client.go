@@ -3,6 +3,7 @@ package sourcegraph
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/google/go-querystring/query"
+	muxpkg "github.com/sourcegraph/mux"
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 )
 
@@ -38,6 +40,7 @@ type Client struct {
 	Users        UsersService
 	Defs         DefsService
 	Markdown     MarkdownService
+	Snippets     SnippetsService
 
 	// Base URL for API requests, which should have a trailing slash.
 	BaseURL *url.URL
@@ -45,6 +48,51 @@ type Client struct {
 	// User agent used for HTTP requests to the Sourcegraph API.
 	UserAgent string
 
+	// RetryPolicy, if non-nil, specifies how requests that fail with
+	// transient errors are retried. If nil, requests are not retried.
+	RetryPolicy *RetryPolicy
+
+	// FollowRepoRenames is whether requests to repository routes that
+	// fail because the repository was renamed (ErrRenamed) or exists
+	// at another URI (ErrRedirect) are automatically retried against
+	// the repository's new URI. The new URI is remembered, so that
+	// later requests for the old URI go directly to the new one.
+	//
+	// When a request is redirected, the canonical RepoSpec is
+	// reported in the HTTPResponse's CanonicalRepo field.
+	FollowRepoRenames bool
+
+	// Cache, if non-nil, caches the responses to GET requests.
+	// Responses for a full commit ID from routes whose results are
+	// determined by the commit (commits, tree entries, build data
+	// files, defs, and units) are then served from the cache without
+	// a request. Other responses that have an ETag or Last-Modified
+	// header are revalidated with a conditional request
+	// (If-None-Match or If-Modified-Since), and served from the cache
+	// if they haven't changed. The HTTPResponse's CacheStatus reports
+	// how each response was obtained.
+	//
+	// The cache is keyed by URL, so it must not be shared by clients
+	// that use different credentials.
+	Cache ResponseCache
+
+	// Middleware intercepts the requests that Do sends, in order: the
+	// first middleware is called first, and its response is returned
+	// last. Each middleware is told the request's route name, route
+	// variables, and options (see RequestInfo), so it can log or
+	// measure requests by route. This package provides LogRequests,
+	// LatencyHistogram, and PropagateTraceID.
+	//
+	// Middleware sees each request once, including any retries (see
+	// RetryPolicy) and cache lookups (see Cache), except that
+	// requests that follow a repository rename (see
+	// FollowRepoRenames) are separate requests.
+	Middleware []Middleware
+
+	// repoAliases caches repository renames and redirects (if
+	// FollowRepoRenames is true).
+	repoAliases repoAliasCache
+
 	// HTTP client used to communicate with the Sourcegraph API.
 	httpClient *http.Client
 }
@@ -73,6 +121,7 @@ func NewClient(httpClient *http.Client) *Client {
 	c.Users = &usersService{c}
 	c.Defs = &defsService{c}
 	c.Markdown = &markdownService{c}
+	c.Snippets = &snippetsService{c}
 
 	c.BaseURL = &url.URL{Scheme: "https", Host: "sourcegraph.com", Path: "/api/"}
 
@@ -81,6 +130,42 @@ func NewClient(httpClient *http.Client) *Client {
 	return c
 }
 
+// WithTransport returns a new Client with the same configuration as c
+// (BaseURL, UserAgent, RetryPolicy, FollowRepoRenames, Cache, and
+// Middleware) and the same HTTP client settings, except that its
+// requests are sent using the transport returned by wrap. The
+// transport of c's HTTP client (or http.DefaultTransport, if it has
+// none) is passed to wrap, so that the new Client can add to c's
+// credentials (e.g., with an auth.TicketAuthedTransport).
+//
+// The new Client's services communicate with the API; services that
+// were replaced on c (such as mocks) are not copied. It shares c's
+// Cache, so if the new transport's credentials grant access to
+// resources that c can't access, set its Cache to nil (or a separate
+// cache).
+func (c *Client) WithTransport(wrap func(http.RoundTripper) http.RoundTripper) *Client {
+	var hc http.Client
+	if c.httpClient != nil {
+		hc = *c.httpClient
+	} else {
+		hc = *http.DefaultClient
+	}
+	t := hc.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	hc.Transport = wrap(t)
+
+	c2 := NewClient(&hc)
+	c2.BaseURL = c.BaseURL
+	c2.UserAgent = c.UserAgent
+	c2.RetryPolicy = c.RetryPolicy
+	c2.FollowRepoRenames = c.FollowRepoRenames
+	c2.Cache = c.Cache
+	c2.Middleware = c.Middleware
+	return c2
+}
+
 // Router is used to generate URLs for the Sourcegraph API.
 var Router = router.NewAPIRouter(nil)
 
@@ -140,6 +225,32 @@ func (c *Client) URL(route string, routeVars map[string]string, opt interface{})
 	return url, nil
 }
 
+// routeName returns the name of the API route that req's URL
+// matches, or the empty string if there is no such route.
+func (c *Client) routeName(req *http.Request) string {
+	name, _ := c.matchRoute(req)
+	return name
+}
+
+// matchRoute returns the name and route variables of the API route
+// that req's URL matches. If there is no such route, the name is
+// empty.
+func (c *Client) matchRoute(req *http.Request) (name string, vars map[string]string) {
+	// Router's routes are relative to the API root, so make the URL
+	// path relative to BaseURL.
+	url := *req.URL
+	url.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(url.Path, c.BaseURL.Path), "/")
+	url.RawPath = ""
+	req2 := *req
+	req2.URL = &url
+
+	var match muxpkg.RouteMatch
+	if Router.Match(&req2, &match) && match.Route != nil {
+		return match.Route.GetName(), match.Vars
+	}
+	return "", nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client. Relative
 // URLs should always be specified without a preceding slash. If specified, the
@@ -177,6 +288,17 @@ func newResponse(r *http.Response) *HTTPResponse {
 // implements Response.
 type HTTPResponse struct {
 	*http.Response
+
+	// CanonicalRepo, if non-nil, is the repository that the request
+	// was redirected to because the requested repository was renamed
+	// or exists at another URI. It is only set if the Client's
+	// FollowRepoRenames field is true.
+	CanonicalRepo *RepoSpec
+
+	// CacheStatus is how the response was obtained using the Client's
+	// Cache. It is empty if the Client has no Cache or the request
+	// was not a GET request.
+	CacheStatus CacheStatus
 }
 
 // TotalCount implements Response.
@@ -246,24 +368,27 @@ const preserveBody doKey = iota // when passed as v to (*Client).Do, the resp bo
 // returned as an error if an API error has occurred. If v is
 // preserveBody, then the HTTP response body is not closed by Do; the
 // caller is responsible for closing it.
-func (c *Client) Do(req *http.Request, v interface{}) (*HTTPResponse, error) {
-	var resp *HTTPResponse
-	rawResp, err := c.httpClient.Do(req)
-	if rawResp != nil {
-		if v != preserveBody && rawResp.Body != nil {
-			defer rawResp.Body.Close()
-		}
-		resp = newResponse(rawResp)
-		if err == nil {
-			// Don't clobber error from Do, if any (it could be, e.g.,
-			// a sentinel error returned by the HTTP client's
-			// CheckRedirect func).
-			if err := CheckResponse(rawResp); err != nil {
-				// even though there was an error, we still return the response
-				// in case the caller wants to inspect it further
-				return resp, err
-			}
-		}
+//
+// The request is bound to ctx: if ctx is canceled or its deadline
+// is exceeded before the response is received, the request is
+// aborted and ctx's error is returned.
+//
+// If c.FollowRepoRenames is true, requests to repository routes
+// follow repository renames and redirects (see FollowRepoRenames).
+// If c.Cache is set, GET requests use it (see Cache). The request
+// and its response pass through c.Middleware, if any.
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*HTTPResponse, error) {
+	if c.FollowRepoRenames {
+		return c.doFollowingRepoRenames(ctx, req, v)
+	}
+	return c.do(ctx, req, v)
+}
+
+// do implements Do (without following repository renames).
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*HTTPResponse, error) {
+	resp, err := c.intercept(ctx, req, c.roundTrip)
+	if resp != nil && resp.Body != nil && v != preserveBody {
+		defer resp.Body.Close()
 	}
 	if err != nil {
 		return resp, err
@@ -271,9 +396,9 @@ func (c *Client) Do(req *http.Request, v interface{}) (*HTTPResponse, error) {
 
 	if v != nil {
 		if bp, ok := v.(*[]byte); ok {
-			*bp, err = ioutil.ReadAll(rawResp.Body)
+			*bp, err = ioutil.ReadAll(resp.Body)
 		} else if v != preserveBody {
-			err = json.NewDecoder(rawResp.Body).Decode(v)
+			err = json.NewDecoder(resp.Body).Decode(v)
 		}
 	}
 	if err != nil {
@@ -282,6 +407,42 @@ func (c *Client) Do(req *http.Request, v interface{}) (*HTTPResponse, error) {
 	return resp, nil
 }
 
+// roundTrip sends req (using c.Cache, if set) and returns the API
+// response, or an error if an API error has occurred. It is the
+// innermost Sender of c.Middleware.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request) (*HTTPResponse, error) {
+	req = req.WithContext(ctx)
+
+	var resp *HTTPResponse
+	rawResp, cacheStatus, err := c.sendCached(req)
+	if err != nil {
+		// If the context was canceled or timed out, its error is
+		// more informative than the (wrapped) one from the HTTP
+		// client.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+	}
+	if rawResp != nil {
+		resp = newResponse(rawResp)
+		resp.CacheStatus = cacheStatus
+		if err == nil {
+			// Don't clobber error from Do, if any (it could be, e.g.,
+			// a sentinel error returned by the HTTP client's
+			// CheckRedirect func).
+			if err := CheckResponse(rawResp); err != nil {
+				if e, ok := err.(*ErrorResponse); ok && e.Err == nil {
+					e.Err = errorFromStatus(c.routeName(rawResp.Request), rawResp.StatusCode)
+				}
+				// even though there was an error, we still return the response
+				// in case the caller wants to inspect it further
+				return resp, err
+			}
+		}
+	}
+	return resp, err
+}
+
 // addOptions adds the parameters in opt as URL query parameters to u. opt
 // must be a struct whose fields may contain "url" tags.
 func addOptions(u *url.URL, opt interface{}) error {
@@ -315,5 +476,6 @@ func NewMockClient() *Client {
 		Units:        &MockUnitsService{},
 		Users:        &MockUsersService{},
 		Defs:         &MockDefsService{},
+		Snippets:     &MockSnippetsService{},
 	}
 }
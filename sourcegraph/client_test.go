@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -93,3 +94,28 @@ func testBody(t *testing.T, r *http.Request, want string) {
 func normalizeTime(tm *time.Time) {
 	*tm = tm.In(time.UTC)
 }
+
+func TestDo_canceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var called bool
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req, err := client.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.Do(ctx, req, nil); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if called {
+		t.Error("request was sent despite canceled context")
+	}
+}
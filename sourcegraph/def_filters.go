@@ -0,0 +1,273 @@
+package sourcegraph
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/srclib/graph"
+	"sourcegraph.com/sourcegraph/srclib/store"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
+
+// An InvalidOptionsError describes list options that can't be
+// evaluated (see DefListOptions.Validate). The API responds to them
+// with HTTP 400 Bad Request.
+type InvalidOptionsError struct{ Msg string }
+
+func (e *InvalidOptionsError) Error() string { return e.Msg }
+
+// Validate returns an *InvalidOptionsError if o can't be translated
+// into the filters that DefFilters returns: if one of its RepoRevs
+// has a revision that isn't an absolute commit ID (because only the
+// API can resolve other revisions), or if its Sort or Direction is
+// not one that the API accepts.
+func (o *DefListOptions) Validate() error {
+	for i, repoRev := range o.RepoRevs {
+		if _, commitID := ParseRepoAndCommitID(repoRev); commitID != "" && len(commitID) != 40 {
+			return &InvalidOptionsError{fmt.Sprintf("DefListOptions: RepoRevs[%d] %q must have an absolute commit ID or no revision", i, repoRev)}
+		}
+	}
+	switch o.Direction {
+	case "", "asc", "desc":
+	default:
+		return &InvalidOptionsError{fmt.Sprintf("DefListOptions: invalid Direction %q (must be asc or desc)", o.Direction)}
+	}
+	switch o.Sort {
+	case "", "key", "name":
+	default:
+		return &InvalidOptionsError{fmt.Sprintf("DefListOptions: invalid Sort %q (must be key or name)", o.Sort)}
+	}
+	return nil
+}
+
+// DefFilters returns the srclib store filters that select the defs
+// that match o, in the order that DefsService.List returns them. The
+// order is given by the filter that implements DefsSorter (if any);
+// see SortDefs.
+//
+// RepoRevs with no revision match defs at any commit. UnitType and
+// Unit each filter on their own, so UnitType alone filters by
+// language. FilePathPrefix is cleaned (with path.Clean) and then
+// matched as a prefix of the defs' file paths, as the API does, so
+// "a/" matches the files "a/b.go" and "ab/c.go". As in the API, Sort
+// and Direction are ignored if Query is set (query results are
+// ordered by relevance).
+//
+// Options that Validate rejects select no defs (RepoRevs) or are
+// ignored (Sort and Direction), so callers should call Validate
+// first.
+func (o *DefListOptions) DefFilters() []store.DefFilter {
+	var fs []store.DefFilter
+	if o.Name != "" {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return def.Name == o.Name
+		}))
+	}
+	if o.Query != "" {
+		if o.Fuzzy {
+			fs = append(fs, ByDefFuzzyQuery(o.Query))
+		} else {
+			fs = append(fs, store.ByDefQuery(o.Query))
+		}
+	}
+	if len(o.RepoRevs) > 0 {
+		fs = append(fs, repoRevsFilter(o.RepoRevs))
+	}
+	switch {
+	case o.UnitType != "" && o.Unit != "":
+		fs = append(fs, store.ByUnits(unit.ID2{Type: o.UnitType, Name: o.Unit}))
+	case o.UnitType != "":
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return def.UnitType == o.UnitType
+		}))
+	case o.Unit != "":
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return def.Unit == o.Unit
+		}))
+	}
+	if o.Path != "" {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return def.Path == o.Path
+		}))
+	}
+	if o.File != "" {
+		fs = append(fs, store.ByFiles(path.Clean(o.File)))
+	}
+	if o.FilePathPrefix != "" {
+		prefix := path.Clean(o.FilePathPrefix)
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return strings.HasPrefix(def.File, prefix)
+		}))
+	}
+	if len(o.Kinds) > 0 {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			for _, kind := range o.Kinds {
+				if def.Kind == kind {
+					return true
+				}
+			}
+			return false
+		}))
+	}
+	if o.Exported {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return def.Exported
+		}))
+	}
+	if o.Nonlocal {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return !def.Local
+		}))
+	}
+	if !o.IncludeTest {
+		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
+			return !def.Test
+		}))
+	}
+
+	if o.Query == "" && (o.Sort == "key" || o.Sort == "name") {
+		fs = append(fs, DefsSortBy{Field: o.Sort, Desc: o.Direction == "desc"})
+	}
+	return fs
+}
+
+// repoRevsFilter returns a filter that selects defs in any of the
+// given repository revisions (see DefListOptions.DefFilters).
+func repoRevsFilter(repoRevs []string) store.DefFilter {
+	vs := make([]store.Version, len(repoRevs))
+	anyCommit := false
+	for i, repoRev := range repoRevs {
+		repo, commitID := ParseRepoAndCommitID(repoRev)
+		vs[i] = store.Version{Repo: repo, CommitID: commitID}
+		anyCommit = anyCommit || commitID == ""
+	}
+	if !anyCommit {
+		return store.ByRepoCommitIDs(vs...)
+	}
+	return store.DefFilterFunc(func(def *graph.Def) bool {
+		for _, v := range vs {
+			if def.Repo == v.Repo && (v.CommitID == "" || def.CommitID == v.CommitID) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// A DefsSorter is a DefFilter that also orders the defs that it
+// selects. Stores that don't sort defs themselves can be used with
+// SortDefs.
+type DefsSorter interface {
+	store.DefFilter
+	DefsSort(defs []*graph.Def)
+}
+
+// SortDefs sorts defs with each DefsSorter in fs, in order.
+func SortDefs(defs []*graph.Def, fs []store.DefFilter) {
+	for _, f := range fs {
+		if s, ok := f.(DefsSorter); ok {
+			s.DefsSort(defs)
+		}
+	}
+}
+
+// DefsSortBy is a DefsSorter that sorts defs by their key (if Field
+// is "key") or by their name and then key (if Field is "name"), in
+// descending order if Desc is true. It selects all defs.
+type DefsSortBy struct {
+	Field string
+	Desc  bool
+}
+
+func (s DefsSortBy) SelectDef(*graph.Def) bool { return true }
+
+func (s DefsSortBy) DefsSort(defs []*graph.Def) {
+	less := defKeyLess
+	if s.Field == "name" {
+		less = func(a, b *graph.Def) bool {
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return defKeyLess(a, b)
+		}
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		if s.Desc {
+			return less(defs[j], defs[i])
+		}
+		return less(defs[i], defs[j])
+	})
+}
+
+func defKeyLess(a, b *graph.Def) bool {
+	switch {
+	case a.Repo != b.Repo:
+		return a.Repo < b.Repo
+	case a.CommitID != b.CommitID:
+		return a.CommitID < b.CommitID
+	case a.UnitType != b.UnitType:
+		return a.UnitType < b.UnitType
+	case a.Unit != b.Unit:
+		return a.Unit < b.Unit
+	}
+	return a.Path < b.Path
+}
+
+// ByDefFuzzyQuery is a DefsSorter that selects the defs whose names
+// fuzzily match the query (see Score), and sorts them by score (best
+// first) and then by name.
+type ByDefFuzzyQuery string
+
+func (q ByDefFuzzyQuery) SelectDef(def *graph.Def) bool { return q.Score(def) > 0 }
+
+func (q ByDefFuzzyQuery) DefsSort(defs []*graph.Def) {
+	scores := make(map[*graph.Def]int, len(defs))
+	for _, def := range defs {
+		scores[def] = q.Score(def)
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		a, b := defs[i], defs[j]
+		switch {
+		case scores[a] != scores[b]:
+			return scores[a] > scores[b]
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		}
+		return defKeyLess(a, b)
+	})
+}
+
+// Score returns how well def's name matches q (higher is better), or
+// 0 if it doesn't match. The name matches if all of the characters of
+// q appear in it in order, ignoring case. Consecutive characters, a
+// match at the start of the name, and exact matches score higher.
+func (q ByDefFuzzyQuery) Score(def *graph.Def) int {
+	n, qr := []rune(strings.ToLower(def.Name)), []rune(strings.ToLower(string(q)))
+	if len(qr) == 0 {
+		return 0
+	}
+	score, j, prev := 0, 0, -2
+	for i := 0; i < len(n) && j < len(qr); i++ {
+		if n[i] != qr[j] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 {
+			score += 3
+		}
+		prev = i
+		j++
+	}
+	if j < len(qr) {
+		return 0
+	}
+	if len(n) == len(qr) {
+		score += 10
+	}
+	return score
+}
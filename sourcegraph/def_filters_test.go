@@ -0,0 +1,148 @@
+package sourcegraph
+
+import (
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/srclib/graph"
+	"sourcegraph.com/sourcegraph/srclib/store"
+)
+
+func TestDefListOptions_DefFilters(t *testing.T) {
+	commitID := "0123456789012345678901234567890123456789"
+	def := func(repo, unitType, path, name, file string) *graph.Def {
+		return &graph.Def{DefKey: graph.DefKey{Repo: repo, CommitID: commitID, UnitType: unitType, Unit: "u", Path: path}, Name: name, File: file}
+	}
+	defs := []*graph.Def{
+		def("r.com/a", "GoPackage", "p/NewClient", "NewClient", "a/client.go"),
+		def("r.com/a", "GoPackage", "p/Client", "Client", "a/b/client.go"),
+		def("r.com/a", "GoPackage", "p/Close", "Close", "ab/close.go"),
+		def("r.com/b", "PipPackage", "p/newClient", "newClient", "a/client.py"),
+	}
+
+	tests := map[string]struct {
+		opt     DefListOptions
+		want    []string // def names
+		wantErr bool
+	}{
+		"empty": {
+			want: []string{"NewClient", "Client", "Close", "newClient"},
+		},
+		"file path prefix": {
+			opt:  DefListOptions{FilePathPrefix: "a/c"},
+			want: []string{"NewClient", "newClient"},
+		},
+		"file path prefix with trailing slash": {
+			// Cleaned to "a", like the API does.
+			opt:  DefListOptions{FilePathPrefix: "a/"},
+			want: []string{"NewClient", "Client", "Close", "newClient"},
+		},
+		"nested file path prefix": {
+			opt:  DefListOptions{FilePathPrefix: "a/b"},
+			want: []string{"Client"},
+		},
+		"absolute file path prefix": {
+			opt:  DefListOptions{FilePathPrefix: "/a"},
+			want: []string{},
+		},
+		"path": {
+			opt:  DefListOptions{Path: "p/Close"},
+			want: []string{"Close"},
+		},
+		"unit type only": {
+			opt:  DefListOptions{UnitType: "PipPackage"},
+			want: []string{"newClient"},
+		},
+		"repo without revision": {
+			opt:  DefListOptions{RepoRevs: []string{"r.com/b"}},
+			want: []string{"newClient"},
+		},
+		"repo with commit ID": {
+			opt:  DefListOptions{RepoRevs: []string{"r.com/b@" + commitID, "r.com/c@" + commitID}},
+			want: []string{"newClient"},
+		},
+		"sort by key descending": {
+			opt:  DefListOptions{Sort: "key", Direction: "desc"},
+			want: []string{"newClient", "NewClient", "Close", "Client"},
+		},
+		"sort ignored for queries": {
+			opt:  DefListOptions{Query: "client", Sort: "name", Direction: "desc"},
+			want: []string{"NewClient", "Client", "newClient"},
+		},
+		"fuzzy": {
+			opt:  DefListOptions{Query: "clnt", Fuzzy: true},
+			want: []string{"Client", "NewClient", "newClient"},
+		},
+		"non-absolute revision": {
+			opt:     DefListOptions{RepoRevs: []string{"r.com/a@master"}},
+			wantErr: true,
+		},
+		"invalid sort": {
+			opt:     DefListOptions{Sort: "xrefs"},
+			wantErr: true,
+		},
+		"invalid direction": {
+			opt:     DefListOptions{Sort: "name", Direction: "up"},
+			wantErr: true,
+		},
+	}
+	for label, test := range tests {
+		err := test.opt.Validate()
+		if test.wantErr {
+			if _, ok := err.(*InvalidOptionsError); !ok {
+				t.Errorf("%s: got error %v, want *InvalidOptionsError", label, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Validate: %s", label, err)
+			continue
+		}
+		if names := selectDefNames(defs, test.opt.DefFilters()); !reflect.DeepEqual(names, test.want) {
+			t.Errorf("%s: got defs %v, want %v", label, names, test.want)
+		}
+	}
+}
+
+// selectDefNames returns the names of the defs that all of fs select,
+// sorted with SortDefs.
+func selectDefNames(defs []*graph.Def, fs []store.DefFilter) []string {
+	var selected []*graph.Def
+	for _, def := range defs {
+		ok := true
+		for _, f := range fs {
+			ok = ok && f.SelectDef(def)
+		}
+		if ok {
+			selected = append(selected, def)
+		}
+	}
+	SortDefs(selected, fs)
+
+	names := make([]string, len(selected))
+	for i, def := range selected {
+		names[i] = def.Name
+	}
+	return names
+}
+
+func TestByDefFuzzyQuery_Score(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        int
+	}{
+		{"Client", "", 0},
+		{"Client", "x", 0},
+		{"Client", "tc", 0},
+		{"Client", "c", 4},
+		{"NewClient", "c", 1},
+		{"Client", "cl", 7},
+		{"Client", "client", 29},
+		{"Client", "CLIENT", 29},
+	}
+	for _, test := range tests {
+		if score := ByDefFuzzyQuery(test.query).Score(&graph.Def{Name: test.name}); score != test.want {
+			t.Errorf("%q matching %q: got score %d, want %d", test.name, test.query, score, test.want)
+		}
+	}
+}
@@ -1,49 +1,46 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 	"html/template"
-	"log"
-	"path"
 	"time"
 
 	"sourcegraph.com/sourcegraph/go-nnz/nnz"
 
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 	"sourcegraph.com/sourcegraph/srclib/graph"
-	"sourcegraph.com/sourcegraph/srclib/store"
-	"sourcegraph.com/sourcegraph/srclib/unit"
 )
 
 // DefsService communicates with the def- and graph-related endpoints in
 // the Sourcegraph API.
 type DefsService interface {
 	// Get fetches a def.
-	Get(def DefSpec, opt *DefGetOptions) (*Def, Response, error)
+	Get(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error)
 
 	// List defs.
-	List(opt *DefListOptions) ([]*Def, Response, error)
+	List(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error)
 
 	// ListRefs lists references to def.
-	ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
+	ListRefs(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
 
 	// ListExamples lists examples for def.
-	ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
+	ListExamples(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
 
 	// ListExamples lists people who committed parts of def's definition.
-	ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
+	ListAuthors(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
 
 	// ListClients lists people who use def in their code.
-	ListClients(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
+	ListClients(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
 
 	// ListDependents lists repositories that use def in their code.
-	ListDependents(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
+	ListDependents(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
 
 	// ListVersions lists all available versions of a definition in
 	// the various repository commits in which it has appeared.
 	//
 	// TODO(sqs): how to deal with renames, etc.?
-	ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
+	ListVersions(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
 }
 
 // DefSpec specifies a def.
@@ -63,6 +60,32 @@ func (s *DefSpec) RouteVars() map[string]string {
 	return m
 }
 
+// UnmarshalDefSpec marshals a map containing route variables
+// generated by (*DefSpec).RouteVars() and returns the equivalent
+// DefSpec struct. The "Rev" route variable may also be in the
+// "Rev===CommitID" form used by RepoRevSpec, in which case CommitID
+// is taken from it.
+func UnmarshalDefSpec(routeVars map[string]string) (DefSpec, error) {
+	repoRev, err := UnmarshalRepoRevSpec(routeVars)
+	if err != nil {
+		return DefSpec{}, err
+	}
+	if repoRev.URI == "" {
+		return DefSpec{}, fmt.Errorf("DefSpec repository %q must be a URI", routeVars["RepoSpec"])
+	}
+	commitID := repoRev.CommitID
+	if commitID == "" {
+		commitID = repoRev.Rev
+	}
+	return DefSpec{
+		Repo:     repoRev.URI,
+		CommitID: commitID,
+		UnitType: routeVars["UnitType"],
+		Unit:     routeVars["Unit"],
+		Path:     routeVars["Path"],
+	}, nil
+}
+
 // DefKey returns the def key specified by s, using the Repo, UnitType,
 // Unit, and Path fields of s.
 func (s *DefSpec) DefKey() graph.DefKey {
@@ -163,7 +186,7 @@ type DefGetOptions struct {
 	Stats bool `url:",omitempty"`
 }
 
-func (s *defsService) Get(def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
+func (s *defsService) Get(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
 	url, err := s.client.URL(router.Def, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -175,7 +198,7 @@ func (s *defsService) Get(def DefSpec, opt *DefGetOptions) (*Def, Response, erro
 	}
 
 	var def_ *Def
-	resp, err := s.client.Do(req, &def_)
+	resp, err := s.client.Do(ctx, req, &def_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -219,6 +242,9 @@ type DefListOptions struct {
 	// Enhancements
 	Doc   bool `url:",omitempty" json:",omitempty"`
 	Stats bool `url:",omitempty" json:",omitempty"`
+
+	// Fuzzy is whether Query matches def names fuzzily, ordering the
+	// results by how well they match (see ByDefFuzzyQuery).
 	Fuzzy bool `url:",omitempty" json:",omitempty"`
 
 	// Sorting
@@ -229,74 +255,7 @@ type DefListOptions struct {
 	ListOptions
 }
 
-func (o *DefListOptions) DefFilters() []store.DefFilter {
-	var fs []store.DefFilter
-	if o.Name != "" {
-		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
-			return def.Name == o.Name
-		}))
-	}
-	if o.Query != "" {
-		fs = append(fs, store.ByDefQuery(o.Query))
-	}
-	if len(o.RepoRevs) > 0 {
-		vs := make([]store.Version, len(o.RepoRevs))
-		for i, repoRev := range o.RepoRevs {
-			repo, commitID := ParseRepoAndCommitID(repoRev)
-			if len(commitID) != 40 {
-				log.Printf("WARNING: In DefListOptions.DefFilters, o.RepoRevs[%d]==%q has no commit ID or a non-absolute commit ID. No defs will match it.", i, repoRev)
-			}
-			vs[i] = store.Version{Repo: repo, CommitID: commitID}
-		}
-		fs = append(fs, store.ByRepoCommitIDs(vs...))
-	}
-	if o.Unit != "" && o.UnitType != "" {
-		fs = append(fs, store.ByUnits(unit.ID2{Type: o.UnitType, Name: o.Unit}))
-	}
-	if (o.UnitType != "" && o.Name == "") || (o.UnitType == "" && o.Name != "") {
-		log.Println("WARNING: DefListOptions.DefFilter: must specify either both or neither of --type and --name (to filter by source unit)")
-	}
-	if o.File != "" {
-		fs = append(fs, store.ByFiles(path.Clean(o.File)))
-	}
-	if o.FilePathPrefix != "" {
-		fs = append(fs, store.ByFiles(path.Clean(o.FilePathPrefix)))
-	}
-	if len(o.Kinds) > 0 {
-		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
-			for _, kind := range o.Kinds {
-				if def.Kind == kind {
-					return true
-				}
-			}
-			return false
-		}))
-	}
-	if o.Exported {
-		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
-			return def.Exported
-		}))
-	}
-	if o.Nonlocal {
-		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
-			return !def.Local
-		}))
-	}
-	if !o.IncludeTest {
-		fs = append(fs, store.DefFilterFunc(func(def *graph.Def) bool {
-			return !def.Test
-		}))
-	}
-	switch o.Sort {
-	case "key":
-		fs = append(fs, store.DefsSortByKey{})
-	case "name":
-		fs = append(fs, store.DefsSortByName{})
-	}
-	return fs
-}
-
-func (s *defsService) List(opt *DefListOptions) ([]*Def, Response, error) {
+func (s *defsService) List(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error) {
 	url, err := s.client.URL(router.Defs, nil, opt)
 	if err != nil {
 		return nil, nil, err
@@ -308,7 +267,7 @@ func (s *defsService) List(opt *DefListOptions) ([]*Def, Response, error) {
 	}
 
 	var defs []*Def
-	resp, err := s.client.Do(req, &defs)
+	resp, err := s.client.Do(ctx, req, &defs)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -334,7 +293,7 @@ type DefListRefsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
+func (s *defsService) ListRefs(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
 	url, err := s.client.URL(router.DefRefs, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -346,7 +305,7 @@ func (s *defsService) ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Re
 	}
 
 	var defRefs []*Ref
-	resp, err := s.client.Do(req, &defRefs)
+	resp, err := s.client.Do(ctx, req, &defRefs)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -400,7 +359,7 @@ type DefListExamplesOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
+func (s *defsService) ListExamples(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
 	url, err := s.client.URL(router.DefExamples, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -412,7 +371,7 @@ func (s *defsService) ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]
 	}
 
 	var examples []*Example
-	resp, err := s.client.Do(req, &examples)
+	resp, err := s.client.Do(ctx, req, &examples)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -461,7 +420,7 @@ type DefListAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
+func (s *defsService) ListAuthors(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
 	url, err := s.client.URL(router.DefAuthors, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -473,7 +432,7 @@ func (s *defsService) ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*A
 	}
 
 	var authors []*AugmentedDefAuthor
-	resp, err := s.client.Do(req, &authors)
+	resp, err := s.client.Do(ctx, req, &authors)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -501,7 +460,7 @@ type DefListClientsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListClients(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
+func (s *defsService) ListClients(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
 	url, err := s.client.URL(router.DefClients, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -513,7 +472,7 @@ func (s *defsService) ListClients(def DefSpec, opt *DefListClientsOptions) ([]*A
 	}
 
 	var clients []*AugmentedDefClient
-	resp, err := s.client.Do(req, &clients)
+	resp, err := s.client.Do(ctx, req, &clients)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -536,7 +495,7 @@ type DefListDependentsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListDependents(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
+func (s *defsService) ListDependents(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
 	url, err := s.client.URL(router.DefDependents, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -548,7 +507,7 @@ func (s *defsService) ListDependents(def DefSpec, opt *DefListDependentsOptions)
 	}
 
 	var dependents []*AugmentedDefDependent
-	resp, err := s.client.Do(req, &dependents)
+	resp, err := s.client.Do(ctx, req, &dependents)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -560,7 +519,7 @@ type DefListVersionsOptions struct {
 	ListOptions
 }
 
-func (s *defsService) ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
+func (s *defsService) ListVersions(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
 	url, err := s.client.URL(router.DefVersions, def.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -572,7 +531,7 @@ func (s *defsService) ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]
 	}
 
 	var defVersions []*Def
-	resp, err := s.client.Do(req, &defVersions)
+	resp, err := s.client.Do(ctx, req, &defVersions)
 	if err != nil {
 		return nil, resp, err
 	}
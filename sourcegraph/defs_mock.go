@@ -1,42 +1,46 @@
 package sourcegraph
 
+import "context"
+
 type MockDefsService struct {
-	Get_            func(def DefSpec, opt *DefGetOptions) (*Def, Response, error)
-	List_           func(opt *DefListOptions) ([]*Def, Response, error)
-	ListRefs_       func(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
-	ListExamples_   func(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
-	ListAuthors_    func(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
-	ListClients_    func(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
-	ListDependents_ func(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
-	ListVersions_   func(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
+	Get_            func(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error)
+	List_           func(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error)
+	ListRefs_       func(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error)
+	ListExamples_   func(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error)
+	ListAuthors_    func(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error)
+	ListClients_    func(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error)
+	ListDependents_ func(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error)
+	ListVersions_   func(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error)
 }
 
-func (s MockDefsService) Get(def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
-	return s.Get_(def, opt)
+func (s MockDefsService) Get(ctx context.Context, def DefSpec, opt *DefGetOptions) (*Def, Response, error) {
+	return s.Get_(ctx, def, opt)
 }
 
-func (s MockDefsService) List(opt *DefListOptions) ([]*Def, Response, error) { return s.List_(opt) }
+func (s MockDefsService) List(ctx context.Context, opt *DefListOptions) ([]*Def, Response, error) {
+	return s.List_(ctx, opt)
+}
 
-func (s MockDefsService) ListRefs(def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
-	return s.ListRefs_(def, opt)
+func (s MockDefsService) ListRefs(ctx context.Context, def DefSpec, opt *DefListRefsOptions) ([]*Ref, Response, error) {
+	return s.ListRefs_(ctx, def, opt)
 }
 
-func (s MockDefsService) ListExamples(def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
-	return s.ListExamples_(def, opt)
+func (s MockDefsService) ListExamples(ctx context.Context, def DefSpec, opt *DefListExamplesOptions) ([]*Example, Response, error) {
+	return s.ListExamples_(ctx, def, opt)
 }
 
-func (s MockDefsService) ListAuthors(def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
-	return s.ListAuthors_(def, opt)
+func (s MockDefsService) ListAuthors(ctx context.Context, def DefSpec, opt *DefListAuthorsOptions) ([]*AugmentedDefAuthor, Response, error) {
+	return s.ListAuthors_(ctx, def, opt)
 }
 
-func (s MockDefsService) ListClients(def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
-	return s.ListClients_(def, opt)
+func (s MockDefsService) ListClients(ctx context.Context, def DefSpec, opt *DefListClientsOptions) ([]*AugmentedDefClient, Response, error) {
+	return s.ListClients_(ctx, def, opt)
 }
 
-func (s MockDefsService) ListDependents(def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
-	return s.ListDependents_(def, opt)
+func (s MockDefsService) ListDependents(ctx context.Context, def DefSpec, opt *DefListDependentsOptions) ([]*AugmentedDefDependent, Response, error) {
+	return s.ListDependents_(ctx, def, opt)
 }
 
-func (s MockDefsService) ListVersions(def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
-	return s.ListVersions_(def, opt)
+func (s MockDefsService) ListVersions(ctx context.Context, def DefSpec, opt *DefListVersionsOptions) ([]*Def, Response, error) {
+	return s.ListVersions_(ctx, def, opt)
 }
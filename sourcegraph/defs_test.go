@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -24,7 +25,7 @@ func TestDefsService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	repo_, _, err := client.Defs.Get(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, &DefGetOptions{Doc: true})
+	repo_, _, err := client.Defs.Get(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, &DefGetOptions{Doc: true})
 	if err != nil {
 		t.Errorf("Defs.Get returned error: %v", err)
 	}
@@ -62,7 +63,7 @@ func TestDefsService_List(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	defs, _, err := client.Defs.List(&DefListOptions{
+	defs, _, err := client.Defs.List(context.Background(), &DefListOptions{
 		RepoRevs:    []string{"r1", "r2@x"},
 		Sort:        "name",
 		Direction:   "asc",
@@ -99,7 +100,7 @@ func TestDefsService_ListRefs(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	refs, _, err := client.Defs.ListRefs(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, &DefListRefsOptions{Authorship: true})
+	refs, _, err := client.Defs.ListRefs(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, &DefListRefsOptions{Authorship: true})
 	if err != nil {
 		t.Errorf("Defs.ListRefs returned error: %v", err)
 	}
@@ -127,7 +128,7 @@ func TestDefsService_ListExamples(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	refs, _, err := client.Defs.ListExamples(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
+	refs, _, err := client.Defs.ListExamples(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
 	if err != nil {
 		t.Errorf("Defs.ListExamples returned error: %v", err)
 	}
@@ -155,7 +156,7 @@ func TestDefsService_ListAuthors(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	authors, _, err := client.Defs.ListAuthors(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
+	authors, _, err := client.Defs.ListAuthors(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
 	if err != nil {
 		t.Errorf("Defs.ListAuthors returned error: %v", err)
 	}
@@ -183,7 +184,7 @@ func TestDefsService_ListClients(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	clients, _, err := client.Defs.ListClients(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
+	clients, _, err := client.Defs.ListClients(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
 	if err != nil {
 		t.Errorf("Defs.ListClients returned error: %v", err)
 	}
@@ -211,7 +212,7 @@ func TestDefsService_ListDependents(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	dependents, _, err := client.Defs.ListDependents(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
+	dependents, _, err := client.Defs.ListDependents(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
 	if err != nil {
 		t.Errorf("Defs.ListDependents returned error: %v", err)
 	}
@@ -240,7 +241,7 @@ func TestDefsService_ListVersions(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	versions, _, err := client.Defs.ListVersions(DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
+	versions, _, err := client.Defs.ListVersions(context.Background(), DefSpec{Repo: "r.com/x", UnitType: "t", Unit: "u", Path: "p"}, nil)
 	if err != nil {
 		t.Errorf("Defs.ListVersions returned error: %v", err)
 	}
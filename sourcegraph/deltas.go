@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/base64"
 	"strings"
 
@@ -17,38 +18,38 @@ import (
 // impact information, etc.
 type DeltasService interface {
 	// Get fetches a summary of a delta.
-	Get(ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error)
+	Get(ctx context.Context, ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error)
 
 	// ListUnits lists units added/changed/deleted in a delta.
-	ListUnits(ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error)
+	ListUnits(ctx context.Context, ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error)
 
 	// ListDefs lists definitions added/changed/deleted in a delta.
-	ListDefs(ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error)
+	ListDefs(ctx context.Context, ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error)
 
 	// ListDependencies lists dependencies added/changed/deleted in a
 	// delta.
-	ListDependencies(ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error)
+	ListDependencies(ctx context.Context, ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error)
 
 	// ListFiles fetches the file diff for a delta.
-	ListFiles(ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error)
+	ListFiles(ctx context.Context, ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error)
 
 	// ListAffectedAuthors lists authors whose code is added/deleted/changed
 	// in a delta.
-	ListAffectedAuthors(ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error)
+	ListAffectedAuthors(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error)
 
 	// ListAffectedClients lists clients whose code is affected by a delta.
-	ListAffectedClients(ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error)
+	ListAffectedClients(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error)
 
 	// ListAffectedDependents lists dependent repositories that are affected
 	// by a delta.
-	ListAffectedDependents(ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error)
+	ListAffectedDependents(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error)
 
 	// ListReviewers lists people who are reviewing or are suggested
 	// reviewers for this delta.
-	ListReviewers(ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error)
+	ListReviewers(ctx context.Context, ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error)
 
 	// ListIncoming lists deltas that affect the given repo.
-	ListIncoming(rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error)
+	ListIncoming(ctx context.Context, rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error)
 }
 
 // deltasService implements DeltasService.
@@ -145,7 +146,7 @@ func (d *Delta) BaseAndHeadBuildsSuccessful() bool {
 // DeltaGetOptions specifies options for getting a delta.
 type DeltaGetOptions struct{}
 
-func (s *deltasService) Get(ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error) {
+func (s *deltasService) Get(ctx context.Context, ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Response, error) {
 	url, err := s.client.URL(router.Delta, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -157,7 +158,7 @@ func (s *deltasService) Get(ds DeltaSpec, opt *DeltaGetOptions) (*Delta, Respons
 	}
 
 	var delta *Delta
-	resp, err := s.client.Do(req, &delta)
+	resp, err := s.client.Do(ctx, req, &delta)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -204,7 +205,7 @@ func deltaUnitLess(a, b *unit.SourceUnit) bool {
 // DeltaListUnitsOptions specifies options for ListUnits.
 type DeltaListUnitsOptions struct{}
 
-func (s *deltasService) ListUnits(ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error) {
+func (s *deltasService) ListUnits(ctx context.Context, ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*UnitDelta, Response, error) {
 	url, err := s.client.URL(router.DeltaUnits, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -216,7 +217,7 @@ func (s *deltasService) ListUnits(ds DeltaSpec, opt *DeltaListUnitsOptions) ([]*
 	}
 
 	var units []*UnitDelta
-	resp, err := s.client.Do(req, &units)
+	resp, err := s.client.Do(ctx, req, &units)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -285,7 +286,7 @@ func deltaDefLess(a, b *Def) bool {
 	return a.UnitType < b.UnitType || (a.UnitType == b.UnitType && a.Unit < b.Unit) || (a.UnitType == b.UnitType && a.Unit == b.Unit && a.Path < b.Path)
 }
 
-func (s *deltasService) ListDefs(ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error) {
+func (s *deltasService) ListDefs(ctx context.Context, ds DeltaSpec, opt *DeltaListDefsOptions) (*DeltaDefs, Response, error) {
 	url, err := s.client.URL(router.DeltaDefs, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -297,7 +298,7 @@ func (s *deltasService) ListDefs(ds DeltaSpec, opt *DeltaListDefsOptions) (*Delt
 	}
 
 	var defs *DeltaDefs
-	resp, err := s.client.Do(req, &defs)
+	resp, err := s.client.Do(ctx, req, &defs)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -322,7 +323,7 @@ type DeltaDependencies struct {
 	// Deleted []*Dependency
 }
 
-func (s *deltasService) ListDependencies(ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error) {
+func (s *deltasService) ListDependencies(ctx context.Context, ds DeltaSpec, opt *DeltaListDependenciesOptions) (*DeltaDependencies, Response, error) {
 	url, err := s.client.URL(router.DeltaDependencies, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -334,7 +335,7 @@ func (s *deltasService) ListDependencies(ds DeltaSpec, opt *DeltaListDependencie
 	}
 
 	var dependencies *DeltaDependencies
-	resp, err := s.client.Do(req, &dependencies)
+	resp, err := s.client.Do(ctx, req, &dependencies)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -374,7 +375,7 @@ func (d *DeltaFiles) DiffStat() diff.Stat {
 	return ds
 }
 
-func (s *deltasService) ListFiles(ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error) {
+func (s *deltasService) ListFiles(ctx context.Context, ds DeltaSpec, opt *DeltaListFilesOptions) (*DeltaFiles, Response, error) {
 	url, err := s.client.URL(router.DeltaFiles, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -386,7 +387,7 @@ func (s *deltasService) ListFiles(ds DeltaSpec, opt *DeltaListFilesOptions) (*De
 	}
 
 	var files *DeltaFiles
-	resp, err := s.client.Do(req, &files)
+	resp, err := s.client.Do(ctx, req, &files)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -417,7 +418,7 @@ type DeltaListAffectedAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedAuthors(ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error) {
+func (s *deltasService) ListAffectedAuthors(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedAuthorsOptions) ([]*DeltaAffectedPerson, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedAuthors, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -429,7 +430,7 @@ func (s *deltasService) ListAffectedAuthors(ds DeltaSpec, opt *DeltaListAffected
 	}
 
 	var authors []*DeltaAffectedPerson
-	resp, err := s.client.Do(req, &authors)
+	resp, err := s.client.Do(ctx, req, &authors)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -444,7 +445,7 @@ type DeltaListAffectedClientsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedClients(ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error) {
+func (s *deltasService) ListAffectedClients(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedClientsOptions) ([]*DeltaAffectedPerson, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedClients, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -456,7 +457,7 @@ func (s *deltasService) ListAffectedClients(ds DeltaSpec, opt *DeltaListAffected
 	}
 
 	var clients []*DeltaAffectedPerson
-	resp, err := s.client.Do(req, &clients)
+	resp, err := s.client.Do(ctx, req, &clients)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -488,7 +489,7 @@ type DeltaListAffectedDependentsOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListAffectedDependents(ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error) {
+func (s *deltasService) ListAffectedDependents(ctx context.Context, ds DeltaSpec, opt *DeltaListAffectedDependentsOptions) ([]*DeltaAffectedRepo, Response, error) {
 	url, err := s.client.URL(router.DeltaAffectedDependents, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -500,7 +501,7 @@ func (s *deltasService) ListAffectedDependents(ds DeltaSpec, opt *DeltaListAffec
 	}
 
 	var dependents []*DeltaAffectedRepo
-	resp, err := s.client.Do(req, &dependents)
+	resp, err := s.client.Do(ctx, req, &dependents)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -524,7 +525,7 @@ type DeltaListReviewersOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListReviewers(ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error) {
+func (s *deltasService) ListReviewers(ctx context.Context, ds DeltaSpec, opt *DeltaListReviewersOptions) ([]*DeltaReviewer, Response, error) {
 	url, err := s.client.URL(router.DeltaReviewers, ds.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -536,7 +537,7 @@ func (s *deltasService) ListReviewers(ds DeltaSpec, opt *DeltaListReviewersOptio
 	}
 
 	var reviewers []*DeltaReviewer
-	resp, err := s.client.Do(req, &reviewers)
+	resp, err := s.client.Do(ctx, req, &reviewers)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -551,7 +552,7 @@ type DeltaListIncomingOptions struct {
 	ListOptions
 }
 
-func (s *deltasService) ListIncoming(rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error) {
+func (s *deltasService) ListIncoming(ctx context.Context, rr RepoRevSpec, opt *DeltaListIncomingOptions) ([]*Delta, Response, error) {
 	url, err := s.client.URL(router.DeltasIncoming, rr.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -563,7 +564,7 @@ func (s *deltasService) ListIncoming(rr RepoRevSpec, opt *DeltaListIncomingOptio
 	}
 
 	var deltas []*Delta
-	resp, err := s.client.Do(req, &deltas)
+	resp, err := s.client.Do(ctx, req, &deltas)
 	if err != nil {
 		return nil, resp, err
 	}
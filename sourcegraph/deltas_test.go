@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"strings"
@@ -55,7 +56,7 @@ func TestDeltas(t *testing.T) {
 
 		spec, err := UnmarshalDeltaSpec(vars)
 		if err != nil {
-			t.Errorf("UnmarshalDeltaSpec(%+v): %s", err)
+			t.Errorf("UnmarshalDeltaSpec(%+v): %s", vars, err)
 			continue
 		}
 		if !reflect.DeepEqual(spec, test.spec) {
@@ -85,7 +86,7 @@ func TestDeltasService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	delta, _, err := client.Deltas.Get(ds, nil)
+	delta, _, err := client.Deltas.Get(context.Background(), ds, nil)
 	if err != nil {
 		t.Errorf("Deltas.Get returned error: %v", err)
 	}
@@ -117,7 +118,7 @@ func TestDeltasService_ListUnits(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	units, _, err := client.Deltas.ListUnits(ds, &DeltaListUnitsOptions{})
+	units, _, err := client.Deltas.ListUnits(context.Background(), ds, &DeltaListUnitsOptions{})
 	if err != nil {
 		t.Errorf("Deltas.ListUnits returned error: %v", err)
 	}
@@ -153,7 +154,7 @@ func TestDeltasService_ListDefs(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	defs, _, err := client.Deltas.ListDefs(ds, &DeltaListDefsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	defs, _, err := client.Deltas.ListDefs(context.Background(), ds, &DeltaListDefsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListDefs returned error: %v", err)
 	}
@@ -189,7 +190,7 @@ func TestDeltasService_ListDependencies(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	dependencies, _, err := client.Deltas.ListDependencies(ds, &DeltaListDependenciesOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	dependencies, _, err := client.Deltas.ListDependencies(context.Background(), ds, &DeltaListDependenciesOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListDependencies returned error: %v", err)
 	}
@@ -225,7 +226,7 @@ func TestDeltasService_ListFiles(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	files, _, err := client.Deltas.ListFiles(ds, &DeltaListFilesOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	files, _, err := client.Deltas.ListFiles(context.Background(), ds, &DeltaListFilesOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListFiles returned error: %v", err)
 	}
@@ -261,7 +262,7 @@ func TestDeltasService_ListAffectedAuthors(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	affectedAuthors, _, err := client.Deltas.ListAffectedAuthors(ds, &DeltaListAffectedAuthorsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	affectedAuthors, _, err := client.Deltas.ListAffectedAuthors(context.Background(), ds, &DeltaListAffectedAuthorsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListAffectedAuthors returned error: %v", err)
 	}
@@ -297,7 +298,7 @@ func TestDeltasService_ListAffectedClients(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	affectedClients, _, err := client.Deltas.ListAffectedClients(ds, &DeltaListAffectedClientsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	affectedClients, _, err := client.Deltas.ListAffectedClients(context.Background(), ds, &DeltaListAffectedClientsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListAffectedClients returned error: %v", err)
 	}
@@ -333,7 +334,7 @@ func TestDeltasService_ListAffectedDependents(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	affectedDependents, _, err := client.Deltas.ListAffectedDependents(ds, &DeltaListAffectedDependentsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	affectedDependents, _, err := client.Deltas.ListAffectedDependents(context.Background(), ds, &DeltaListAffectedDependentsOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListAffectedDependents returned error: %v", err)
 	}
@@ -369,7 +370,7 @@ func TestDeltasService_ListReviewers(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	reviewers, _, err := client.Deltas.ListReviewers(ds, &DeltaListReviewersOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	reviewers, _, err := client.Deltas.ListReviewers(context.Background(), ds, &DeltaListReviewersOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListReviewers returned error: %v", err)
 	}
@@ -402,7 +403,7 @@ func TestDeltasService_ListIncoming(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	incoming, _, err := client.Deltas.ListIncoming(rr, &DeltaListIncomingOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
+	incoming, _, err := client.Deltas.ListIncoming(context.Background(), rr, &DeltaListIncomingOptions{DeltaFilter: DeltaFilter{UnitType: "t", Unit: "u"}})
 	if err != nil {
 		t.Errorf("Deltas.ListIncoming returned error: %v", err)
 	}
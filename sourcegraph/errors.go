@@ -2,18 +2,36 @@ package sourcegraph
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 	"sourcegraph.com/sourcegraph/srclib/graph"
 )
 
 // An ErrorResponse reports errors caused by an API request.
+//
+// If the error corresponds to one of this package's error values or
+// types (such as ErrNotExist or ErrRenamed), Err holds that error, and
+// errors.Is and errors.As see through the ErrorResponse to it. For
+// example:
+//
+//	_, _, err := client.Repos.Get(ctx, repo, nil)
+//	if errors.Is(err, sourcegraph.ErrNotExist) {
+//		// ...
+//	}
 type ErrorResponse struct {
 	Response *http.Response `json:",omitempty"` // HTTP response that caused this error
 	Message  string         // error message
+
+	// Err is the typed error that the API error corresponds to, or
+	// nil if it does not correspond to a known error.
+	Err error `json:"-"`
 }
 
 func IsDefError(err error) bool {
@@ -28,11 +46,18 @@ func (r *ErrorResponse) Error() string {
 
 func (r *ErrorResponse) HTTPStatusCode() int { return r.Response.StatusCode }
 
+// Unwrap returns r.Err.
+func (r *ErrorResponse) Unwrap() error { return r.Err }
+
 // CheckResponse checks the API response for errors, and returns them if
 // present.  A response is considered an error if it has a status code outside
 // the 200 range.  API error responses are expected to have either no response
 // body, or a JSON response body that maps to ErrorResponse.  Any other
 // response body will be silently ignored.
+//
+// The returned error is always an *ErrorResponse. If its message is
+// that of one of this package's errors, its Err field is set to that
+// error.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
@@ -42,9 +67,85 @@ func CheckResponse(r *http.Response) error {
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
 	}
+	errorResponse.Err = errorFromMessage(errorResponse.Message)
 	return errorResponse
 }
 
+// messageErrors are the errors that are recognized by their message
+// alone.
+var messageErrors = []error{
+	ErrNotExist,
+	ErrForbidden,
+	ErrNotPersisted,
+	ErrNonStandardURI,
+	ErrNoRepoBuild,
+	ErrBuildNotFound,
+	ErrUserNotExist,
+}
+
+var (
+	errRenamedMsgPattern     = regexp.MustCompile(`^repository URI ("(?:[^"\\]|\\.)*") was renamed to ("(?:[^"\\]|\\.)*"); use the new name$`)
+	errUserRenamedMsgPattern = regexp.MustCompile(`^login ("(?:[^"\\]|\\.)*") was renamed to ("(?:[^"\\]|\\.)*"); use the new name$`)
+)
+
+// errorFromMessage returns the error whose message is msg, or nil if
+// there is none.
+func errorFromMessage(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	for _, err := range messageErrors {
+		if msg == err.Error() {
+			return err
+		}
+	}
+	if e := ErrRedirectFromString(msg); e != nil {
+		return *e
+	}
+	if old, new, ok := matchQuotedPair(errRenamedMsgPattern, msg); ok {
+		return ErrRenamed{OldURI: old, NewURI: new}
+	}
+	if old, new, ok := matchQuotedPair(errUserRenamedMsgPattern, msg); ok {
+		return ErrUserRenamed{OldLogin: old, NewLogin: new}
+	}
+	return nil
+}
+
+// matchQuotedPair matches msg against pat, which must have 2
+// subexpressions that match Go-quoted strings, and returns the
+// unquoted strings.
+func matchQuotedPair(pat *regexp.Regexp, msg string) (a, b string, ok bool) {
+	m := pat.FindStringSubmatch(msg)
+	if m == nil {
+		return "", "", false
+	}
+	a, err := strconv.Unquote(m[1])
+	if err != nil {
+		return "", "", false
+	}
+	b, err = strconv.Unquote(m[2])
+	if err != nil {
+		return "", "", false
+	}
+	return a, b, true
+}
+
+// statusErrors maps API routes and HTTP status codes to the errors
+// that they imply, for error responses whose message doesn't identify
+// the error.
+var statusErrors = map[string]map[int]error{
+	router.Repo:  {http.StatusNotFound: ErrNotExist, http.StatusForbidden: ErrForbidden},
+	router.Build: {http.StatusNotFound: ErrBuildNotFound},
+	router.User:  {http.StatusNotFound: ErrUserNotExist},
+}
+
+// errorFromStatus returns the error implied by an error response with
+// the given status code from the named route, or nil if there is
+// none.
+func errorFromStatus(routeName string, statusCode int) error {
+	return statusErrors[routeName][statusCode]
+}
+
 func IsHTTPErrorCode(err error, statusCode int) bool {
 	if err == nil {
 		return false
@@ -54,7 +155,8 @@ func IsHTTPErrorCode(err error, statusCode int) bool {
 		Error() string
 		HTTPStatusCode() int
 	}
-	if httpErr, ok := err.(httpError); ok {
+	var httpErr httpError
+	if errors.As(err, &httpErr) {
 		return statusCode == httpErr.HTTPStatusCode()
 	}
 	return false
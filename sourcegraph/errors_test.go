@@ -0,0 +1,107 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr error
+	}{
+		{body: ``, wantErr: nil},
+		{body: `{"Message":"x"}`, wantErr: nil},
+		{body: `{"Message":"repository does not exist on external host"}`, wantErr: ErrNotExist},
+		{body: `{"Message":"repository is unavailable"}`, wantErr: ErrForbidden},
+		{body: `{"Message":"build not found"}`, wantErr: ErrBuildNotFound},
+		{body: `{"Message":"user does not exist"}`, wantErr: ErrUserNotExist},
+		{
+			body:    `{"Message":"the repository requested exists at another URI (r.com/y)"}`,
+			wantErr: ErrRedirect{RedirectURI: "r.com/y"},
+		},
+		{
+			body:    `{"Message":"repository URI \"r.com/x\" was renamed to \"r.com/y\"; use the new name"}`,
+			wantErr: ErrRenamed{OldURI: "r.com/x", NewURI: "r.com/y"},
+		},
+		{
+			body:    `{"Message":"login \"a\" was renamed to \"b\"; use the new name"}`,
+			wantErr: ErrUserRenamed{OldLogin: "a", NewLogin: "b"},
+		},
+	}
+	for _, test := range tests {
+		resp := &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(test.body)),
+			Request:    &http.Request{Method: "GET"},
+		}
+		err := CheckResponse(resp)
+		errResp, ok := err.(*ErrorResponse)
+		if !ok {
+			t.Errorf("%s: got error %T, want *ErrorResponse", test.body, err)
+			continue
+		}
+		if errResp.Response != resp {
+			t.Errorf("%s: got Response %v, want %v", test.body, errResp.Response, resp)
+		}
+		if !reflect.DeepEqual(errResp.Err, test.wantErr) {
+			t.Errorf("%s: got Err %#v, want %#v", test.body, errResp.Err, test.wantErr)
+		}
+		if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: errors.Is(err, %v) == false", test.body, test.wantErr)
+		}
+	}
+}
+
+func TestCheckResponse_errorsAs(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusMovedPermanently,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Message":"repository URI \"r.com/x\" was renamed to \"r.com/y\"; use the new name"}`)),
+		Request:    &http.Request{Method: "GET"},
+	}
+	err := CheckResponse(resp)
+
+	var renamed ErrRenamed
+	if !errors.As(err, &renamed) {
+		t.Fatalf("errors.As(%v, *ErrRenamed) == false", err)
+	}
+	if want := "r.com/y"; renamed.NewURI != want {
+		t.Errorf("got NewURI %q, want %q", renamed.NewURI, want)
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("errors.As(%v, **ErrorResponse) == false", err)
+	}
+	if !IsHTTPErrorCode(err, http.StatusMovedPermanently) {
+		t.Errorf("IsHTTPErrorCode(%v, 301) == false", err)
+	}
+}
+
+func TestDo_statusError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "", http.StatusNotFound)
+	})
+
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	resp, err := client.Do(context.Background(), req, nil)
+	if !IsNotPresent(err) {
+		t.Errorf("got error %v, want IsNotPresent", err)
+	}
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("got error %v, want ErrNotExist", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got response %v, want HTTP 404 response", resp)
+	}
+}
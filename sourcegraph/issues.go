@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sourcegraph/go-github/github"
@@ -15,22 +16,22 @@ import (
 // Sourcegraph API.
 type IssuesService interface {
 	// Get fetches a issue.
-	Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
+	Get(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error)
 
 	// List issues for a repository.
-	ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
+	ListByRepo(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error)
 
 	// ListComments lists comments on a issue.
-	ListComments(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
+	ListComments(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error)
 
 	// CreateComment creates a comment on an issue.
-	CreateComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	CreateComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
 
 	// EditComment updates a comment on an issue.
-	EditComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
+	EditComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error)
 
 	// DeleteComment deletes a comment on an issue.
-	DeleteComment(issue IssueSpec, commentID int) (Response, error)
+	DeleteComment(ctx context.Context, issue IssueSpec, commentID int) (Response, error)
 }
 
 // issuesService implements IssuesService.
@@ -48,7 +49,9 @@ type IssueSpec struct {
 }
 
 func (s IssueSpec) RouteVars() map[string]string {
-	return map[string]string{"RepoSpec": s.Repo.URI, "Issue": strconv.Itoa(s.Number)}
+	m := s.Repo.RouteVars()
+	m["Issue"] = strconv.Itoa(s.Number)
+	return m
 }
 
 func UnmarshalIssueSpec(routeVars map[string]string) (IssueSpec, error) {
@@ -56,12 +59,12 @@ func UnmarshalIssueSpec(routeVars map[string]string) (IssueSpec, error) {
 	if err != nil {
 		return IssueSpec{}, err
 	}
-	repoURI := routeVars["RepoSpec"]
-	if repoURI == "" {
-		return IssueSpec{}, fmt.Errorf("RepoSpec was empty")
+	repo, err := UnmarshalRepoSpec(routeVars)
+	if err != nil {
+		return IssueSpec{}, err
 	}
 	return IssueSpec{
-		Repo:   RepoSpec{URI: repoURI},
+		Repo:   repo,
 		Number: issueNumber,
 	}, nil
 }
@@ -83,7 +86,7 @@ func (r *Issue) Spec() IssueSpec {
 
 type IssueGetOptions struct{}
 
-func (s *issuesService) Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
+func (s *issuesService) Get(ctx context.Context, issue IssueSpec, opt *IssueGetOptions) (*Issue, Response, error) {
 	url, err := s.client.URL(router.RepoIssue, issue.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -95,7 +98,7 @@ func (s *issuesService) Get(issue IssueSpec, opt *IssueGetOptions) (*Issue, Resp
 	}
 
 	var issue_ *Issue
-	resp, err := s.client.Do(req, &issue_)
+	resp, err := s.client.Do(ctx, req, &issue_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -108,7 +111,7 @@ type IssueListOptions struct {
 	ListOptions
 }
 
-func (s *issuesService) ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
+func (s *issuesService) ListByRepo(ctx context.Context, repo RepoSpec, opt *IssueListOptions) ([]*Issue, Response, error) {
 	url, err := s.client.URL(router.RepoIssues, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -120,7 +123,7 @@ func (s *issuesService) ListByRepo(repo RepoSpec, opt *IssueListOptions) ([]*Iss
 	}
 
 	var issues []*Issue
-	resp, err := s.client.Do(req, &issues)
+	resp, err := s.client.Do(ctx, req, &issues)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -149,7 +152,7 @@ func (s IssueCommentSpec) RouteVars() map[string]string {
 	return rv
 }
 
-func (s *issuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
+func (s *issuesService) ListComments(ctx context.Context, issue IssueSpec, opt *IssueListCommentsOptions) ([]*IssueComment, Response, error) {
 	url, err := s.client.URL(router.RepoIssueComments, issue.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -161,7 +164,7 @@ func (s *issuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOpti
 	}
 
 	var comments []*IssueComment
-	resp, err := s.client.Do(req, &comments)
+	resp, err := s.client.Do(ctx, req, &comments)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -169,7 +172,7 @@ func (s *issuesService) ListComments(issue IssueSpec, opt *IssueListCommentsOpti
 	return comments, resp, nil
 }
 
-func (s *issuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+func (s *issuesService) CreateComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
 	url, err := s.client.URL(router.RepoIssueCommentsCreate, issue.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -181,7 +184,7 @@ func (s *issuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*
 	}
 
 	var createdComment IssueComment
-	resp, err := s.client.Do(req, &createdComment)
+	resp, err := s.client.Do(ctx, req, &createdComment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -189,7 +192,7 @@ func (s *issuesService) CreateComment(issue IssueSpec, comment *IssueComment) (*
 	return &createdComment, resp, nil
 }
 
-func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
+func (s *issuesService) EditComment(ctx context.Context, issue IssueSpec, comment *IssueComment) (*IssueComment, Response, error) {
 	if comment.ID == nil {
 		return nil, nil, fmt.Errorf("comment ID not specified")
 	}
@@ -205,7 +208,7 @@ func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*Is
 	}
 
 	var updatedComment IssueComment
-	resp, err := s.client.Do(req, &updatedComment)
+	resp, err := s.client.Do(ctx, req, &updatedComment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,7 +216,7 @@ func (s *issuesService) EditComment(issue IssueSpec, comment *IssueComment) (*Is
 	return &updatedComment, resp, nil
 }
 
-func (s *issuesService) DeleteComment(issue IssueSpec, commentID int) (Response, error) {
+func (s *issuesService) DeleteComment(ctx context.Context, issue IssueSpec, commentID int) (Response, error) {
 	url, err := s.client.URL(router.RepoIssueCommentsDelete, IssueCommentSpec{Issue: issue, Comment: commentID}.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -224,7 +227,7 @@ func (s *issuesService) DeleteComment(issue IssueSpec, commentID int) (Response,
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -54,7 +55,7 @@ func TestIssuesService_Get(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	issue, _, err := client.Issues.Get(IssueSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, nil)
+	issue, _, err := client.Issues.Get(context.Background(), IssueSpec{Repo: RepoSpec{URI: "r.com/x"}, Number: 1}, nil)
 	if err != nil {
 		t.Errorf("Issues.Get returned error: %v", err)
 	}
@@ -87,7 +88,7 @@ func TestIssuesService_ListByRepo(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	issues, _, err := client.Issues.ListByRepo(
+	issues, _, err := client.Issues.ListByRepo(context.Background(),
 		repoSpec,
 		&IssueListOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
@@ -125,7 +126,7 @@ func TestIssuesService_ListComments(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	comments, _, err := client.Issues.ListComments(
+	comments, _, err := client.Issues.ListComments(context.Background(),
 		issueSpec,
 		&IssueListCommentsOptions{
 			ListOptions: ListOptions{PerPage: 1, Page: 2},
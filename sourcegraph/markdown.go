@@ -1,9 +1,13 @@
 package sourcegraph
 
-import "sourcegraph.com/sourcegraph/go-sourcegraph/router"
+import (
+	"context"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
 
 type MarkdownService interface {
-	Render(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
+	Render(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
 }
 
 type markdownService struct {
@@ -24,7 +28,7 @@ type MarkdownData struct {
 	Checklist *Checklist
 }
 
-func (m *markdownService) Render(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
+func (m *markdownService) Render(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
 	url, err := m.client.URL(router.Markdown, nil, nil)
 	if err != nil {
 		return nil, nil, err
@@ -39,7 +43,7 @@ func (m *markdownService) Render(markdown []byte, opt MarkdownOpt) (*MarkdownDat
 	}
 
 	var out MarkdownData
-	resp, err := m.client.Do(req, &out)
+	resp, err := m.client.Do(ctx, req, &out)
 	if err != nil {
 		return nil, resp, err
 	}
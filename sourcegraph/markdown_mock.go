@@ -1,9 +1,11 @@
 package sourcegraph
 
+import "context"
+
 type MockMarkdownService struct {
-	Render_ func(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
+	Render_ func(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error)
 }
 
-func (s MockMarkdownService) Render(markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
-	return s.Render_(markdown, opt)
+func (s MockMarkdownService) Render(ctx context.Context, markdown []byte, opt MarkdownOpt) (*MarkdownData, Response, error) {
+	return s.Render_(ctx, markdown, opt)
 }
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -41,7 +42,7 @@ func TestMarkdown(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	got, _, err := client.Markdown.Render(input.Markdown, input.MarkdownOpt)
+	got, _, err := client.Markdown.Render(context.Background(), input.Markdown, input.MarkdownOpt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,260 @@
+package sourcegraph
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestInfo describes an API request that is passed through a
+// Client's Middleware.
+type RequestInfo struct {
+	// Route is the name of the API route (such as router.Repo or
+	// router.DefRefs) that the request is for, or the empty string if
+	// its URL doesn't match any route.
+	Route string
+
+	// RouteVars are the route variables of the request's URL (such as
+	// "RepoSpec").
+	RouteVars map[string]string
+
+	// Options are the querystring options of the request (the URL
+	// encoding of the options struct that was passed to the service
+	// method).
+	Options url.Values
+
+	// Start is when the request was passed to the first middleware.
+	Start time.Time
+}
+
+// A Sender sends an API request and returns the API response.
+type Sender func(ctx context.Context, req *http.Request) (*HTTPResponse, error)
+
+// A Middleware intercepts the API requests that a Client sends (see
+// Client.Middleware). It is called with information about the request
+// and must call send (usually once) to send the request on to the
+// next middleware, or to the API. It may modify the request (for
+// example, to add headers) by passing a copy of req to send, and it
+// may inspect or replace the response and error that send returns.
+//
+// The response's body has not yet been decoded. If the API responded
+// with an error, the error is an *ErrorResponse (as returned by
+// CheckResponse) and the response is also returned.
+type Middleware func(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error)
+
+// intercept sends req through c.Middleware, with send as the
+// innermost Sender.
+func (c *Client) intercept(ctx context.Context, req *http.Request, send Sender) (*HTTPResponse, error) {
+	if len(c.Middleware) == 0 {
+		return send(ctx, req)
+	}
+
+	name, vars := c.matchRoute(req)
+	info := &RequestInfo{
+		Route:     name,
+		RouteVars: vars,
+		Options:   req.URL.Query(),
+		Start:     time.Now(),
+	}
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		m, next := c.Middleware[i], send
+		send = func(ctx context.Context, req *http.Request) (*HTTPResponse, error) {
+			return m(ctx, info, req, next)
+		}
+	}
+	return send(ctx, req)
+}
+
+// LogRequests returns a Middleware that logs each API request to l
+// after its response is received, as a line of space-separated
+// key=value pairs. For example:
+//
+//	api method=GET route=repo status=200 cache=hit duration=3.2ms trace=f00 RepoSpec=github.com/foo/bar
+//
+// The route variables follow the other pairs. The cache and trace
+// pairs are omitted if empty, and an err pair is added if the request
+// failed.
+func LogRequests(l *log.Logger) Middleware {
+	return func(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error) {
+		resp, err := send(ctx, req)
+
+		kvs := []string{"method", req.Method, "route", info.Route}
+		if resp != nil {
+			kvs = append(kvs, "status", strconv.Itoa(resp.StatusCode))
+			if resp.CacheStatus != "" {
+				kvs = append(kvs, "cache", string(resp.CacheStatus))
+			}
+		}
+		kvs = append(kvs, "duration", time.Since(info.Start).String())
+		if id := TraceID(ctx); id != "" {
+			kvs = append(kvs, "trace", id)
+		}
+		if err != nil {
+			kvs = append(kvs, "err", err.Error())
+		}
+		names := make([]string, 0, len(info.RouteVars))
+		for name := range info.RouteVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			kvs = append(kvs, name, info.RouteVars[name])
+		}
+
+		var buf strings.Builder
+		buf.WriteString("api")
+		for i := 0; i < len(kvs); i += 2 {
+			buf.WriteString(" " + kvs[i] + "=" + logfmtValue(kvs[i+1]))
+		}
+		l.Print(buf.String())
+
+		return resp, err
+	}
+}
+
+// logfmtValue quotes v if it is empty or contains characters that
+// would make a key=value log line ambiguous.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"\t\r\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// DefaultLatencyBuckets are the histogram buckets that
+// NewLatencyHistogram uses if none are given.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// A LatencyHistogram records the latencies of API requests, grouped
+// by the name of their route (see RequestInfo.Route). Its Middleware
+// method records the requests that a Client sends:
+//
+//	h := sourcegraph.NewLatencyHistogram(nil)
+//	client.Middleware = append(client.Middleware, h.Middleware)
+//
+// A LatencyHistogram is safe for concurrent use.
+type LatencyHistogram struct {
+	buckets []time.Duration
+
+	mu     sync.Mutex
+	routes map[string]*RouteLatencies
+}
+
+// RouteLatencies is a histogram of the latencies of the requests to
+// a route.
+type RouteLatencies struct {
+	// Buckets are the upper bounds (inclusive) of the histogram's
+	// buckets, in increasing order.
+	Buckets []time.Duration
+
+	// Counts are the numbers of requests in each bucket: Counts[i] is
+	// the number of requests that took more than Buckets[i-1] and at
+	// most Buckets[i]. The last element (Counts[len(Buckets)]) is the
+	// number of requests that took longer than all of the Buckets.
+	Counts []int64
+
+	// Count is the total number of requests, and Sum is their total
+	// latency.
+	Count int64
+	Sum   time.Duration
+}
+
+// NewLatencyHistogram returns a LatencyHistogram with the given
+// bucket upper bounds, which must be in increasing order. If buckets
+// is empty, DefaultLatencyBuckets is used.
+func NewLatencyHistogram(buckets []time.Duration) *LatencyHistogram {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	return &LatencyHistogram{buckets: buckets, routes: map[string]*RouteLatencies{}}
+}
+
+// Middleware is a Middleware that records the latency of each request
+// (until its response is received) under the request's route name.
+// Requests that don't match a route are recorded under the empty
+// route name.
+func (h *LatencyHistogram) Middleware(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error) {
+	resp, err := send(ctx, req)
+	h.Observe(info.Route, time.Since(info.Start))
+	return resp, err
+}
+
+// Observe records a request to route that took d.
+func (h *LatencyHistogram) Observe(route string, d time.Duration) {
+	i := sort.Search(len(h.buckets), func(i int) bool { return d <= h.buckets[i] })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.routes[route]
+	if !ok {
+		r = &RouteLatencies{Buckets: h.buckets, Counts: make([]int64, len(h.buckets)+1)}
+		h.routes[route] = r
+	}
+	r.Counts[i]++
+	r.Count++
+	r.Sum += d
+}
+
+// Routes returns a copy of the histogram of each route that requests
+// have been recorded for, keyed by route name.
+func (h *LatencyHistogram) Routes() map[string]RouteLatencies {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	routes := make(map[string]RouteLatencies, len(h.routes))
+	for route, r := range h.routes {
+		c := *r
+		c.Counts = append([]int64(nil), r.Counts...)
+		routes[route] = c
+	}
+	return routes
+}
+
+// TraceIDHeader is the HTTP request header that PropagateTraceID
+// sends trace IDs in.
+const TraceIDHeader = "X-Trace-Id"
+
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx with the given trace ID. API
+// requests made with the returned context are sent with the trace ID
+// by the PropagateTraceID middleware, and the LogRequests middleware
+// logs it.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID returns the trace ID of ctx (see WithTraceID), or the empty
+// string if it has none.
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// PropagateTraceID is a Middleware that sends the trace ID of each
+// request's context (see WithTraceID), if any, in the TraceIDHeader
+// header. Requests that already have the header are sent unchanged.
+func PropagateTraceID(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error) {
+	if id := TraceID(ctx); id != "" && req.Header.Get(TraceIDHeader) == "" {
+		req = req.Clone(ctx)
+		req.Header.Set(TraceIDHeader, id)
+	}
+	return send(ctx, req)
+}
@@ -0,0 +1,185 @@
+package sourcegraph
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Test"), "a,b"; got != want {
+			t.Errorf("got X-Test header %q, want %q", got, want)
+		}
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error) {
+			calls = append(calls, name)
+			if info.Route != router.Repo {
+				t.Errorf("%s: got route %q, want %q", name, info.Route, router.Repo)
+			}
+			if want := map[string]string{"RepoSpec": "r.com/x"}; !reflect.DeepEqual(info.RouteVars, want) {
+				t.Errorf("%s: got route vars %v, want %v", name, info.RouteVars, want)
+			}
+			if want := (url.Values{"Stats": {"true"}}); !reflect.DeepEqual(info.Options, want) {
+				t.Errorf("%s: got options %v, want %v", name, info.Options, want)
+			}
+			req = req.Clone(ctx)
+			req.Header.Set("X-Test", strings.TrimPrefix(req.Header.Get("X-Test")+","+name, ","))
+			resp, err := send(ctx, req)
+			calls = append(calls, name+" done")
+			return resp, err
+		}
+	}
+	client.Middleware = []Middleware{middleware("a"), middleware("b")}
+
+	repo, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, &RepoGetOptions{Stats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.URI != "r.com/x" {
+		t.Errorf("got repo %q, want %q", repo.URI, "r.com/x")
+	}
+	if want := []string{"a", "b", "b done", "a done"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got calls %v, want %v", calls, want)
+	}
+}
+
+func TestClient_Middleware_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "", http.StatusNotFound)
+	})
+
+	var gotErr error
+	client.Middleware = []Middleware{func(ctx context.Context, info *RequestInfo, req *http.Request, send Sender) (*HTTPResponse, error) {
+		resp, err := send(ctx, req)
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			t.Errorf("got response %v, want HTTP 404", resp)
+		}
+		gotErr = err
+		return resp, err
+	}}
+
+	_, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil)
+	if !IsHTTPErrorCode(err, http.StatusNotFound) {
+		t.Errorf("got error %v, want HTTP 404", err)
+	}
+	if gotErr != err {
+		t.Errorf("middleware got error %v, want %v", gotErr, err)
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	var buf bytes.Buffer
+	client.Middleware = []Middleware{LogRequests(log.New(&buf, "", 0))}
+
+	ctx := WithTraceID(context.Background(), "t 1")
+	if _, _, err := client.Repos.Get(ctx, RepoSpec{URI: "r.com/x"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+	for _, want := range []string{"api method=GET route=repo status=200 duration=", ` trace="t 1" RepoSpec=r.com/x` + "\n"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("got log line %q, want it to contain %q", line, want)
+		}
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram([]time.Duration{10 * time.Millisecond, 100 * time.Millisecond})
+	h.Observe(router.Repo, 5*time.Millisecond)
+	h.Observe(router.Repo, 10*time.Millisecond)
+	h.Observe(router.Repo, time.Second)
+	h.Observe(router.Def, 50*time.Millisecond)
+
+	routes := h.Routes()
+	want := map[string]RouteLatencies{
+		router.Repo: {
+			Buckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+			Counts:  []int64{2, 0, 1},
+			Count:   3,
+			Sum:     1015 * time.Millisecond,
+		},
+		router.Def: {
+			Buckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+			Counts:  []int64{0, 1, 0},
+			Count:   1,
+			Sum:     50 * time.Millisecond,
+		},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("got %+v, want %+v", routes, want)
+	}
+
+	// Routes returns a copy.
+	routes[router.Repo].Counts[0] = 100
+	if h.Routes()[router.Repo].Counts[0] != 2 {
+		t.Error("modifying the result of Routes modified the histogram")
+	}
+}
+
+func TestLatencyHistogram_Middleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	h := NewLatencyHistogram(nil)
+	client.Middleware = []Middleware{h.Middleware}
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Repos.Get(context.Background(), RepoSpec{URI: "r.com/x"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	routes := h.Routes()
+	if len(routes) != 1 || routes[router.Repo].Count != 2 {
+		t.Errorf("got %+v, want 2 requests to route %q", routes, router.Repo)
+	}
+}
+
+func TestPropagateTraceID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got []string
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(TraceIDHeader))
+		writeJSON(w, &Repo{URI: "r.com/x"})
+	})
+
+	client.Middleware = []Middleware{PropagateTraceID}
+	for _, ctx := range []context.Context{context.Background(), WithTraceID(context.Background(), "abc")} {
+		if _, _, err := client.Repos.Get(ctx, RepoSpec{URI: "r.com/x"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"", "abc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got trace ID headers %q, want %q", got, want)
+	}
+}
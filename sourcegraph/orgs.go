@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -11,16 +12,16 @@ import (
 // Sourcegraph API.
 type OrgsService interface {
 	// Get fetches an organization.
-	Get(org OrgSpec) (*Org, Response, error)
+	Get(ctx context.Context, org OrgSpec) (*Org, Response, error)
 
 	// ListMembers lists members of an organization.
-	ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
+	ListMembers(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error)
 
 	// GetSettings fetches an org's configuration settings.
-	GetSettings(org OrgSpec) (*OrgSettings, Response, error)
+	GetSettings(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error)
 
 	// UpdateSettings updates an org's configuration settings.
-	UpdateSettings(org OrgSpec, settings OrgSettings) (Response, error)
+	UpdateSettings(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error)
 }
 
 // orgsService implements OrgsService.
@@ -69,7 +70,7 @@ func ParseOrgSpec(pathComponent string) (OrgSpec, error) {
 	return OrgSpec{Org: pathComponent}, nil
 }
 
-func (s *orgsService) Get(org OrgSpec) (*Org, Response, error) {
+func (s *orgsService) Get(ctx context.Context, org OrgSpec) (*Org, Response, error) {
 	url, err := s.client.URL(router.Org, org.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -81,7 +82,7 @@ func (s *orgsService) Get(org OrgSpec) (*Org, Response, error) {
 	}
 
 	var org_ *Org
-	resp, err := s.client.Do(req, &org_)
+	resp, err := s.client.Do(ctx, req, &org_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -93,7 +94,7 @@ type OrgListMembersOptions struct {
 	ListOptions
 }
 
-func (s *orgsService) ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
+func (s *orgsService) ListMembers(ctx context.Context, org OrgSpec, opt *OrgListMembersOptions) ([]*User, Response, error) {
 	url, err := s.client.URL(router.OrgMembers, org.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -105,7 +106,7 @@ func (s *orgsService) ListMembers(org OrgSpec, opt *OrgListMembersOptions) ([]*U
 	}
 
 	var members []*User
-	resp, err := s.client.Do(req, &members)
+	resp, err := s.client.Do(ctx, req, &members)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -118,7 +119,7 @@ type OrgSettings struct {
 	PlanSettings `json:",omitempty"`
 }
 
-func (s *orgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
+func (s *orgsService) GetSettings(ctx context.Context, org OrgSpec) (*OrgSettings, Response, error) {
 	url, err := s.client.URL(router.OrgSettings, org.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -130,7 +131,7 @@ func (s *orgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
 	}
 
 	var settings *OrgSettings
-	resp, err := s.client.Do(req, &settings)
+	resp, err := s.client.Do(ctx, req, &settings)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -138,7 +139,7 @@ func (s *orgsService) GetSettings(org OrgSpec) (*OrgSettings, Response, error) {
 	return settings, resp, nil
 }
 
-func (s *orgsService) UpdateSettings(org OrgSpec, settings OrgSettings) (Response, error) {
+func (s *orgsService) UpdateSettings(ctx context.Context, org OrgSpec, settings OrgSettings) (Response, error) {
 	url, err := s.client.URL(router.OrgSettingsUpdate, org.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -149,7 +150,7 @@ func (s *orgsService) UpdateSettings(org OrgSpec, settings OrgSettings) (Respons
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,152 @@
+package sourcegraph
+
+import "context"
+
+// A Pager iterates over all of the results of a paginated list API
+// method (any method whose options embed ListOptions), fetching pages
+// lazily as they are needed.
+//
+// A Pager is created with a fetch func that calls the list method
+// with the given ListOptions. For example, to iterate over all repos:
+//
+//	opt := &RepoListOptions{Owner: "alice"}
+//	p := NewPager(ctx, opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*Repo, Response, error) {
+//		opt.ListOptions = lo
+//		return client.Repos.List(ctx, opt)
+//	})
+//	for p.Next() {
+//		repo := p.Value()
+//		// ...
+//	}
+//	if err := p.Err(); err != nil {
+//		// ...
+//	}
+//
+// Iteration stops after the last page, which is the first page that
+// is shorter than PerPage or that reaches the total count reported
+// by the response's TotalCount.
+//
+// The fetch func is never called concurrently with itself, even if
+// Prefetch is true, so it is safe for it to modify shared options.
+type Pager[T any] struct {
+	// Prefetch is whether to fetch the next page in the background
+	// while the current page is being consumed. It must be set
+	// before the first call to Next.
+	Prefetch bool
+
+	ctx   context.Context
+	fetch func(ctx context.Context, opt ListOptions) ([]T, Response, error)
+	opt   ListOptions // options for the next page to fetch
+
+	page []T // current page
+	i    int // index of the next item in page
+	cur  T
+
+	done    bool // whether the last page has been fetched
+	err     error
+	pending chan pageResult[T] // prefetched next page (if any)
+}
+
+type pageResult[T any] struct {
+	opt   ListOptions // options used to fetch this page
+	items []T
+	resp  Response
+	err   error
+}
+
+// NewPager creates a new Pager that starts at the page specified by
+// opt (or the first page, if opt.Page is not set) and calls fetch to
+// fetch each page.
+func NewPager[T any](ctx context.Context, opt ListOptions, fetch func(ctx context.Context, opt ListOptions) ([]T, Response, error)) *Pager[T] {
+	// Set PerPage explicitly so that we can detect short pages even
+	// if the server's default differs from ours.
+	opt.Page = opt.PageOrDefault()
+	opt.PerPage = opt.PerPageOrDefault()
+	return &Pager[T]{ctx: ctx, fetch: fetch, opt: opt}
+}
+
+// Next advances the pager to the next item, which will then be
+// available through the Value method. It returns false when there
+// are no more items or an error occurred (which is returned by Err).
+func (p *Pager[T]) Next() bool {
+	for p.i >= len(p.page) {
+		if p.done || p.err != nil {
+			return false
+		}
+		res := p.nextPage()
+		if res.err != nil {
+			p.err = res.err
+			return false
+		}
+		p.page, p.i = res.items, 0
+	}
+	p.cur = p.page[p.i]
+	p.i++
+	return true
+}
+
+// Value returns the current item (set by the most recent call to
+// Next).
+func (p *Pager[T]) Value() T { return p.cur }
+
+// Err returns the first error that occurred while fetching pages, if
+// any.
+func (p *Pager[T]) Err() error { return p.err }
+
+// All consumes all of the remaining items and returns them.
+func (p *Pager[T]) All() ([]T, error) {
+	var all []T
+	for p.Next() {
+		all = append(all, p.Value())
+	}
+	return all, p.Err()
+}
+
+// nextPage returns the next page, either by waiting for the pending
+// prefetch or by fetching it now. It then determines whether that
+// was the last page, and starts prefetching the page after it (if
+// enabled).
+func (p *Pager[T]) nextPage() pageResult[T] {
+	var res pageResult[T]
+	if p.pending != nil {
+		res = <-p.pending
+		p.pending = nil
+	} else {
+		res = p.fetchPage(p.opt)
+	}
+	if res.err != nil {
+		return res
+	}
+
+	p.done = isLastPage(res.opt, len(res.items), res.resp)
+	p.opt.Page = res.opt.Page + 1
+	if !p.done && p.Prefetch {
+		// Buffered so that the goroutine doesn't leak if the caller
+		// stops iterating before consuming this page.
+		ch := make(chan pageResult[T], 1)
+		go func(opt ListOptions) {
+			ch <- p.fetchPage(opt)
+		}(p.opt)
+		p.pending = ch
+	}
+	return res
+}
+
+func (p *Pager[T]) fetchPage(opt ListOptions) pageResult[T] {
+	items, resp, err := p.fetch(p.ctx, opt)
+	return pageResult[T]{opt: opt, items: items, resp: resp, err: err}
+}
+
+// isLastPage returns whether a page of n items, fetched with opt, is
+// the last page of results.
+func isLastPage(opt ListOptions, n int, resp Response) bool {
+	if n < opt.PerPageOrDefault() {
+		return true
+	}
+	if resp != nil {
+		if total := resp.TotalCount(); total >= 0 && opt.Offset()+n >= total {
+			return true
+		}
+	}
+	return false
+}
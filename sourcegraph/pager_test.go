@@ -0,0 +1,143 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestPager_totalCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var want []*Repo
+	for i := 1; i <= 25; i++ {
+		want = append(want, &Repo{RID: i})
+	}
+
+	var requests int
+	mux.HandleFunc(urlPath(t, router.Repos, nil), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"Owner": "o", "Page": strconv.Itoa(requests), "PerPage": "10"})
+
+		end := requests * 10
+		if end > len(want) {
+			end = len(want)
+		}
+		w.Header().Set("x-total-count", strconv.Itoa(len(want)))
+		writeJSON(w, want[(requests-1)*10:end])
+	})
+
+	opt := &RepoListOptions{Owner: "o"}
+	p := NewPager(context.Background(), opt.ListOptions, func(ctx context.Context, lo ListOptions) ([]*Repo, Response, error) {
+		opt.ListOptions = lo
+		return client.Repos.List(ctx, opt)
+	})
+	repos, err := p.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+	normRepo(want...)
+	if !reflect.DeepEqual(repos, want) {
+		t.Errorf("got repos %+v, want %+v", repos, want)
+	}
+}
+
+// testPageResponse is a Response with a fixed total count.
+type testPageResponse int
+
+func (r testPageResponse) TotalCount() int { return int(r) }
+
+func TestPager(t *testing.T) {
+	tests := map[string]struct {
+		opt        ListOptions
+		items      int
+		totalCount int
+		wantPages  []int
+	}{
+		"short last page": {
+			opt:        ListOptions{PerPage: 2},
+			items:      5,
+			totalCount: -1,
+			wantPages:  []int{1, 2, 3},
+		},
+		"empty last page": {
+			opt:        ListOptions{PerPage: 2},
+			items:      4,
+			totalCount: -1,
+			wantPages:  []int{1, 2, 3},
+		},
+		"total count": {
+			opt:        ListOptions{PerPage: 2},
+			items:      4,
+			totalCount: 4,
+			wantPages:  []int{1, 2},
+		},
+		"start page": {
+			opt:        ListOptions{PerPage: 2, Page: 2},
+			items:      5,
+			totalCount: 5,
+			wantPages:  []int{2, 3},
+		},
+		"default per page": {
+			items:      DefaultPerPage,
+			totalCount: -1,
+			wantPages:  []int{1, 2},
+		},
+	}
+	for label, test := range tests {
+		for _, prefetch := range []bool{false, true} {
+			var pages []int
+			p := NewPager(context.Background(), test.opt, func(ctx context.Context, opt ListOptions) ([]int, Response, error) {
+				pages = append(pages, opt.Page)
+				var items []int
+				for i := opt.Offset(); i < opt.Offset()+opt.Limit() && i < test.items; i++ {
+					items = append(items, i)
+				}
+				return items, testPageResponse(test.totalCount), nil
+			})
+			p.Prefetch = prefetch
+
+			items, err := p.All()
+			if err != nil {
+				t.Errorf("%s (prefetch=%v): %s", label, prefetch, err)
+				continue
+			}
+			if want := test.items - test.opt.Offset(); len(items) != want {
+				t.Errorf("%s (prefetch=%v): got %d items, want %d", label, prefetch, len(items), want)
+			}
+			if !reflect.DeepEqual(pages, test.wantPages) {
+				t.Errorf("%s (prefetch=%v): got pages %v, want %v", label, prefetch, pages, test.wantPages)
+			}
+		}
+	}
+}
+
+func TestPager_error(t *testing.T) {
+	wantErr := errors.New("x")
+	p := NewPager(context.Background(), ListOptions{PerPage: 1}, func(ctx context.Context, opt ListOptions) ([]int, Response, error) {
+		if opt.Page == 2 {
+			return nil, nil, wantErr
+		}
+		return []int{opt.Page}, nil, nil
+	})
+	if !p.Next() || p.Value() != 1 {
+		t.Fatal("want first item")
+	}
+	if p.Next() {
+		t.Fatal("want no second item")
+	}
+	if err := p.Err(); err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"strconv"
@@ -15,7 +16,7 @@ type PeopleService interface {
 	// Get gets a person. If an email is provided and it resolves to a
 	// registered user, information about that user is
 	// returned. Otherwise a transient person is created and returned.
-	Get(person PersonSpec) (*Person, Response, error)
+	Get(ctx context.Context, person PersonSpec) (*Person, Response, error)
 }
 
 // peopleService implements PeopleService.
@@ -114,7 +115,7 @@ func ParsePersonSpec(pathComponent string) (PersonSpec, error) {
 	return PersonSpec{Login: pathComponent}, nil
 }
 
-func (s *peopleService) Get(spec PersonSpec) (*Person, Response, error) {
+func (s *peopleService) Get(ctx context.Context, spec PersonSpec) (*Person, Response, error) {
 	url, err := s.client.URL(router.Person, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -126,7 +127,7 @@ func (s *peopleService) Get(spec PersonSpec) (*Person, Response, error) {
 	}
 
 	var person *Person
-	resp, err := s.client.Do(req, &person)
+	resp, err := s.client.Do(ctx, req, &person)
 	if err != nil {
 		return nil, resp, err
 	}
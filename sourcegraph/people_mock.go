@@ -1,7 +1,11 @@
 package sourcegraph
 
+import "context"
+
 type MockPeopleService struct {
-	Get_ func(person PersonSpec) (*Person, Response, error)
+	Get_ func(ctx context.Context, person PersonSpec) (*Person, Response, error)
 }
 
-func (s MockPeopleService) Get(person PersonSpec) (*Person, Response, error) { return s.Get_(person) }
+func (s MockPeopleService) Get(ctx context.Context, person PersonSpec) (*Person, Response, error) {
+	return s.Get_(ctx, person)
+}
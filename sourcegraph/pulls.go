@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sourcegraph/go-github/github"
@@ -15,25 +16,25 @@ import (
 // Sourcegraph API.
 type PullRequestsService interface {
 	// Get fetches a pull request.
-	Get(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
+	Get(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
 
 	// List pull requests for a repository.
-	ListByRepo(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
+	ListByRepo(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
 
 	// ListComments lists comments on a pull request.
-	ListComments(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
+	ListComments(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
 
 	// CreateComment creates a comment on a pull request.
-	CreateComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	CreateComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
 
 	// EditComment updates an existing comment on a pull request.
-	EditComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	EditComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
 
 	// DeleteComment deletes a comment on a pull request.
-	DeleteComment(pull PullRequestSpec, commentID int) (Response, error)
+	DeleteComment(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error)
 
 	// Merge merges a pull request
-	Merge(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
+	Merge(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
 }
 
 // pullRequestsService implements PullRequestsService.
@@ -53,7 +54,9 @@ type PullRequestSpec struct {
 // RouteVars returns the route variables for generating pull request
 // URLs.
 func (s PullRequestSpec) RouteVars() map[string]string {
-	return map[string]string{"RepoSpec": s.Repo.URI, "Pull": strconv.Itoa(s.Number)}
+	m := s.Repo.RouteVars()
+	m["Pull"] = strconv.Itoa(s.Number)
+	return m
 }
 
 // IssueSpec returns a specifier for the issue associated with this
@@ -99,7 +102,7 @@ type PullRequestGetOptions struct {
 	Checklist bool
 }
 
-func (s *pullRequestsService) Get(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
+func (s *pullRequestsService) Get(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequest, pull.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -111,7 +114,7 @@ func (s *pullRequestsService) Get(pull PullRequestSpec, opt *PullRequestGetOptio
 	}
 
 	var pull_ *PullRequest
-	resp, err := s.client.Do(req, &pull_)
+	resp, err := s.client.Do(ctx, req, &pull_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -124,7 +127,7 @@ type PullRequestListOptions struct {
 	ListOptions
 }
 
-func (s *pullRequestsService) ListByRepo(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
+func (s *pullRequestsService) ListByRepo(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequests, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -136,7 +139,7 @@ func (s *pullRequestsService) ListByRepo(repo RepoSpec, opt *PullRequestListOpti
 	}
 
 	var pulls []*PullRequest
-	resp, err := s.client.Do(req, &pulls)
+	resp, err := s.client.Do(ctx, req, &pulls)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -186,7 +189,7 @@ func (c PullRequestCommentSpec) RouteVars() map[string]string {
 	return rv
 }
 
-func (s *pullRequestsService) ListComments(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
+func (s *pullRequestsService) ListComments(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestComments, pull.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -198,7 +201,7 @@ func (s *pullRequestsService) ListComments(pull PullRequestSpec, opt *PullReques
 	}
 
 	var comments []*PullRequestComment
-	resp, err := s.client.Do(req, &comments)
+	resp, err := s.client.Do(ctx, req, &comments)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -206,7 +209,7 @@ func (s *pullRequestsService) ListComments(pull PullRequestSpec, opt *PullReques
 	return comments, resp, nil
 }
 
-func (s *pullRequestsService) CreateComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+func (s *pullRequestsService) CreateComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestCommentsCreate, pull.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -218,7 +221,7 @@ func (s *pullRequestsService) CreateComment(pull PullRequestSpec, comment *PullR
 	}
 
 	var createdComment PullRequestComment
-	resp, err := s.client.Do(req, &createdComment)
+	resp, err := s.client.Do(ctx, req, &createdComment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -226,7 +229,7 @@ func (s *pullRequestsService) CreateComment(pull PullRequestSpec, comment *PullR
 	return &createdComment, resp, nil
 }
 
-func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+func (s *pullRequestsService) EditComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
 	if comment.ID == nil {
 		return nil, nil, fmt.Errorf("comment ID not specified")
 	}
@@ -242,7 +245,7 @@ func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullReq
 	}
 
 	var updatedComment PullRequestComment
-	resp, err := s.client.Do(req, &updatedComment)
+	resp, err := s.client.Do(ctx, req, &updatedComment)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -250,7 +253,7 @@ func (s *pullRequestsService) EditComment(pull PullRequestSpec, comment *PullReq
 	return &updatedComment, resp, nil
 }
 
-func (s *pullRequestsService) DeleteComment(pull PullRequestSpec, commentID int) (Response, error) {
+func (s *pullRequestsService) DeleteComment(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestCommentsDelete, PullRequestCommentSpec{Pull: pull, Comment: commentID}.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -261,7 +264,7 @@ func (s *pullRequestsService) DeleteComment(pull PullRequestSpec, commentID int)
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -277,7 +280,7 @@ type PullRequestMergeRequest struct {
 	CommitMessage string
 }
 
-func (s *pullRequestsService) Merge(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
+func (s *pullRequestsService) Merge(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
 	url, err := s.client.URL(router.RepoPullRequestMerge, pull.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -289,7 +292,7 @@ func (s *pullRequestsService) Merge(pull PullRequestSpec, mergeRequest *PullRequ
 	}
 
 	var result PullRequestMergeResult
-	resp, err := s.client.Do(req, &result)
+	resp, err := s.client.Do(ctx, req, &result)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,39 +1,41 @@
 package sourcegraph
 
+import "context"
+
 type MockPullRequestsService struct {
-	Get_           func(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
-	ListByRepo_    func(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
-	ListComments_  func(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
-	CreateComment_ func(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
-	EditComment_   func(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
-	DeleteComment_ func(pull PullRequestSpec, commentID int) (Response, error)
-	Merge_         func(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
+	Get_           func(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error)
+	ListByRepo_    func(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error)
+	ListComments_  func(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error)
+	CreateComment_ func(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	EditComment_   func(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error)
+	DeleteComment_ func(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error)
+	Merge_         func(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error)
 }
 
-func (s MockPullRequestsService) Get(pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
-	return s.Get_(pull, opt)
+func (s MockPullRequestsService) Get(ctx context.Context, pull PullRequestSpec, opt *PullRequestGetOptions) (*PullRequest, Response, error) {
+	return s.Get_(ctx, pull, opt)
 }
 
-func (s MockPullRequestsService) ListByRepo(repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
-	return s.ListByRepo_(repo, opt)
+func (s MockPullRequestsService) ListByRepo(ctx context.Context, repo RepoSpec, opt *PullRequestListOptions) ([]*PullRequest, Response, error) {
+	return s.ListByRepo_(ctx, repo, opt)
 }
 
-func (s MockPullRequestsService) ListComments(pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
-	return s.ListComments_(pull, opt)
+func (s MockPullRequestsService) ListComments(ctx context.Context, pull PullRequestSpec, opt *PullRequestListCommentsOptions) ([]*PullRequestComment, Response, error) {
+	return s.ListComments_(ctx, pull, opt)
 }
 
-func (s MockPullRequestsService) CreateComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
-	return s.CreateComment_(pull, comment)
+func (s MockPullRequestsService) CreateComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+	return s.CreateComment_(ctx, pull, comment)
 }
 
-func (s MockPullRequestsService) EditComment(pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
-	return s.EditComment_(pull, comment)
+func (s MockPullRequestsService) EditComment(ctx context.Context, pull PullRequestSpec, comment *PullRequestComment) (*PullRequestComment, Response, error) {
+	return s.EditComment_(ctx, pull, comment)
 }
 
-func (s MockPullRequestsService) DeleteComment(pull PullRequestSpec, commentID int) (Response, error) {
-	return s.DeleteComment_(pull, commentID)
+func (s MockPullRequestsService) DeleteComment(ctx context.Context, pull PullRequestSpec, commentID int) (Response, error) {
+	return s.DeleteComment_(ctx, pull, commentID)
 }
 
-func (s MockPullRequestsService) Merge(pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
-	return s.Merge_(pull, mergeRequest)
+func (s MockPullRequestsService) Merge(ctx context.Context, pull PullRequestSpec, mergeRequest *PullRequestMergeRequest) (*PullRequestMergeResult, Response, error) {
+	return s.Merge_(ctx, pull, mergeRequest)
 }
@@ -2,6 +2,8 @@ package sourcegraph
 
 import (
 	"bytes"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -46,3 +48,134 @@ func Join(tokens []Token) RawQuery {
 	}
 	return RawQuery{String: buf.String(), InsertionPoint: ip}
 }
+
+// Tokenize splits a raw query into tokens. It is the inverse of Join:
+// for any query string in canonical form (tokens separated by single
+// spaces), Join(Tokenize(q)).String == q.String.
+//
+// Double-quoted strings become Terms (an unterminated quote extends
+// to the end of the query). Words prefixed with ":", "~", "/", and
+// "@" become RevTokens, UnitTokens ("~name" or "~name@type"),
+// FileTokens, and UserTokens, respectively. Words of the form
+// "field:value" (where field is one of FilterFields) become
+// FilterTokens, and words prefixed with "-" become NotTokens that
+// negate the rest of the word. Tokens separated by "OR" (such as "a
+// OR b OR c") become a single OrToken. All other words become
+// AnyTokens, which must be resolved before they have a definite
+// meaning.
+func Tokenize(q RawQuery) Tokens {
+	spans := tokenize(q.String)
+	if spans == nil {
+		return nil
+	}
+	toks := make(Tokens, len(spans))
+	for i, span := range spans {
+		toks[i] = span.tok
+	}
+	return toks
+}
+
+// ActiveTokenIndex returns the 0-indexed index of the token (as
+// returned by Tokenize) that q's InsertionPoint falls in, or -1 if
+// the insertion point is not inside or immediately after any token
+// (e.g., if it is in the whitespace between 2 tokens).
+func ActiveTokenIndex(q RawQuery) int {
+	for i, span := range tokenize(q.String) {
+		if span.start <= q.InsertionPoint && q.InsertionPoint <= span.end {
+			return i
+		}
+	}
+	return -1
+}
+
+// tokenSpan is a token and the character offsets [start, end) of its
+// string representation in the raw query.
+type tokenSpan struct {
+	tok        Token
+	start, end int
+}
+
+func tokenize(s string) []tokenSpan {
+	rs := []rune(s)
+	var spans []tokenSpan
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		if rs[i] == '"' {
+			i++
+			for i < len(rs) && rs[i] != '"' {
+				i++
+			}
+			term := Term(rs[start+1 : i])
+			if i < len(rs) {
+				i++ // consume closing quote
+			}
+			spans = append(spans, tokenSpan{term, start, i})
+			continue
+		}
+
+		for i < len(rs) && !unicode.IsSpace(rs[i]) {
+			i++
+		}
+		spans = append(spans, tokenSpan{parseToken(string(rs[start:i])), start, i})
+	}
+	return groupOr(spans)
+}
+
+// orOperator is the word that separates the operands of an OrToken.
+const orOperator = AnyToken("OR")
+
+// groupOr replaces each sequence of spans separated by "OR" words
+// (such as "a OR b OR c") with a single span of an OrToken. An "OR"
+// word that does not separate 2 other tokens is left as-is.
+func groupOr(spans []tokenSpan) []tokenSpan {
+	isOperand := func(i int) bool { return i < len(spans) && spans[i].tok != orOperator }
+	isOr := func(i int) bool { return i < len(spans) && spans[i].tok == orOperator }
+
+	var grouped []tokenSpan
+	for i := 0; i < len(spans); i++ {
+		if !isOperand(i) || !isOr(i+1) || !isOperand(i+2) {
+			grouped = append(grouped, spans[i])
+			continue
+		}
+		start, operands := spans[i].start, Tokens{spans[i].tok}
+		for ; isOr(i+1) && isOperand(i+2); i += 2 {
+			operands = append(operands, spans[i+2].tok)
+		}
+		grouped = append(grouped, tokenSpan{OrToken{Operands: operands}, start, spans[i].end})
+	}
+	return grouped
+}
+
+// parseToken parses a single unquoted, non-empty word from a raw
+// query.
+func parseToken(word string) Token {
+	switch word[0] {
+	case ':':
+		return RevToken{Rev: word[1:]}
+	case '~':
+		tok := UnitToken{Name: word[1:]}
+		if i := strings.LastIndex(tok.Name, "@"); i != -1 {
+			tok.Name, tok.UnitType = tok.Name[:i], tok.Name[i+1:]
+		}
+		return tok
+	case '/':
+		return FileToken{Path: word[1:]}
+	case '@':
+		return UserToken{Login: word[1:]}
+	case '-':
+		if len(word) > 1 {
+			return NotToken{parseToken(word[1:])}
+		}
+	}
+	if i := strings.Index(word, ":"); i > 0 {
+		if _, ok := FilterFields[word[:i]]; ok {
+			return FilterToken{Field: word[:i], Value: word[i+1:]}
+		}
+	}
+	return AnyToken(word)
+}
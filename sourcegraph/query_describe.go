@@ -0,0 +1,224 @@
+package sourcegraph
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// A Phrase is a phrase that describes a list of items in a query
+// description. It has a format string for each plural form (as
+// chosen by Phrases.PluralForm), in which "%s" is replaced by the
+// list.
+type Phrase []string
+
+// Phrases is a table of the phrases that Describe uses to describe
+// queries. UIs can use their own Phrases (or modify DefaultPhrases)
+// to localize descriptions.
+type Phrases struct {
+	// Subject is the description of what the query searches for
+	// (e.g., "definitions").
+	Subject string
+
+	Terms Phrase // query terms (e.g., `matching "foo"`)
+	Repos Phrase // repositories (e.g., "in github.com/foo/bar")
+	Units Phrase // source units (e.g., "in source unit mypkg")
+	Files Phrase // files (e.g., "in /a/b.go")
+	Dirs  Phrase // directories (e.g., "under /pkg")
+	Users Phrase // users and orgs (e.g., "by @alice")
+
+	Filters  Phrase // filters (e.g., "with kind:func")
+	Excluded Phrase // negated tokens (e.g., "excluding test:yes")
+
+	// Term is the format of a single query term.
+	Term string
+
+	// Filter is the format of a filter's field (the first "%s") and
+	// value (the second "%s").
+	Filter string
+
+	// Or separates the operands of an OR (e.g., "kind:func or
+	// kind:type").
+	Or string
+
+	// RepoRev is the format of a repository (the first "%s") at a
+	// revision (the second "%s").
+	RepoRev string
+
+	// ListSep separates all but the last 2 items of a list, and
+	// ListLastSep separates the last 2 items.
+	ListSep, ListLastSep string
+
+	// PluralForm returns the index of the plural form (in each
+	// Phrase) to use for a list of n items.
+	PluralForm func(n int) int
+}
+
+// DefaultPhrases are the phrases that Describe uses. They are in
+// English.
+var DefaultPhrases = &Phrases{
+	Subject:     "definitions",
+	Terms:       Phrase{"matching %s", "matching all of %s"},
+	Repos:       Phrase{"in %s", "in any of %s"},
+	Units:       Phrase{"in source unit %s", "in source units %s"},
+	Files:       Phrase{"in %s", "in any of %s"},
+	Dirs:        Phrase{"under %s", "under any of %s"},
+	Users:       Phrase{"by %s", "by any of %s"},
+	Filters:     Phrase{"with %s", "with %s"},
+	Excluded:    Phrase{"excluding %s", "excluding %s"},
+	Term:        `"%s"`,
+	Filter:      "%s:%s",
+	Or:          " or ",
+	RepoRev:     "%s at %s",
+	ListSep:     ", ",
+	ListLastSep: " and ",
+	PluralForm: func(n int) int {
+		if n == 1 {
+			return 0
+		}
+		return 1
+	},
+}
+
+// Describe returns a human-readable description of the query
+// consisting of toks, such as "definitions in github.com/foo/bar at
+// v1.2 under /pkg by @alice", using DefaultPhrases.
+func Describe(toks Tokens) string { return DefaultPhrases.Describe(toks) }
+
+// Describe returns a human-readable description of the query
+// consisting of toks, using the phrases in p.
+//
+// AnyTokens are described as terms. FileTokens are described as
+// files if they were resolved to files, and as directories otherwise.
+// RevTokens are described along with the preceding RepoToken. "repo:"
+// filters are described as repositories, NotTokens as the exclusion
+// of the negated token, and OrTokens as their operands joined by
+// p.Or (in the list of the first operand).
+func (p *Phrases) Describe(toks Tokens) string {
+	var lists [numDescLists][]string
+	lastRepo := -1 // index in lists[descRepos] of the last repo
+	for _, tok := range toks {
+		if tok, ok := tok.(RevToken); ok {
+			if repos := lists[descRepos]; lastRepo != -1 && tok.Rev != "" {
+				repos[lastRepo] = fmt.Sprintf(p.RepoRev, repos[lastRepo], tok.Rev)
+				lastRepo = -1
+			}
+			continue
+		}
+		list, item, ok := p.describe(tok)
+		if !ok {
+			continue
+		}
+		lists[list] = append(lists[list], item)
+		if _, isOr := tok.(OrToken); list == descRepos && !isOr {
+			lastRepo = len(lists[descRepos]) - 1
+		} else if isOr {
+			lastRepo = -1 // a RevToken can't follow an OR
+		}
+	}
+
+	parts := []string{p.Subject}
+	for list, phrase := range []Phrase{
+		descTerms:    p.Terms,
+		descRepos:    p.Repos,
+		descUnits:    p.Units,
+		descFiles:    p.Files,
+		descDirs:     p.Dirs,
+		descUsers:    p.Users,
+		descFilters:  p.Filters,
+		descExcluded: p.Excluded,
+	} {
+		if items := lists[list]; len(items) > 0 {
+			parts = append(parts, p.phrase(phrase, items))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// The lists of items in a query description, in the order that they
+// are described.
+const (
+	descTerms = iota
+	descRepos
+	descUnits
+	descFiles
+	descDirs
+	descUsers
+	descFilters
+	descExcluded
+	numDescLists
+)
+
+// describe returns the description of tok and the list (such as
+// descTerms) that it belongs in, or false if tok isn't described.
+// RevTokens aren't described (Describe describes them along with
+// their repository).
+func (p *Phrases) describe(tok Token) (list int, item string, ok bool) {
+	switch tok := tok.(type) {
+	case Term:
+		if tok != "" {
+			return descTerms, fmt.Sprintf(p.Term, tok.UnquotedString()), true
+		}
+	case AnyToken:
+		return descTerms, fmt.Sprintf(p.Term, string(tok)), true
+	case RepoToken:
+		return descRepos, tok.URI, true
+	case UnitToken:
+		return descUnits, tok.Name, true
+	case FileToken:
+		if path.Clean(strings.TrimPrefix(tok.Path, "/")) == "." {
+			return 0, "", false // the whole repository
+		}
+		if tok.Entry != nil && tok.Entry.Type == vcsclient.FileEntry {
+			return descFiles, tok.String(), true
+		}
+		return descDirs, tok.String(), true
+	case UserToken:
+		return descUsers, tok.String(), true
+	case FilterToken:
+		if tok.Field == "repo" {
+			return descRepos, tok.Value, true
+		}
+		return descFilters, fmt.Sprintf(p.Filter, tok.Field, tok.Value), true
+	case NotToken:
+		if _, item, ok := p.describe(tok.Token); ok {
+			return descExcluded, item, true
+		}
+	case OrToken:
+		var items []string
+		for _, op := range tok.Operands {
+			opList, item, ok := p.describe(op)
+			if !ok {
+				continue
+			}
+			if len(items) == 0 {
+				list = opList
+			}
+			items = append(items, item)
+		}
+		if len(items) > 0 {
+			return list, strings.Join(items, p.Or), true
+		}
+	}
+	return 0, "", false
+}
+
+// phrase formats the list of items with the plural form of ph that
+// is appropriate for the number of items.
+func (p *Phrases) phrase(ph Phrase, items []string) string {
+	form := p.PluralForm(len(items))
+	if form >= len(ph) {
+		form = len(ph) - 1
+	}
+	return fmt.Sprintf(ph[form], p.list(items))
+}
+
+// list joins items into a list, such as "a, b and c".
+func (p *Phrases) list(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], p.ListSep) + p.ListLastSep + items[len(items)-1]
+}
@@ -0,0 +1,73 @@
+package sourcegraph
+
+import (
+	"testing"
+
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		toks Tokens
+		want string
+	}{
+		{nil, "definitions"},
+		{
+			Tokens{RepoToken{URI: "github.com/foo/bar"}, RevToken{Rev: "v1.2"}, FileToken{Path: "pkg"}, UserToken{Login: "alice"}},
+			"definitions in github.com/foo/bar at v1.2 under /pkg by @alice",
+		},
+		{
+			Tokens{Term("a b"), AnyToken("c"), Term(""), UnitToken{Name: "u", UnitType: "t"}, FileToken{Path: "a/b.go", Entry: &vcsclient.TreeEntry{Type: vcsclient.FileEntry}}},
+			`definitions matching all of "a b" and "c" in source unit u in /a/b.go`,
+		},
+		{
+			Tokens{RepoToken{URI: "r/a"}, RepoToken{URI: "r/b"}, RevToken{Rev: "v"}, RepoToken{URI: "r/c"}, RevToken{Rev: "v2"}, RevToken{Rev: "v3"}, UnitToken{Name: "u1"}, UnitToken{Name: "u2"}, FileToken{Path: "/"}},
+			"definitions in any of r/a, r/b at v and r/c at v2 in source units u1 and u2",
+		},
+		{
+			Tokens{UserToken{Login: "alice"}, UserToken{Login: "bob"}},
+			"definitions by any of @alice and @bob",
+		},
+		{
+			Tokens{FilterToken{Field: "kind", Value: "func"}, NotToken{FilterToken{Field: "test", Value: "yes"}}},
+			"definitions with kind:func excluding test:yes",
+		},
+		{
+			Tokens{
+				OrToken{Operands: Tokens{FilterToken{Field: "kind", Value: "func"}, FilterToken{Field: "kind", Value: "type"}}},
+				FilterToken{Field: "lang", Value: "go"},
+				FilterToken{Field: "repo", Value: "r/a"}, RevToken{Rev: "v"},
+				OrToken{Operands: Tokens{RepoToken{URI: "r/b"}, FilterToken{Field: "repo", Value: "r/c"}}},
+				NotToken{RepoToken{URI: "r/d"}},
+				NotToken{},
+			},
+			"definitions in any of r/a at v and r/b or r/c with kind:func or kind:type and lang:go excluding r/d",
+		},
+	}
+	for _, test := range tests {
+		if got := Describe(test.toks); got != test.want {
+			t.Errorf("%v: got %q, want %q", test.toks, got, test.want)
+		}
+	}
+}
+
+func TestPhrases_Describe(t *testing.T) {
+	p := *DefaultPhrases
+	p.Subject = "définitions"
+	p.Repos = Phrase{"dans %s", "dans %s"}
+	p.Users = Phrase{"par %s", "par %s"}
+	p.RepoRev = "%s à %s"
+	p.ListLastSep = " et "
+	p.PluralForm = func(n int) int {
+		if n <= 1 {
+			return 0
+		}
+		return 1
+	}
+
+	toks := Tokens{RepoToken{URI: "r/a"}, RevToken{Rev: "v"}, UserToken{Login: "alice"}, UserToken{Login: "bob"}}
+	want := "définitions dans r/a à v par @alice et @bob"
+	if got := p.Describe(toks); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
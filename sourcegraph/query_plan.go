@@ -0,0 +1,449 @@
+package sourcegraph
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// NewPlan compiles resolved tokens into a query plan, with the same
+// semantics as the plans that the server returns in
+// SearchResults.Plan:
+//
+//   - RepoTokens (each optionally followed by a RevToken) constrain
+//     the defs to those repository revisions, and the repos to those
+//     URIs. So do "repo:" filters.
+//   - A UnitToken constrains the defs to a source unit.
+//   - A FileToken constrains the defs to a file (if it resolved to a
+//     file) or to the files underneath a directory.
+//   - A UserToken constrains the repos to those owned by the user.
+//   - "kind:", "lang:", "exported:", and "test:" filters constrain
+//     the defs (to a kind, a language's source unit type, exported
+//     defs, and whether defs in test files are included). "fork:"
+//     and "type:" filters constrain the repos.
+//   - NotTokens negate "exported:", "test:", "fork:", and "type:"
+//     filters. Negated repositories (RepoTokens and "repo:" filters)
+//     are excluded from the repositories that the query lists; they
+//     can't exclude repositories from an unscoped search.
+//   - OrTokens of "kind:" filters constrain the defs to any of the
+//     kinds, and OrTokens of repositories are equivalent to
+//     listing the repositories.
+//   - Terms are joined into the Query of each list options.
+//
+// Repos are only searched if no token scopes the query to within
+// repositories (RevToken, UnitToken, or FileToken), and users are
+// only searched if the query consists only of Terms.
+//
+// Tokens that can't be used (such as unresolved AnyTokens, or a
+// RevToken without a preceding RepoToken) are returned as
+// TokenErrors, and the plan is built from the other tokens.
+func NewPlan(toks Tokens) (*Plan, []TokenError) {
+	p := &planner{
+		plan: &Plan{
+			Repos: &RepoListOptions{},
+			Defs:  &DefListOptions{},
+			Users: &UsersListOptions{},
+		},
+		onlyTerms: true,
+		lastRepo:  -1,
+		seen:      map[string]bool{},
+	}
+	for i, tok := range toks {
+		if _, ok := tok.(Term); !ok {
+			p.onlyTerms = false
+		}
+		p.add(i, tok)
+	}
+	p.excludeRepos()
+
+	if q := strings.Join(p.terms, " "); q != "" {
+		p.plan.Repos.Query = q
+		p.plan.Defs.Query = q
+		p.plan.Users.Query = q
+	}
+	if p.scoped {
+		p.plan.Repos = nil
+	}
+	if !p.onlyTerms {
+		p.plan.Users = nil
+	}
+	return p.plan, p.errs
+}
+
+// planner holds the state of a call to NewPlan.
+type planner struct {
+	plan  *Plan
+	errs  []TokenError
+	terms []string
+
+	scoped      bool // whether the query is scoped to within repos
+	onlyTerms   bool // whether the query consists only of Terms
+	lastRepo    int  // index in plan.Defs.RepoRevs of the last repo
+	lastRepoRev bool // whether the last repo had a RevToken
+
+	// excluded are the negated repositories, which are removed from
+	// the listed repositories after all tokens are added.
+	excluded []excludedRepo
+
+	// seen records the kinds of tokens that may only be specified
+	// once ("unit", "file", "user", and filter fields).
+	seen map[string]bool
+}
+
+// An excludedRepo is a negated repository (the i'th token, tok).
+type excludedRepo struct {
+	i   int
+	tok Token
+	uri string
+}
+
+func (p *planner) tokenError(i int, tok Token, format string, a ...interface{}) {
+	p.errs = append(p.errs, TokenError{Index: i + 1, Token: tok, Message: fmt.Sprintf(format, a...)})
+}
+
+// once records that the kind of token named by what was specified,
+// and returns false if it was already specified.
+func (p *planner) once(what string) bool {
+	if p.seen[what] {
+		return false
+	}
+	p.seen[what] = true
+	return true
+}
+
+// add adds the i'th token, tok, to the plan.
+func (p *planner) add(i int, tok Token) {
+	switch tok := tok.(type) {
+	case Term:
+		if tok != "" {
+			p.terms = append(p.terms, tok.UnquotedString())
+		}
+
+	case RepoToken:
+		p.addRepo(i, tok, tok.uri())
+
+	case RevToken:
+		rev := tok.Rev
+		if tok.Commit != nil && tok.Commit.Commit != nil {
+			rev = string(tok.Commit.ID)
+		}
+		switch {
+		case p.lastRepo == -1:
+			p.tokenError(i, tok, "A revision must follow a repository.")
+		case p.lastRepoRev:
+			p.tokenError(i, tok, "Only one revision may be specified per repository.")
+		case rev == "":
+			p.tokenError(i, tok, "Empty revision.")
+		default:
+			p.plan.Defs.RepoRevs[p.lastRepo] += "@" + rev
+			p.lastRepoRev = true
+			p.scoped = true
+		}
+
+	case UnitToken:
+		unitType, name := tok.UnitType, tok.Name
+		if tok.Unit != nil {
+			unitType, name = tok.Unit.UnitType, tok.Unit.Unit
+		}
+		switch {
+		case name == "":
+			p.tokenError(i, tok, "Empty source unit.")
+		case !p.once("unit"):
+			p.tokenError(i, tok, "Only one source unit may be specified.")
+		case unitType != "" && !p.setUnitType(unitType):
+			p.tokenError(i, tok, "Only one language or source unit type may be specified.")
+		default:
+			p.plan.Defs.Unit = name
+			p.scoped = true
+		}
+
+	case FileToken:
+		if !p.once("file") {
+			p.tokenError(i, tok, "Only one file or directory may be specified.")
+			return
+		}
+		p.scoped = true
+		filePath := path.Clean(strings.TrimPrefix(tok.Path, "/"))
+		if tok.Entry != nil && tok.Entry.Type == vcsclient.FileEntry {
+			p.plan.Defs.File = filePath
+		} else if filePath != "." {
+			p.plan.Defs.FilePathPrefix = filePath + "/"
+		}
+
+	case UserToken:
+		login := tok.Login
+		if tok.User != nil {
+			login = tok.User.Login
+		}
+		switch {
+		case login == "":
+			p.tokenError(i, tok, "Empty user or organization.")
+		case !p.once("user"):
+			p.tokenError(i, tok, "Only one user or organization may be specified.")
+		default:
+			p.plan.Repos.Owner = login
+		}
+
+	case FilterToken:
+		p.addFilter(i, tok, false)
+
+	case NotToken:
+		switch t := tok.Token.(type) {
+		case RepoToken:
+			p.excludeRepo(i, tok, t.uri())
+			return
+		case FilterToken:
+			if t.Field == "repo" {
+				p.excludeRepo(i, tok, t.Value)
+				return
+			}
+			if negatableFilterFields[t.Field] {
+				p.addFilter(i, t, true)
+				return
+			}
+		}
+		p.tokenError(i, tok, "Only repositories and exported:, test:, fork:, and type: filters can be negated.")
+
+	case OrToken:
+		p.addOr(i, tok)
+
+	case AnyToken:
+		p.tokenError(i, tok, "Unresolved token %q.", string(tok))
+
+	default:
+		p.tokenError(i, tok, "Unrecognized token type %s.", tokenTypeOrNil(tok))
+	}
+}
+
+func (p *planner) addRepo(i int, tok Token, uri string) {
+	if uri == "" {
+		p.tokenError(i, tok, "Empty repository.")
+		return
+	}
+	p.plan.Repos.URIs = append(p.plan.Repos.URIs, uri)
+	p.plan.Defs.RepoRevs = append(p.plan.Defs.RepoRevs, uri)
+	p.lastRepo, p.lastRepoRev = len(p.plan.Defs.RepoRevs)-1, false
+}
+
+// excludeRepo records that the negated repository tok (the i'th
+// token) is to be excluded from the listed repositories.
+func (p *planner) excludeRepo(i int, tok Token, uri string) {
+	if uri == "" {
+		p.tokenError(i, tok, "Empty repository.")
+		return
+	}
+	p.excluded = append(p.excluded, excludedRepo{i: i, tok: tok, uri: uri})
+}
+
+// excludeRepos removes the negated repositories from the listed
+// repositories. If no repositories are listed (or all of them would
+// be removed), the negated repositories are returned as errors
+// instead, because the plan can't express that.
+func (p *planner) excludeRepos() {
+	if len(p.excluded) == 0 {
+		return
+	}
+	if len(p.plan.Defs.RepoRevs) == 0 {
+		for _, ex := range p.excluded {
+			p.tokenError(ex.i, ex.tok, "Repositories can only be excluded from the repositories listed in the query.")
+		}
+		return
+	}
+
+	exclude := map[string]bool{}
+	for _, ex := range p.excluded {
+		exclude[ex.uri] = true
+	}
+	var repoRevs, uris []string
+	for _, repoRev := range p.plan.Defs.RepoRevs {
+		if repo, _ := ParseRepoAndCommitID(repoRev); !exclude[repo] {
+			repoRevs = append(repoRevs, repoRev)
+		}
+	}
+	for _, uri := range p.plan.Repos.URIs {
+		if !exclude[uri] {
+			uris = append(uris, uri)
+		}
+	}
+	if len(repoRevs) == 0 {
+		for _, ex := range p.excluded {
+			p.tokenError(ex.i, ex.tok, "All of the repositories listed in the query are excluded.")
+		}
+		return
+	}
+	p.plan.Defs.RepoRevs = repoRevs
+	p.plan.Repos.URIs = uris
+}
+
+// setUnitType sets the defs' source unit type, and returns false if
+// a different one was already set.
+func (p *planner) setUnitType(unitType string) bool {
+	if t := p.plan.Defs.UnitType; t != "" && t != unitType {
+		return false
+	}
+	p.plan.Defs.UnitType = unitType
+	return true
+}
+
+// negatableFilterFields are the fields of the filters that may be
+// negated (because their values are booleans or have an opposite).
+var negatableFilterFields = map[string]bool{"exported": true, "test": true, "fork": true, "type": true}
+
+// langUnitTypes maps the values of "lang:" filters to the source unit
+// types of the languages.
+var langUnitTypes = map[string]string{
+	"go":         "GoPackage",
+	"java":       "JavaArtifact",
+	"javascript": "CommonJSPackage",
+	"python":     "PipPackage",
+	"ruby":       "RubyGem",
+}
+
+// addFilter adds the "field:value" filter tok (the i'th token) to the
+// plan. If negate is true, it adds the negation of the filter (which
+// must be one of negatableFilterFields).
+func (p *planner) addFilter(i int, tok FilterToken, negate bool) {
+	if tok.Field == "repo" {
+		p.addRepo(i, tok, tok.Value)
+		return
+	}
+	errTok := Token(tok)
+	if negate {
+		errTok = NotToken{tok}
+	}
+	if !p.once(tok.Field) {
+		p.tokenError(i, errTok, "Only one %s: filter may be specified.", tok.Field)
+		return
+	}
+
+	switch tok.Field {
+	case "kind":
+		if tok.Value == "" {
+			p.tokenError(i, errTok, "Empty kind.")
+			return
+		}
+		p.plan.Defs.Kinds = []string{tok.Value}
+
+	case "lang":
+		unitType, ok := langUnitTypes[strings.ToLower(tok.Value)]
+		if !ok {
+			p.tokenError(i, errTok, "Unrecognized language %q.", tok.Value)
+		} else if !p.setUnitType(unitType) {
+			p.tokenError(i, errTok, "Only one language or source unit type may be specified.")
+		}
+
+	case "exported", "test", "fork":
+		v, ok := parseFilterBool(tok.Value)
+		if !ok {
+			p.tokenError(i, errTok, "The value of a %s: filter must be yes or no.", tok.Field)
+			return
+		}
+		if negate {
+			v = !v
+		}
+		switch tok.Field {
+		case "exported":
+			if !v {
+				p.tokenError(i, errTok, "Searching for only unexported definitions is not supported.")
+				return
+			}
+			p.plan.Defs.Exported = true
+		case "test":
+			p.plan.Defs.IncludeTest = v
+		case "fork":
+			p.plan.Repos.NoFork = !v
+		}
+
+	case "type":
+		typ := strings.ToLower(tok.Value)
+		if typ != "public" && typ != "private" {
+			p.tokenError(i, errTok, "The value of a type: filter must be public or private.")
+			return
+		}
+		if negate {
+			if typ == "public" {
+				typ = "private"
+			} else {
+				typ = "public"
+			}
+		}
+		p.plan.Repos.Type = typ
+
+	default:
+		p.tokenError(i, errTok, "Unrecognized filter %s:.", tok.Field)
+	}
+}
+
+// addOr adds the OrToken tok (the i'th token) to the plan. Only ORs of
+// "kind:" filters and of repositories can be planned.
+func (p *planner) addOr(i int, tok OrToken) {
+	var kinds, repos []string
+	for _, op := range tok.Operands {
+		switch op := op.(type) {
+		case FilterToken:
+			switch op.Field {
+			case "kind":
+				kinds = append(kinds, op.Value)
+				continue
+			case "repo":
+				repos = append(repos, op.Value)
+				continue
+			}
+		case RepoToken:
+			repos = append(repos, op.uri())
+			continue
+		}
+		kinds, repos = nil, nil
+		break
+	}
+
+	switch {
+	case len(tok.Operands) == 0:
+		p.tokenError(i, tok, "Empty OR.")
+	case len(kinds) == len(tok.Operands):
+		if !p.once("kind") {
+			p.tokenError(i, tok, "Only one kind: filter may be specified.")
+			return
+		}
+		p.plan.Defs.Kinds = kinds
+	case len(repos) == len(tok.Operands):
+		for _, repo := range repos {
+			p.addRepo(i, tok, repo)
+		}
+		p.lastRepo = -1 // a RevToken can't follow an OR
+	default:
+		p.tokenError(i, tok, "OR is only supported between kind: filters and between repositories.")
+	}
+}
+
+// parseFilterBool parses the value of a boolean filter, such as
+// "exported:yes".
+func parseFilterBool(v string) (value, ok bool) {
+	switch strings.ToLower(v) {
+	case "yes", "true":
+		return true, true
+	case "no", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// tokenTypeOrNil is TokenType, except that it returns "nil" for a nil
+// token (instead of panicking).
+func tokenTypeOrNil(tok Token) string {
+	if tok == nil {
+		return "nil"
+	}
+	return TokenType(tok)
+}
+
+// uri returns the URI of the repository that t resolved to, or t.URI
+// if it wasn't resolved.
+func (t RepoToken) uri() string {
+	if t.Repo != nil {
+		return t.Repo.URI
+	}
+	return t.URI
+}
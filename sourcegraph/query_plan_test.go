@@ -0,0 +1,212 @@
+package sourcegraph
+
+import (
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+func TestNewPlan(t *testing.T) {
+	commitID := "0123456789012345678901234567890123456789"
+	tests := map[string]struct {
+		toks     Tokens
+		want     *Plan
+		wantErrs []TokenError
+	}{
+		"empty": {
+			toks: nil,
+			want: &Plan{Repos: &RepoListOptions{}, Defs: &DefListOptions{}, Users: &UsersListOptions{}},
+		},
+		"terms": {
+			toks: Tokens{Term("a"), Term(""), Term("b c")},
+			want: &Plan{
+				Repos: &RepoListOptions{Query: "a b c"},
+				Defs:  &DefListOptions{Query: "a b c"},
+				Users: &UsersListOptions{Query: "a b c"},
+			},
+		},
+		"repos and revs": {
+			toks: Tokens{
+				RepoToken{URI: "r.com/a"}, RevToken{Rev: "v1"},
+				RepoToken{URI: "r.com/b", Repo: &Repo{URI: "r.com/B"}}, RevToken{Rev: "master", Commit: &Commit{&vcs.Commit{ID: vcs.CommitID(commitID)}}},
+				RepoToken{URI: "r.com/c"},
+				Term("x"),
+			},
+			want: &Plan{
+				Defs: &DefListOptions{RepoRevs: []string{"r.com/a@v1", "r.com/B@" + commitID, "r.com/c"}, Query: "x"},
+			},
+		},
+		"repos without revs": {
+			toks: Tokens{RepoToken{URI: "r.com/a"}, UserToken{Login: "alice"}},
+			want: &Plan{
+				Repos: &RepoListOptions{URIs: []string{"r.com/a"}, Owner: "alice"},
+				Defs:  &DefListOptions{RepoRevs: []string{"r.com/a"}},
+			},
+		},
+		"unit and file": {
+			toks: Tokens{
+				UnitToken{Name: "u", UnitType: "t", Unit: &unit.RepoSourceUnit{Unit: "u2", UnitType: "t2"}},
+				FileToken{Path: "a/b.go", Entry: &vcsclient.TreeEntry{Type: vcsclient.FileEntry}},
+			},
+			want: &Plan{Defs: &DefListOptions{Unit: "u2", UnitType: "t2", File: "a/b.go"}},
+		},
+		"dir": {
+			toks: Tokens{FileToken{Path: "a/b/"}},
+			want: &Plan{Defs: &DefListOptions{FilePathPrefix: "a/b/"}},
+		},
+		"root dir": {
+			toks: Tokens{FileToken{Path: ""}},
+			want: &Plan{Defs: &DefListOptions{}},
+		},
+		"filters": {
+			toks: Tokens{
+				FilterToken{Field: "kind", Value: "func"},
+				FilterToken{Field: "lang", Value: "Go"},
+				FilterToken{Field: "exported", Value: "yes"},
+				FilterToken{Field: "test", Value: "no"},
+				FilterToken{Field: "fork", Value: "no"},
+				FilterToken{Field: "type", Value: "public"},
+				Term("x"),
+			},
+			want: &Plan{
+				Repos: &RepoListOptions{Query: "x", NoFork: true, Type: "public"},
+				Defs:  &DefListOptions{Query: "x", Kinds: []string{"func"}, UnitType: "GoPackage", Exported: true},
+			},
+		},
+		"negated filters": {
+			toks: Tokens{
+				NotToken{FilterToken{Field: "test", Value: "no"}},
+				NotToken{FilterToken{Field: "fork", Value: "yes"}},
+				NotToken{FilterToken{Field: "type", Value: "public"}},
+			},
+			want: &Plan{
+				Repos: &RepoListOptions{NoFork: true, Type: "private"},
+				Defs:  &DefListOptions{IncludeTest: true},
+			},
+		},
+		"or": {
+			toks: Tokens{
+				OrToken{Operands: Tokens{FilterToken{Field: "kind", Value: "func"}, FilterToken{Field: "kind", Value: "type"}}},
+				OrToken{Operands: Tokens{RepoToken{URI: "r.com/a"}, FilterToken{Field: "repo", Value: "r.com/b"}}},
+			},
+			want: &Plan{
+				Repos: &RepoListOptions{URIs: []string{"r.com/a", "r.com/b"}},
+				Defs:  &DefListOptions{RepoRevs: []string{"r.com/a", "r.com/b"}, Kinds: []string{"func", "type"}},
+			},
+		},
+		"excluded repos": {
+			toks: Tokens{
+				RepoToken{URI: "r.com/a"}, RevToken{Rev: "v1"},
+				FilterToken{Field: "repo", Value: "r.com/b"},
+				RepoToken{URI: "r.com/c"},
+				NotToken{FilterToken{Field: "repo", Value: "r.com/a"}},
+				NotToken{RepoToken{URI: "r.com/x", Repo: &Repo{URI: "r.com/c"}}},
+			},
+			want: &Plan{
+				Defs: &DefListOptions{RepoRevs: []string{"r.com/b"}},
+			},
+		},
+		"excluded repos errors": {
+			toks: Tokens{
+				NotToken{FilterToken{Field: "repo", Value: "vendor/x"}},
+				NotToken{FilterToken{Field: "repo", Value: ""}},
+				Term("x"),
+			},
+			want: &Plan{
+				Repos: &RepoListOptions{Query: "x"},
+				Defs:  &DefListOptions{Query: "x"},
+			},
+			wantErrs: []TokenError{
+				{Index: 2, Token: NotToken{FilterToken{Field: "repo", Value: ""}}, Message: "Empty repository."},
+				{Index: 1, Token: NotToken{FilterToken{Field: "repo", Value: "vendor/x"}}, Message: "Repositories can only be excluded from the repositories listed in the query."},
+			},
+		},
+		"all repos excluded": {
+			toks: Tokens{
+				FilterToken{Field: "repo", Value: "r.com/a"},
+				NotToken{RepoToken{URI: "r.com/a"}},
+			},
+			want: &Plan{
+				Repos: &RepoListOptions{URIs: []string{"r.com/a"}},
+				Defs:  &DefListOptions{RepoRevs: []string{"r.com/a"}},
+			},
+			wantErrs: []TokenError{
+				{Index: 2, Token: NotToken{RepoToken{URI: "r.com/a"}}, Message: "All of the repositories listed in the query are excluded."},
+			},
+		},
+		"filter errors": {
+			toks: Tokens{
+				FilterToken{Field: "kind", Value: "func"}, FilterToken{Field: "kind", Value: "type"},
+				FilterToken{Field: "exported", Value: "no"},
+				FilterToken{Field: "test", Value: "maybe"},
+				FilterToken{Field: "type", Value: "secret"},
+				FilterToken{Field: "x", Value: "y"},
+				NotToken{FilterToken{Field: "kind", Value: "func"}},
+				NotToken{UserToken{Login: "alice"}},
+				OrToken{Operands: Tokens{FilterToken{Field: "kind", Value: "func"}, RepoToken{URI: "r.com/a"}}},
+				OrToken{Operands: Tokens{RepoToken{URI: "r.com/a"}, RepoToken{URI: "r.com/b"}}}, RevToken{Rev: "v1"},
+				UnitToken{Name: "u", UnitType: "GoPackage"},
+				FilterToken{Field: "lang", Value: "java"},
+			},
+			want: &Plan{
+				Defs: &DefListOptions{RepoRevs: []string{"r.com/a", "r.com/b"}, Kinds: []string{"func"}, Unit: "u", UnitType: "GoPackage"},
+			},
+			wantErrs: []TokenError{
+				{Index: 2, Token: FilterToken{Field: "kind", Value: "type"}, Message: "Only one kind: filter may be specified."},
+				{Index: 3, Token: FilterToken{Field: "exported", Value: "no"}, Message: "Searching for only unexported definitions is not supported."},
+				{Index: 4, Token: FilterToken{Field: "test", Value: "maybe"}, Message: "The value of a test: filter must be yes or no."},
+				{Index: 5, Token: FilterToken{Field: "type", Value: "secret"}, Message: "The value of a type: filter must be public or private."},
+				{Index: 6, Token: FilterToken{Field: "x", Value: "y"}, Message: "Unrecognized filter x:."},
+				{Index: 7, Token: NotToken{FilterToken{Field: "kind", Value: "func"}}, Message: "Only repositories and exported:, test:, fork:, and type: filters can be negated."},
+				{Index: 8, Token: NotToken{UserToken{Login: "alice"}}, Message: "Only repositories and exported:, test:, fork:, and type: filters can be negated."},
+				{Index: 9, Token: OrToken{Operands: Tokens{FilterToken{Field: "kind", Value: "func"}, RepoToken{URI: "r.com/a"}}}, Message: "OR is only supported between kind: filters and between repositories."},
+				{Index: 11, Token: RevToken{Rev: "v1"}, Message: "A revision must follow a repository."},
+				{Index: 13, Token: FilterToken{Field: "lang", Value: "java"}, Message: "Only one language or source unit type may be specified."},
+			},
+		},
+		"unrecognized language": {
+			toks: Tokens{FilterToken{Field: "lang", Value: "cobol"}},
+			want: &Plan{Repos: &RepoListOptions{}, Defs: &DefListOptions{}},
+			wantErrs: []TokenError{
+				{Index: 1, Token: FilterToken{Field: "lang", Value: "cobol"}, Message: `Unrecognized language "cobol".`},
+			},
+		},
+		"errors": {
+			toks: Tokens{
+				RevToken{Rev: "v1"},
+				RepoToken{URI: "r.com/a"}, RevToken{Rev: ""}, RevToken{Rev: "v1"}, RevToken{Rev: "v2"},
+				UnitToken{Name: "u"}, UnitToken{Name: "u2"},
+				FileToken{Path: "a"}, FileToken{Path: "b"},
+				UserToken{}, UserToken{Login: "alice"}, UserToken{Login: "bob"},
+				AnyToken("x"),
+				nil,
+			},
+			want: &Plan{
+				Defs: &DefListOptions{RepoRevs: []string{"r.com/a@v1"}, Unit: "u", FilePathPrefix: "a/"},
+			},
+			wantErrs: []TokenError{
+				{Index: 1, Token: RevToken{Rev: "v1"}, Message: "A revision must follow a repository."},
+				{Index: 3, Token: RevToken{Rev: ""}, Message: "Empty revision."},
+				{Index: 5, Token: RevToken{Rev: "v2"}, Message: "Only one revision may be specified per repository."},
+				{Index: 7, Token: UnitToken{Name: "u2"}, Message: "Only one source unit may be specified."},
+				{Index: 9, Token: FileToken{Path: "b"}, Message: "Only one file or directory may be specified."},
+				{Index: 10, Token: UserToken{}, Message: "Empty user or organization."},
+				{Index: 12, Token: UserToken{Login: "bob"}, Message: "Only one user or organization may be specified."},
+				{Index: 13, Token: AnyToken("x"), Message: `Unresolved token "x".`},
+				{Index: 14, Token: nil, Message: "Unrecognized token type nil."},
+			},
+		},
+	}
+	for label, test := range tests {
+		plan, errs := NewPlan(test.toks)
+		if !reflect.DeepEqual(plan, test.want) {
+			t.Errorf("%s: got plan %s, want %s", label, plan, test.want)
+		}
+		if !reflect.DeepEqual(errs, test.wantErrs) {
+			t.Errorf("%s: got errors %+v, want %+v", label, errs, test.wantErrs)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
+
+// A Resolution is the result of resolving a query's tokens (with
+// (*Client).Resolve). Its fields have the same meaning as those of
+// SearchResults.
+type Resolution struct {
+	// ResolvedTokens holds the resolved tokens, in the same order as
+	// the original tokens. Tokens that could not be resolved (which
+	// are described in ResolveErrors) are left as they were.
+	ResolvedTokens Tokens
+
+	ResolveErrors []TokenError `json:",omitempty"`
+
+	// Tips are helpful tips for the user about their query.
+	Tips []TokenError `json:",omitempty"`
+
+	// Ambiguous holds the candidate resolutions of the AnyTokens that
+	// could be resolved in more than one way.
+	Ambiguous []AmbiguousToken `json:",omitempty"`
+}
+
+// An AmbiguousToken is an AnyToken that could be resolved in more than
+// one way.
+type AmbiguousToken struct {
+	// Index is the 1-indexed index of the token (like
+	// TokenError.Index).
+	Index int
+
+	// Candidates are the possible resolutions of the token.
+	Candidates Tokens
+}
+
+// Resolve resolves toks using the API. It upgrades AnyTokens into
+// RepoTokens, UserTokens, UnitTokens, or FileTokens (or Terms, if
+// they don't refer to anything), and fills in the Repo, Commit, Unit,
+// Entry, and User fields of the typed tokens.
+//
+// RevTokens and FileTokens (and AnyTokens that resolve to source
+// units, files, or directories) are resolved in the repository (and
+// revision) of the closest preceding RepoToken. UnitTokens are too,
+// or in all repositories if there is no preceding RepoToken.
+//
+// Tokens that don't exist (or can't be resolved) are reported in the
+// returned Resolution's ResolveErrors, and ambiguous AnyTokens in its
+// Ambiguous. Only other errors (such as network errors) are returned
+// as errors.
+func (c *Client) Resolve(ctx context.Context, toks Tokens) (*Resolution, error) {
+	r := &resolver{c: c, res: &Resolution{}}
+	if toks != nil {
+		r.res.ResolvedTokens = make(Tokens, len(toks))
+	}
+	for i, tok := range toks {
+		resolved, err := r.resolve(ctx, i, tok)
+		if err != nil {
+			return nil, err
+		}
+		r.res.ResolvedTokens[i] = resolved
+	}
+	return r.res, nil
+}
+
+// resolver holds the state of a call to Resolve.
+type resolver struct {
+	c   *Client
+	res *Resolution
+
+	// repoRev is the repository (and revision) of the closest
+	// preceding resolved RepoToken (and RevToken), or nil if there
+	// is none.
+	repoRev *RepoRevSpec
+}
+
+func (r *resolver) tokenError(i int, tok Token, format string, a ...interface{}) {
+	r.res.ResolveErrors = append(r.res.ResolveErrors, TokenError{Index: i + 1, Token: tok, Message: fmt.Sprintf(format, a...)})
+}
+
+func (r *resolver) tip(i int, tok Token, format string, a ...interface{}) {
+	r.res.Tips = append(r.res.Tips, TokenError{Index: i + 1, Token: tok, Message: fmt.Sprintf(format, a...)})
+}
+
+// resolve resolves the i'th token, tok, and returns the resolved token
+// (or tok, if it can't be resolved).
+func (r *resolver) resolve(ctx context.Context, i int, tok Token) (Token, error) {
+	switch tok := tok.(type) {
+	case RepoToken:
+		r.repoRev = nil
+		if tok.Repo == nil {
+			repo, err := r.getRepo(ctx, tok.URI)
+			if err != nil {
+				return nil, err
+			}
+			if repo == nil {
+				r.tokenError(i, tok, "Repository %q does not exist.", tok.URI)
+				return tok, nil
+			}
+			tok = RepoToken{URI: repo.URI, Repo: repo}
+		}
+		r.repoRev = &RepoRevSpec{RepoSpec: tok.Spec()}
+		return tok, nil
+
+	case RevToken:
+		if r.repoRev == nil {
+			r.tokenError(i, tok, "A revision must follow a repository.")
+			return tok, nil
+		}
+		if tok.Commit == nil {
+			spec := RepoRevSpec{RepoSpec: r.repoRev.RepoSpec, Rev: tok.Rev}
+			commit, _, err := r.c.Repos.GetCommit(ctx, spec, nil)
+			if isNotFound(err) {
+				r.tokenError(i, tok, "Revision %q does not exist in repository %q.", tok.Rev, r.repoRev.URI)
+				return tok, nil
+			} else if err != nil {
+				return nil, err
+			}
+			tok.Commit = commit
+		}
+		repoRev := RepoRevSpec{RepoSpec: r.repoRev.RepoSpec, Rev: tok.Rev}
+		if tok.Commit != nil && tok.Commit.Commit != nil {
+			repoRev.CommitID = string(tok.Commit.ID)
+		}
+		r.repoRev = &repoRev
+		return tok, nil
+
+	case UnitToken:
+		if tok.Unit != nil {
+			return tok, nil
+		}
+		units, err := r.listUnits(ctx, tok.Name, tok.UnitType)
+		if err != nil {
+			return nil, err
+		}
+		switch len(units) {
+		case 0:
+			r.tokenError(i, tok, "Source unit %q does not exist.", tok.Name)
+			return tok, nil
+		case 1:
+			return unitToken(units[0]), nil
+		}
+		cands := make(Tokens, len(units))
+		for j, u := range units {
+			cands[j] = unitToken(u)
+		}
+		r.ambiguous(i, tok, cands)
+		return tok, nil
+
+	case FileToken:
+		if tok.Entry != nil {
+			return tok, nil
+		}
+		if r.repoRev == nil {
+			r.tokenError(i, tok, "A file or directory must follow a repository.")
+			return tok, nil
+		}
+		fileTok, err := r.getFile(ctx, tok.Path)
+		if err != nil {
+			return nil, err
+		}
+		if fileTok == nil {
+			r.tokenError(i, tok, "File or directory %q does not exist.", tok.Path)
+			return tok, nil
+		}
+		return *fileTok, nil
+
+	case UserToken:
+		if tok.User != nil {
+			return tok, nil
+		}
+		user, err := r.getUser(ctx, tok.Login)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			r.tokenError(i, tok, "User or organization %q does not exist.", tok.Login)
+			return tok, nil
+		}
+		return UserToken{Login: user.Login, User: user}, nil
+
+	case AnyToken:
+		return r.resolveAny(ctx, i, tok)
+
+	case NotToken:
+		// A negated token does not change the repository that
+		// following tokens are resolved in.
+		repoRev := r.repoRev
+		defer func() { r.repoRev = repoRev }()
+		resolved, err := r.resolve(ctx, i, tok.Token)
+		if err != nil {
+			return nil, err
+		}
+		return NotToken{resolved}, nil
+
+	case OrToken:
+		// Each operand is resolved independently, and the OR does not
+		// change the repository that following tokens are resolved
+		// in.
+		repoRev := r.repoRev
+		defer func() { r.repoRev = repoRev }()
+		operands := make(Tokens, len(tok.Operands))
+		for j, op := range tok.Operands {
+			r.repoRev = repoRev
+			resolved, err := r.resolve(ctx, i, op)
+			if err != nil {
+				return nil, err
+			}
+			operands[j] = resolved
+		}
+		return OrToken{Operands: operands}, nil
+	}
+	return tok, nil
+}
+
+// resolveAny resolves an AnyToken to a RepoToken (if it contains a
+// slash), a UserToken (if it doesn't), or a UnitToken or FileToken (if
+// it follows a RepoToken). If it resolves to nothing, it is a Term.
+func (r *resolver) resolveAny(ctx context.Context, i int, tok AnyToken) (Token, error) {
+	s := string(tok)
+	var cands Tokens
+
+	if strings.Contains(s, "/") {
+		repo, err := r.getRepo(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if repo != nil {
+			cands = append(cands, RepoToken{URI: repo.URI, Repo: repo})
+		}
+	} else {
+		user, err := r.getUser(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			cands = append(cands, UserToken{Login: user.Login, User: user})
+		}
+	}
+
+	if r.repoRev != nil {
+		units, err := r.listUnits(ctx, s, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range units {
+			cands = append(cands, unitToken(u))
+		}
+
+		fileTok, err := r.getFile(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if fileTok != nil {
+			cands = append(cands, *fileTok)
+		}
+	}
+
+	switch len(cands) {
+	case 0:
+		if strings.Contains(s, "/") {
+			r.tip(i, tok, "No repository, source unit, file, or directory named %q exists, so it was searched for as text.", s)
+		}
+		return Term(s), nil
+	case 1:
+		if repoTok, ok := cands[0].(RepoToken); ok {
+			r.repoRev = &RepoRevSpec{RepoSpec: repoTok.Spec()}
+		}
+		return cands[0], nil
+	}
+	r.ambiguous(i, tok, cands)
+	return tok, nil
+}
+
+// candidateKinds describes the kinds of things that a candidate
+// resolution can refer to, in the ResolveErrors of ambiguous tokens.
+var candidateKinds = map[string]string{
+	"RepoToken": "a repository",
+	"UserToken": "a user or organization",
+	"UnitToken": "a source unit",
+	"FileToken": "a file or directory",
+}
+
+func (r *resolver) ambiguous(i int, tok Token, cands Tokens) {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, cand := range cands {
+		if kind := candidateKinds[TokenType(cand)]; !seen[kind] {
+			kinds = append(kinds, kind)
+			seen[kind] = true
+		}
+	}
+	var what string
+	if len(kinds) == 1 {
+		what = fmt.Sprintf("%d different things (each %s)", len(cands), kinds[0])
+	} else {
+		what = strings.Join(kinds[:len(kinds)-1], ", ") + " or " + kinds[len(kinds)-1]
+	}
+	r.tokenError(i, tok, "%q is ambiguous; it could refer to %s.", tok.String(), what)
+	r.res.Ambiguous = append(r.res.Ambiguous, AmbiguousToken{Index: i + 1, Candidates: cands})
+}
+
+// getRepo gets the repository with the given URI, or returns nil if
+// it does not exist.
+func (r *resolver) getRepo(ctx context.Context, uri string) (*Repo, error) {
+	repo, _, err := r.c.Repos.Get(ctx, RepoSpec{URI: uri}, nil)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return repo, err
+}
+
+// getUser gets the user or org with the given login, or returns nil
+// if it does not exist.
+func (r *resolver) getUser(ctx context.Context, login string) (*User, error) {
+	user, _, err := r.c.Users.Get(ctx, UserSpec{Login: login}, nil)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// listUnits lists the source units with the given name (and type, if
+// nonempty) in r.repoRev, or in all repositories if r.repoRev is nil.
+func (r *resolver) listUnits(ctx context.Context, name, unitType string) ([]*unit.RepoSourceUnit, error) {
+	opt := &UnitListOptions{Unit: name, UnitType: unitType}
+	if r.repoRev != nil {
+		repoRev := r.repoRev.URI
+		if rev := r.repoRev.Rev; rev != "" {
+			repoRev += "@" + rev
+		}
+		opt.RepoRevs = []string{repoRev}
+	}
+	units, _, err := r.c.Units.List(ctx, opt)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return units, err
+}
+
+// getFile gets the file or directory at p in r.repoRev and returns a
+// FileToken for it, or returns nil if it does not exist.
+func (r *resolver) getFile(ctx context.Context, p string) (*FileToken, error) {
+	p = path.Clean(strings.TrimPrefix(p, "/"))
+	entry, _, err := r.c.RepoTree.Get(ctx, TreeEntrySpec{RepoRev: *r.repoRev, Path: p}, nil)
+	if isNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	return &FileToken{Path: p, Entry: entry.TreeEntry}, nil
+}
+
+func unitToken(u *unit.RepoSourceUnit) UnitToken {
+	return UnitToken{UnitType: u.UnitType, Name: u.Unit, Unit: u}
+}
+
+// isNotFound returns whether err indicates that the requested
+// repository, user, commit, source unit, or tree entry does not
+// exist.
+func isNotFound(err error) bool {
+	return IsNotPresent(err) || errors.Is(err, ErrUserNotExist) || IsHTTPErrorCode(err, http.StatusNotFound)
+}
@@ -0,0 +1,180 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
+)
+
+// newResolveTestClient returns a mock client whose services contain
+// repo r.com/a (with revision v1 and file a/b.go), user alice, and
+// source units u (in r.com/a) and v (twice, in r.com/a).
+func newResolveTestClient() *Client {
+	c := NewMockClient()
+	c.Repos = &MockReposService{
+		Get_: func(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+			if repo.URI == "r.com/a" {
+				return &Repo{URI: "r.com/a", RID: 1}, nil, nil
+			}
+			return nil, nil, ErrNotExist
+		},
+		GetCommit_: func(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
+			if rev.URI == "r.com/a" && rev.Rev == "v1" {
+				return &Commit{&vcs.Commit{ID: "c"}}, nil, nil
+			}
+			return nil, nil, &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		},
+	}
+	c.Users = &MockUsersService{
+		Get_: func(ctx context.Context, user UserSpec, opt *UserGetOptions) (*User, Response, error) {
+			if user.Login == "alice" {
+				return &User{Login: "alice", UID: 1}, nil, nil
+			}
+			return nil, nil, ErrUserNotExist
+		},
+	}
+	c.Units = &MockUnitsService{
+		List_: func(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
+			var units []*unit.RepoSourceUnit
+			switch opt.Unit {
+			case "u":
+				units = append(units, &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t", Unit: "u"})
+			case "v":
+				units = append(units, &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t", Unit: "v"}, &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t2", Unit: "v"})
+			}
+			return units, nil, nil
+		},
+	}
+	c.RepoTree = &MockRepoTreeService{
+		Get_: func(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
+			if entry.RepoRev.URI == "r.com/a" && entry.Path == "a/b.go" {
+				return &TreeEntry{TreeEntry: &vcsclient.TreeEntry{Name: "b.go", Type: vcsclient.FileEntry}}, nil, nil
+			}
+			return nil, nil, &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		},
+	}
+	return c
+}
+
+func TestClient_Resolve(t *testing.T) {
+	c := newResolveTestClient()
+	repo := &Repo{URI: "r.com/a", RID: 1}
+	alice := &User{Login: "alice", UID: 1}
+	unitU := &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t", Unit: "u"}
+	file := &vcsclient.TreeEntry{Name: "b.go", Type: vcsclient.FileEntry}
+
+	tests := map[string]struct {
+		toks Tokens
+		want *Resolution
+	}{
+		"empty": {
+			toks: nil,
+			want: &Resolution{},
+		},
+		"typed tokens": {
+			toks: Tokens{RepoToken{URI: "r.com/a"}, RevToken{Rev: "v1"}, UnitToken{Name: "u"}, FileToken{Path: "/a/b.go"}, UserToken{Login: "alice"}, Term("x")},
+			want: &Resolution{
+				ResolvedTokens: Tokens{
+					RepoToken{URI: "r.com/a", Repo: repo},
+					RevToken{Rev: "v1", Commit: &Commit{&vcs.Commit{ID: "c"}}},
+					UnitToken{Name: "u", UnitType: "t", Unit: unitU},
+					FileToken{Path: "a/b.go", Entry: file},
+					UserToken{Login: "alice", User: alice},
+					Term("x"),
+				},
+			},
+		},
+		"any tokens": {
+			toks: Tokens{AnyToken("alice"), AnyToken("r.com/a"), AnyToken("u"), AnyToken("a/b.go"), AnyToken("x"), AnyToken("x/y")},
+			want: &Resolution{
+				ResolvedTokens: Tokens{
+					UserToken{Login: "alice", User: alice},
+					RepoToken{URI: "r.com/a", Repo: repo},
+					UnitToken{Name: "u", UnitType: "t", Unit: unitU},
+					FileToken{Path: "a/b.go", Entry: file},
+					Term("x"),
+					Term("x/y"),
+				},
+				Tips: []TokenError{
+					{Index: 6, Token: AnyToken("x/y"), Message: `No repository, source unit, file, or directory named "x/y" exists, so it was searched for as text.`},
+				},
+			},
+		},
+		"compound tokens": {
+			toks: Tokens{OrToken{Operands: Tokens{AnyToken("r.com/a"), AnyToken("x/y")}}, AnyToken("u"), NotToken{AnyToken("alice")}, FilterToken{Field: "kind", Value: "func"}},
+			want: &Resolution{
+				ResolvedTokens: Tokens{
+					OrToken{Operands: Tokens{RepoToken{URI: "r.com/a", Repo: repo}, Term("x/y")}},
+					Term("u"),
+					NotToken{UserToken{Login: "alice", User: alice}},
+					FilterToken{Field: "kind", Value: "func"},
+				},
+				Tips: []TokenError{
+					{Index: 1, Token: AnyToken("x/y"), Message: `No repository, source unit, file, or directory named "x/y" exists, so it was searched for as text.`},
+				},
+			},
+		},
+		"ambiguous": {
+			toks: Tokens{RepoToken{URI: "r.com/a"}, AnyToken("v"), UnitToken{Name: "v"}},
+			want: &Resolution{
+				ResolvedTokens: Tokens{RepoToken{URI: "r.com/a", Repo: repo}, AnyToken("v"), UnitToken{Name: "v"}},
+				ResolveErrors: []TokenError{
+					{Index: 2, Token: AnyToken("v"), Message: `"v" is ambiguous; it could refer to 2 different things (each a source unit).`},
+					{Index: 3, Token: UnitToken{Name: "v"}, Message: `"~v" is ambiguous; it could refer to 2 different things (each a source unit).`},
+				},
+				Ambiguous: []AmbiguousToken{
+					{Index: 2, Candidates: Tokens{
+						UnitToken{Name: "v", UnitType: "t", Unit: &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t", Unit: "v"}},
+						UnitToken{Name: "v", UnitType: "t2", Unit: &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t2", Unit: "v"}},
+					}},
+					{Index: 3, Candidates: Tokens{
+						UnitToken{Name: "v", UnitType: "t", Unit: &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t", Unit: "v"}},
+						UnitToken{Name: "v", UnitType: "t2", Unit: &unit.RepoSourceUnit{Repo: "r.com/a", UnitType: "t2", Unit: "v"}},
+					}},
+				},
+			},
+		},
+		"errors": {
+			toks: Tokens{RevToken{Rev: "v1"}, FileToken{Path: "a"}, RepoToken{URI: "r.com/b"}, UserToken{Login: "bob"}, RepoToken{URI: "r.com/a"}, RevToken{Rev: "v2"}, FileToken{Path: "a"}, UnitToken{Name: "w"}},
+			want: &Resolution{
+				ResolvedTokens: Tokens{RevToken{Rev: "v1"}, FileToken{Path: "a"}, RepoToken{URI: "r.com/b"}, UserToken{Login: "bob"}, RepoToken{URI: "r.com/a", Repo: repo}, RevToken{Rev: "v2"}, FileToken{Path: "a"}, UnitToken{Name: "w"}},
+				ResolveErrors: []TokenError{
+					{Index: 1, Token: RevToken{Rev: "v1"}, Message: "A revision must follow a repository."},
+					{Index: 2, Token: FileToken{Path: "a"}, Message: "A file or directory must follow a repository."},
+					{Index: 3, Token: RepoToken{URI: "r.com/b"}, Message: `Repository "r.com/b" does not exist.`},
+					{Index: 4, Token: UserToken{Login: "bob"}, Message: `User or organization "bob" does not exist.`},
+					{Index: 6, Token: RevToken{Rev: "v2"}, Message: `Revision "v2" does not exist in repository "r.com/a".`},
+					{Index: 7, Token: FileToken{Path: "a"}, Message: `File or directory "a" does not exist.`},
+					{Index: 8, Token: UnitToken{Name: "w"}, Message: `Source unit "w" does not exist.`},
+				},
+			},
+		},
+	}
+	for label, test := range tests {
+		res, err := c.Resolve(context.Background(), test.toks)
+		if err != nil {
+			t.Errorf("%s: Resolve: %s", label, err)
+			continue
+		}
+		if !reflect.DeepEqual(res, test.want) {
+			t.Errorf("%s: got\n%+v\n\nwant\n%+v", label, res, test.want)
+		}
+	}
+}
+
+func TestClient_Resolve_error(t *testing.T) {
+	c := newResolveTestClient()
+	c.Users = &MockUsersService{
+		Get_: func(ctx context.Context, user UserSpec, opt *UserGetOptions) (*User, Response, error) {
+			return nil, nil, context.DeadlineExceeded
+		},
+	}
+	if _, err := c.Resolve(context.Background(), Tokens{AnyToken("alice")}); err != context.DeadlineExceeded {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -1,6 +1,9 @@
 package sourcegraph
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestJoin(t *testing.T) {
 	tests := []struct {
@@ -15,6 +18,10 @@ func TestJoin(t *testing.T) {
 			tokens: []Token{Term("a"), Term("b")},
 			want:   RawQuery{String: "a b", InsertionPoint: 4},
 		},
+		{
+			tokens: []Token{Term("a b"), Term(":v"), Term("OR"), Term(""), Term("x:y")},
+			want:   RawQuery{String: `"a b" ":v" "OR" "" x:y`, InsertionPoint: 23},
+		},
 	}
 	for _, test := range tests {
 		q := Join(test.tokens)
@@ -23,3 +30,91 @@ func TestJoin(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		q          string
+		want       Tokens
+		wantString string // if empty, q
+	}{
+		{q: "", want: nil},
+		{q: "a", want: Tokens{AnyToken("a")}},
+		{q: "a b", want: Tokens{AnyToken("a"), AnyToken("b")}},
+		{q: "  a   b ", want: Tokens{AnyToken("a"), AnyToken("b")}, wantString: "a b"},
+		{q: `"a b"`, want: Tokens{Term("a b")}},
+		{q: `"a b" c`, want: Tokens{Term("a b"), AnyToken("c")}},
+		{q: `"a`, want: Tokens{Term("a")}, wantString: "a"},
+		{q: `"a b`, want: Tokens{Term("a b")}, wantString: `"a b"`},
+		{q: "github.com/foo/bar :v1.2", want: Tokens{AnyToken("github.com/foo/bar"), RevToken{Rev: "v1.2"}}},
+		{q: ":", want: Tokens{RevToken{}}},
+		{q: "~mypkg", want: Tokens{UnitToken{Name: "mypkg"}}},
+		{q: "~mypkg@GoPackage", want: Tokens{UnitToken{Name: "mypkg", UnitType: "GoPackage"}}},
+		{q: "~a@b@GoPackage", want: Tokens{UnitToken{Name: "a@b", UnitType: "GoPackage"}}},
+		{q: "/", want: Tokens{FileToken{Path: ""}}},
+		{q: "/a/b.go", want: Tokens{FileToken{Path: "a/b.go"}}},
+		{q: "@alice", want: Tokens{UserToken{Login: "alice"}}},
+		{q: "@", want: Tokens{UserToken{}}},
+		{q: "kind:func", want: Tokens{FilterToken{Field: "kind", Value: "func"}}},
+		{q: "kind:", want: Tokens{FilterToken{Field: "kind"}}},
+		{q: "foo:bar", want: Tokens{AnyToken("foo:bar")}},
+		{q: "-test:yes", want: Tokens{NotToken{FilterToken{Field: "test", Value: "yes"}}}},
+		{q: "-@alice", want: Tokens{NotToken{UserToken{Login: "alice"}}}},
+		{q: "-", want: Tokens{AnyToken("-")}},
+		{q: "a OR b", want: Tokens{OrToken{Operands: Tokens{AnyToken("a"), AnyToken("b")}}}},
+		{
+			q:    "x kind:func OR kind:type OR -@u y",
+			want: Tokens{AnyToken("x"), OrToken{Operands: Tokens{FilterToken{Field: "kind", Value: "func"}, FilterToken{Field: "kind", Value: "type"}, NotToken{UserToken{Login: "u"}}}}, AnyToken("y")},
+		},
+		{q: "OR", want: Tokens{AnyToken("OR")}},
+		{q: "a OR", want: Tokens{AnyToken("a"), AnyToken("OR")}},
+		{q: "OR OR a", want: Tokens{AnyToken("OR"), AnyToken("OR"), AnyToken("a")}},
+		{q: `a "OR" b`, want: Tokens{AnyToken("a"), Term("OR"), AnyToken("b")}},
+		{
+			q:          `"a" ":v" "~u" "/p" "@u" "kind:func" "-x" "" "a	b"`,
+			want:       Tokens{Term("a"), Term(":v"), Term("~u"), Term("/p"), Term("@u"), Term("kind:func"), Term("-x"), Term(""), Term("a\tb")},
+			wantString: `a ":v" "~u" "/p" "@u" "kind:func" "-x" "" "a	b"`,
+		},
+		{
+			q:    "r :v ~u@t /p @u x",
+			want: Tokens{AnyToken("r"), RevToken{Rev: "v"}, UnitToken{Name: "u", UnitType: "t"}, FileToken{Path: "p"}, UserToken{Login: "u"}, AnyToken("x")},
+		},
+	}
+	for _, test := range tests {
+		toks := Tokenize(RawQuery{String: test.q})
+		if !reflect.DeepEqual(toks, test.want) {
+			t.Errorf("%q: got tokens %#v, want %#v", test.q, toks, test.want)
+			continue
+		}
+
+		wantString := test.wantString
+		if wantString == "" {
+			wantString = test.q
+		}
+		if s := Join(toks).String; s != wantString {
+			t.Errorf("%q: Join(Tokenize(q)) == %q, want %q", test.q, s, wantString)
+		}
+	}
+}
+
+func TestActiveTokenIndex(t *testing.T) {
+	tests := []struct {
+		q    RawQuery
+		want int
+	}{
+		{RawQuery{String: "", InsertionPoint: 0}, -1},
+		{RawQuery{String: "a", InsertionPoint: 0}, 0},
+		{RawQuery{String: "a", InsertionPoint: 1}, 0},
+		{RawQuery{String: "a b", InsertionPoint: 2}, 1},
+		{RawQuery{String: "a  b", InsertionPoint: 2}, -1},
+		{RawQuery{String: "a b", InsertionPoint: 4}, -1},
+		{RawQuery{String: `"a b" c`, InsertionPoint: 3}, 0},
+		{RawQuery{String: "é b", InsertionPoint: 3}, 1},
+		{RawQuery{String: "a OR b c", InsertionPoint: 6}, 0},
+		{RawQuery{String: "a OR b c", InsertionPoint: 8}, 1},
+	}
+	for _, test := range tests {
+		if i := ActiveTokenIndex(test.q); i != test.want {
+			t.Errorf("%+v: got active token index %d, want %d", test.q, i, test.want)
+		}
+	}
+}
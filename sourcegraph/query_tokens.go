@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"unicode"
 
 	"sourcegraph.com/sourcegraph/srclib/unit"
 	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
@@ -20,13 +21,25 @@ type Token interface {
 
 // A Term is a query term token. It is either a word or an arbitrary
 // string (if quoted in the raw query).
+//
+// A quoted string ends at the next double quote (there is no escape
+// for it), so a Term that contains a double quote can't always be
+// written in a raw query, and its String doesn't round-trip through
+// Tokenize.
 type Term string
 
+// String returns the term, quoted if Tokenize would otherwise split
+// it or parse it as something other than an AnyToken (which resolves
+// to a Term), such as a RevToken or the OR operator.
 func (t Term) String() string {
-	if strings.Contains(string(t), " ") {
-		return `"` + string(t) + `"`
+	s := string(t)
+	if s == "" || strings.IndexFunc(s, unicode.IsSpace) != -1 || AnyToken(s) == orOperator {
+		return `"` + s + `"`
 	}
-	return string(t)
+	if _, ok := parseToken(s).(AnyToken); !ok {
+		return `"` + s + `"`
+	}
+	return s
 }
 
 func (t Term) UnquotedString() string { return string(t) }
@@ -95,7 +108,13 @@ type FileToken struct {
 	Entry *vcsclient.TreeEntry
 }
 
-func (t FileToken) String() string { return "/" + filepath.Clean(t.Path) }
+func (t FileToken) String() string {
+	p := filepath.Clean(t.Path)
+	if p == "." {
+		return "/"
+	}
+	return "/" + p
+}
 
 // A UserToken represents a user or org, although it does not
 // necessarily uniquely identify one. It consists of the string "@"
@@ -108,6 +127,67 @@ type UserToken struct {
 
 func (t UserToken) String() string { return "@" + t.Login }
 
+// A FilterToken is a "field:value" filter, such as "kind:func" or
+// "test:yes". FilterFields lists the recognized fields.
+type FilterToken struct {
+	Field string
+	Value string
+}
+
+func (t FilterToken) String() string { return t.Field + ":" + t.Value }
+
+// FilterFields are the fields of FilterTokens, and the meanings of
+// their values.
+var FilterFields = map[string]string{
+	"kind":     "the kind of definition (e.g., func or type)",
+	"lang":     "the programming language (e.g., go or python)",
+	"exported": "whether definitions are exported (yes or no)",
+	"test":     "whether to include definitions in test files (yes or no)",
+	"fork":     "whether to include forked repositories (yes or no)",
+	"type":     "the type of repository (public or private)",
+	"repo":     "a repository",
+}
+
+// A NotToken negates a token. It is the string "-" followed by the
+// negated token (e.g., "-test:yes").
+type NotToken struct {
+	Token Token
+}
+
+func (t NotToken) String() string {
+	if t.Token == nil {
+		return "-"
+	}
+	return "-" + t.Token.String()
+}
+
+func (t NotToken) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct{ Token jsonToken }{jsonToken{t.Token}})
+}
+
+func (t *NotToken) UnmarshalJSON(b []byte) error {
+	var v struct{ Token jsonToken }
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	t.Token = v.Token.Token
+	return nil
+}
+
+// An OrToken matches if any of its operands match. It is the operands
+// separated by "OR" (e.g., "kind:func OR kind:type").
+type OrToken struct {
+	Operands Tokens
+}
+
+func (t OrToken) String() string {
+	s := make([]string, len(t.Operands))
+	for i, tok := range t.Operands {
+		s[i] = tok.String()
+	}
+	return strings.Join(s, " OR ")
+}
+
 // Tokens wraps a list of tokens and adds some helper methods. It also
 // serializes to JSON with "Type" fields added to each token and
 // deserializes that same JSON back into a typed list of tokens.
@@ -200,6 +280,12 @@ func (t *jsonToken) UnmarshalJSON(b []byte) error {
 		t.Token = &FileToken{}
 	case "UserToken":
 		t.Token = &UserToken{}
+	case "FilterToken":
+		t.Token = &FilterToken{}
+	case "NotToken":
+		t.Token = &NotToken{}
+	case "OrToken":
+		t.Token = &OrToken{}
 	default:
 		return fmt.Errorf("unmarshal Tokens: unrecognized Type %q", typ.Type)
 	}
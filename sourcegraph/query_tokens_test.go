@@ -103,6 +103,32 @@ func TestTokens_JSON(t *testing.T) {
 	}
 }
 
+func TestTokens_JSON_compound(t *testing.T) {
+	tokens := Tokens{
+		FilterToken{Field: "kind", Value: "func"},
+		NotToken{FilterToken{Field: "test", Value: "yes"}},
+		OrToken{Operands: Tokens{AnyToken("a"), NotToken{UserToken{Login: "u"}}}},
+	}
+
+	b, err := json.Marshal(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON := `[{"Field":"kind","Value":"func","Type":"FilterToken"},{"Token":{"Field":"test","Value":"yes","Type":"FilterToken"},"Type":"NotToken"},{"Operands":[{"String":"a","Type":"AnyToken"},{"Token":{"Login":"u","Type":"UserToken"},"Type":"NotToken"}],"Type":"OrToken"}]`
+	if string(b) != wantJSON {
+		t.Errorf("got JSON\n%s\n\nwant JSON\n%s", b, wantJSON)
+	}
+
+	var tokens2 Tokens
+	if err := json.Unmarshal(b, &tokens2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tokens2, tokens) {
+		t.Errorf("got tokens\n%+v\n\nwant tokens\n%+v", tokens2, tokens)
+	}
+}
+
 func TestTokens_nil(t *testing.T) {
 	tokens := Tokens(nil)
 
@@ -116,3 +142,9 @@ func TestTokens_nil(t *testing.T) {
 		t.Errorf("got JSON\n%s\n\nwant JSON\n%s", b, wantJSON)
 	}
 }
+
+func TestNotToken_String_nil(t *testing.T) {
+	if got, want := (NotToken{}).String(), "-"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
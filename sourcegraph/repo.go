@@ -220,11 +220,11 @@ func ErrRedirectFromString(msg string) *ErrRedirect {
 // IsNotPresent returns whether err is one of ErrNotExist, ErrNotPersisted, or
 // ErrRedirected.
 func IsNotPresent(err error) bool {
-	return err == ErrNotExist || err == ErrNotPersisted
+	return errors.Is(err, ErrNotExist) || errors.Is(err, ErrNotPersisted)
 }
 
 func IsForbidden(err error) bool {
-	return err == ErrForbidden
+	return errors.Is(err, ErrForbidden)
 }
 
 // ErrNoScheme is an error indicating that a clone URL contained no scheme
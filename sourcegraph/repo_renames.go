@@ -0,0 +1,164 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// maxRepoRenames is the maximum number of repository renames and
+// redirects that are followed for a single request.
+const maxRepoRenames = 5
+
+// maxRepoAliases is the maximum number of entries in a
+// repoAliasCache.
+const maxRepoAliases = 500
+
+// doFollowingRepoRenames implements Do when c.FollowRepoRenames is
+// true.
+func (c *Client) doFollowingRepoRenames(ctx context.Context, req *http.Request, v interface{}) (*HTTPResponse, error) {
+	var canonical *RepoSpec
+	if uri, ok := c.repoAliases.resolve(repoURIOfRoute(c.matchRoute(req))); ok {
+		if req2, err := c.withRepoURI(req, uri); err == nil {
+			req, canonical = req2, &RepoSpec{URI: uri}
+		}
+	}
+
+	for i := 0; ; i++ {
+		resp, err := c.do(ctx, req, v)
+		if err == nil || i >= maxRepoRenames {
+			return withCanonicalRepo(resp, canonical), err
+		}
+
+		newURI := renamedRepoURI(err)
+		oldURI := repoURIOfRoute(c.matchRoute(req))
+		if newURI == "" || oldURI == "" || newURI == oldURI {
+			return withCanonicalRepo(resp, canonical), err
+		}
+		req2, err2 := c.withRepoURI(req, newURI)
+		if err2 != nil {
+			return withCanonicalRepo(resp, canonical), err
+		}
+		if resp != nil && v == preserveBody {
+			resp.Body.Close()
+		}
+
+		c.repoAliases.add(oldURI, newURI)
+		req, canonical = req2, &RepoSpec{URI: newURI}
+	}
+}
+
+func withCanonicalRepo(resp *HTTPResponse, repo *RepoSpec) *HTTPResponse {
+	if resp != nil {
+		resp.CanonicalRepo = repo
+	}
+	return resp
+}
+
+// renamedRepoURI returns the new repository URI if err is (or wraps)
+// ErrRenamed or ErrRedirect, and the empty string otherwise.
+func renamedRepoURI(err error) string {
+	var renamed ErrRenamed
+	if errors.As(err, &renamed) {
+		return renamed.NewURI
+	}
+	var redirect ErrRedirect
+	if errors.As(err, &redirect) {
+		return redirect.RedirectURI
+	}
+	return ""
+}
+
+// repoURIOfRoute returns the repository URI in a route's RepoSpec
+// variable, or the empty string if it has none (or the repository is
+// specified by RID).
+func repoURIOfRoute(routeName string, routeVars map[string]string) string {
+	if routeName == "" {
+		return ""
+	}
+	spec, err := ParseRepoSpec(routeVars["RepoSpec"])
+	if err != nil {
+		return ""
+	}
+	return spec.URI
+}
+
+// withRepoURI returns a copy of req for the same route as req but for
+// the repository with the given URI.
+func (c *Client) withRepoURI(req *http.Request, uri string) (*http.Request, error) {
+	name, vars := c.matchRoute(req)
+	if name == "" {
+		return nil, errors.New("request URL matches no API route")
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, errors.New("request body can't be resent")
+	}
+
+	routeVars := make(map[string]string, len(vars))
+	for k, v := range vars {
+		routeVars[k] = v
+	}
+	routeVars["RepoSpec"] = RepoSpec{URI: uri}.PathComponent()
+	url, err := c.URL(name, routeVars, nil)
+	if err != nil {
+		return nil, err
+	}
+	url.RawQuery = req.URL.RawQuery
+
+	req2 := req.Clone(req.Context())
+	req2.URL = url
+	req2.Host = ""
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req2.Body = body
+	}
+	return req2, nil
+}
+
+// A repoAliasCache maps old repository URIs to the URIs that they
+// were renamed or redirect to. It is safe for concurrent use, and its
+// zero value is an empty cache.
+type repoAliasCache struct {
+	mu      sync.Mutex
+	aliases map[string]string
+}
+
+// add records that oldURI was renamed or redirects to newURI. If the
+// cache is full, an arbitrary entry is evicted.
+func (c *repoAliasCache) add(oldURI, newURI string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.aliases == nil {
+		c.aliases = map[string]string{}
+	}
+	if _, present := c.aliases[oldURI]; !present && len(c.aliases) >= maxRepoAliases {
+		for k := range c.aliases {
+			delete(c.aliases, k)
+			break
+		}
+	}
+	c.aliases[oldURI] = newURI
+}
+
+// resolve returns the URI that uri was (possibly transitively)
+// renamed or redirects to, if any.
+func (c *repoAliasCache) resolve(uri string) (string, bool) {
+	if uri == "" {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resolved := uri
+	for i := 0; i < maxRepoRenames; i++ {
+		newURI, present := c.aliases[resolved]
+		if !present {
+			break
+		}
+		resolved = newURI
+	}
+	return resolved, resolved != uri
+}
@@ -0,0 +1,106 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestClient_FollowRepoRenames(t *testing.T) {
+	tests := map[string]string{
+		"renamed":  `repository URI "r.com/x" was renamed to "r.com/y"; use the new name`,
+		"redirect": `the repository requested exists at another URI (r.com/y)`,
+	}
+	for label, msg := range tests {
+		func() {
+			setup()
+			defer teardown()
+
+			var oldCalled, newCalled int
+			mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+				oldCalled++
+				w.WriteHeader(http.StatusMovedPermanently)
+				writeJSON(w, &ErrorResponse{Message: msg})
+			})
+			mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/y"}), func(w http.ResponseWriter, r *http.Request) {
+				newCalled++
+				testFormValues(t, r, values{"a": "b"})
+				writeJSON(w, &Repo{RID: 1})
+			})
+
+			client.FollowRepoRenames = true
+
+			for i := 1; i <= 2; i++ {
+				req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+				req.URL.RawQuery = "a=b"
+				var repo *Repo
+				resp, err := client.Do(context.Background(), req, &repo)
+				if err != nil {
+					t.Fatalf("%s: %s", label, err)
+				}
+				if want := (&RepoSpec{URI: "r.com/y"}); !reflect.DeepEqual(resp.CanonicalRepo, want) {
+					t.Errorf("%s: got CanonicalRepo %+v, want %+v", label, resp.CanonicalRepo, want)
+				}
+				if repo == nil || repo.RID != 1 {
+					t.Errorf("%s: got repo %+v, want RID 1", label, repo)
+				}
+				// The second request should go directly to the new URI.
+				if oldCalled != 1 || newCalled != i {
+					t.Errorf("%s: request %d: got %d old and %d new calls, want 1 and %d", label, i, oldCalled, newCalled, i)
+				}
+			}
+		}()
+	}
+}
+
+func TestClient_FollowRepoRenames_disabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMovedPermanently)
+		writeJSON(w, &ErrorResponse{Message: ErrRenamed{OldURI: "r.com/x", NewURI: "r.com/y"}.Error()})
+	})
+
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	resp, err := client.Do(context.Background(), req, nil)
+	var renamed ErrRenamed
+	if !errors.As(err, &renamed) {
+		t.Fatalf("got error %v, want ErrRenamed", err)
+	}
+	if resp.CanonicalRepo != nil {
+		t.Errorf("got CanonicalRepo %+v, want nil", resp.CanonicalRepo)
+	}
+}
+
+func TestClient_FollowRepoRenames_loop(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	for _, uri := range []string{"r.com/x", "r.com/y"} {
+		other := map[string]string{"r.com/x": "r.com/y", "r.com/y": "r.com/x"}[uri]
+		mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": uri}), func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusMovedPermanently)
+			writeJSON(w, &ErrorResponse{Message: fmt.Sprintf("the repository requested exists at another URI (%s)", other)})
+		})
+	}
+
+	client.FollowRepoRenames = true
+
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	_, err := client.Do(context.Background(), req, nil)
+	var redirect ErrRedirect
+	if !errors.As(err, &redirect) {
+		t.Fatalf("got error %v, want ErrRedirect", err)
+	}
+	if want := maxRepoRenames + 1; calls != want {
+		t.Errorf("got %d calls, want %d", calls, want)
+	}
+}
@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"github.com/sourcegraph/go-github/github"
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 )
@@ -13,7 +15,7 @@ type CombinedStatus struct {
 	github.CombinedStatus
 }
 
-func (s *repositoriesService) GetCombinedStatus(spec RepoRevSpec) (*CombinedStatus, Response, error) {
+func (s *repositoriesService) GetCombinedStatus(ctx context.Context, spec RepoRevSpec) (*CombinedStatus, Response, error) {
 	url, err := s.client.URL(router.RepoCombinedStatus, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -25,7 +27,7 @@ func (s *repositoriesService) GetCombinedStatus(spec RepoRevSpec) (*CombinedStat
 	}
 
 	var status CombinedStatus
-	resp, err := s.client.Do(req, &status)
+	resp, err := s.client.Do(ctx, req, &status)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -33,7 +35,7 @@ func (s *repositoriesService) GetCombinedStatus(spec RepoRevSpec) (*CombinedStat
 	return &status, resp, nil
 }
 
-func (s *repositoriesService) CreateStatus(spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error) {
+func (s *repositoriesService) CreateStatus(ctx context.Context, spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error) {
 	url, err := s.client.URL(router.RepoStatusCreate, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -45,7 +47,7 @@ func (s *repositoriesService) CreateStatus(spec RepoRevSpec, st RepoStatus) (*Re
 	}
 
 	var created RepoStatus
-	resp, err := s.client.Do(req, &created)
+	resp, err := s.client.Do(ctx, req, &created)
 	if err != nil {
 		return nil, resp, err
 	}
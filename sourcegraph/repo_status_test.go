@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -24,7 +25,7 @@ func TestReposService_GetCombinedStatus(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	cs, _, err := client.Repos.GetCombinedStatus(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"})
+	cs, _, err := client.Repos.GetCombinedStatus(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"})
 	if err != nil {
 		t.Errorf("Repos.GetCombinedStatus returned error: %v", err)
 	}
@@ -60,7 +61,7 @@ func TestReposService_CreateStatus(t *testing.T) {
 		writeJSON(w, want)
 	})
 
-	s, _, err := client.Repos.CreateStatus(RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, want)
+	s, _, err := client.Repos.CreateStatus(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "r"}, want)
 	if err != nil {
 		t.Errorf("Repos.CreateStatus returned error: %v", err)
 	}
@@ -1,10 +1,12 @@
 package sourcegraph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"text/template"
 
+	"sourcegraph.com/sourcegraph/go-diff/diff"
 	"sourcegraph.com/sourcegraph/go-nnz/nnz"
 	"sourcegraph.com/sourcegraph/go-vcs/vcs"
 	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
@@ -19,33 +21,33 @@ import (
 // Sourcegraph API.
 type ReposService interface {
 	// Get fetches a repository.
-	Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
+	Get(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
 
 	// GetStats gets statistics about a repository at a specific
 	// commit. Some statistics are per-commit and some are global to
 	// the repository. If you only care about global repository
 	// statistics, pass an empty Rev to the RepoRevSpec (which will be
 	// resolved to the repository's default branch).
-	GetStats(repo RepoRevSpec) (RepoStats, Response, error)
+	GetStats(ctx context.Context, repo RepoRevSpec) (RepoStats, Response, error)
 
 	// CreateStatus creates a repository status for the given commit.
-	CreateStatus(spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error)
+	CreateStatus(ctx context.Context, spec RepoRevSpec, st RepoStatus) (*RepoStatus, Response, error)
 
 	// GetCombinedStatus fetches the combined repository status for
 	// the given commit.
-	GetCombinedStatus(spec RepoRevSpec) (*CombinedStatus, Response, error)
+	GetCombinedStatus(ctx context.Context, spec RepoRevSpec) (*CombinedStatus, Response, error)
 
 	// GetOrCreate fetches a repository using Get. If no such repository exists
 	// with the URI, and the URI refers to a recognized repository host (such as
 	// github.com), the repository's information is fetched from the external
 	// host and the repository is created.
-	GetOrCreate(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
+	GetOrCreate(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error)
 
 	// GetSettings fetches a repository's configuration settings.
-	GetSettings(repo RepoSpec) (*RepoSettings, Response, error)
+	GetSettings(ctx context.Context, repo RepoSpec) (*RepoSettings, Response, error)
 
 	// UpdateSettings updates a repository's configuration settings.
-	UpdateSettings(repo RepoSpec, settings RepoSettings) (Response, error)
+	UpdateSettings(ctx context.Context, repo RepoSpec, settings RepoSettings) (Response, error)
 
 	// RefreshProfile updates the repository metadata for a repository, fetching
 	// it from an external host if the host is recognized (such as GitHub).
@@ -53,7 +55,7 @@ type ReposService interface {
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	RefreshProfile(repo RepoSpec) (Response, error)
+	RefreshProfile(ctx context.Context, repo RepoSpec) (Response, error)
 
 	// RefreshVCSData updates the repository VCS (git/hg) data, fetching all new
 	// commits, branches, tags, and blobs.
@@ -61,76 +63,82 @@ type ReposService interface {
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	RefreshVCSData(repo RepoSpec) (Response, error)
+	RefreshVCSData(ctx context.Context, repo RepoSpec) (Response, error)
 
 	// ComputeStats updates the statistics about a repository.
 	//
 	// This operation is performed asynchronously on the server side (after
 	// receiving the request) and the API currently has no way of notifying
 	// callers when the operation completes.
-	ComputeStats(repo RepoRevSpec) (Response, error)
+	ComputeStats(ctx context.Context, repo RepoRevSpec) (Response, error)
 
 	// GetBuild gets the build for a specific revspec. It returns
 	// additional information about the build, such as whether it is
 	// exactly up-to-date with the revspec or a few commits behind the
 	// revspec. The opt param controls what is returned in this case.
-	GetBuild(repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error)
+	GetBuild(ctx context.Context, repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error)
 
 	// Create adds the repository at cloneURL, filling in all information about
 	// the repository that can be inferred from the URL (or, for GitHub
 	// repositories, fetched from the GitHub API). If a repository with the
 	// specified clone URL, or the same URI, already exists, it is returned.
-	Create(newRepoSpec NewRepoSpec) (*Repo, Response, error)
+	Create(ctx context.Context, newRepoSpec NewRepoSpec) (*Repo, Response, error)
 
 	// GetReadme fetches the formatted README file for a repository.
-	GetReadme(repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error)
+	GetReadme(ctx context.Context, repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error)
 
 	// List repositories.
-	List(opt *RepoListOptions) ([]*Repo, Response, error)
+	List(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error)
 
 	// List commits.
-	ListCommits(repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error)
+	ListCommits(ctx context.Context, repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error)
 
 	// GetCommit gets a commit.
-	GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error)
+	GetCommit(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error)
+
+	// CompareCommits compares the head commit with the base commit
+	// specified in opt. It returns the commits in the range
+	// base..head, how far head is ahead of and behind base, and the
+	// diffstat of each changed file.
+	CompareCommits(ctx context.Context, head RepoRevSpec, opt *RepoCompareCommitsOptions) (*CommitsComparison, Response, error)
 
 	// ListBranches lists a repository's branches.
-	ListBranches(repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error)
+	ListBranches(ctx context.Context, repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error)
 
 	// ListTags lists a repository's tags.
-	ListTags(repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error)
+	ListTags(ctx context.Context, repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error)
 
 	// ListBadges lists the available badges for repo.
-	ListBadges(repo RepoSpec) ([]*Badge, Response, error)
+	ListBadges(ctx context.Context, repo RepoSpec) ([]*Badge, Response, error)
 
 	// ListCounters lists the available counters for repo.
-	ListCounters(repo RepoSpec) ([]*Counter, Response, error)
+	ListCounters(ctx context.Context, repo RepoSpec) ([]*Counter, Response, error)
 
 	// ListAuthors lists people who have contributed (i.e., committed) code to
 	// repo.
-	ListAuthors(repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error)
+	ListAuthors(ctx context.Context, repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error)
 
 	// ListClients lists people who reference defs defined in repo.
-	ListClients(repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error)
+	ListClients(ctx context.Context, repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error)
 
 	// ListDependents lists repositories that contain defs referenced by
 	// repo.
-	ListDependencies(repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error)
+	ListDependencies(ctx context.Context, repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error)
 
 	// ListDependents lists repositories that reference defs defined in repo.
-	ListDependents(repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error)
+	ListDependents(ctx context.Context, repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error)
 
 	// ListByContributor lists repositories that user has contributed (i.e.,
 	// committed) code to.
-	ListByContributor(user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error)
+	ListByContributor(ctx context.Context, user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error)
 
 	// ListByClient lists repositories that contain defs referenced by
 	// user.
-	ListByClient(user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error)
+	ListByClient(ctx context.Context, user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error)
 
 	// ListByRefdAuthor lists repositories that reference code authored by
 	// user.
-	ListByRefdAuthor(user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error)
+	ListByRefdAuthor(ctx context.Context, user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error)
 }
 
 // repositoriesService implements ReposService.
@@ -291,7 +299,7 @@ type RepoGetOptions struct {
 	Stats bool `url:",omitempty" json:",omitempty"` // whether to fetch and include stats in the returned repository
 }
 
-func (s *repositoriesService) Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+func (s *repositoriesService) Get(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
 	url, err := s.client.URL(router.Repo, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -303,7 +311,7 @@ func (s *repositoriesService) Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Re
 	}
 
 	var repo_ *Repo
-	resp, err := s.client.Do(req, &repo_)
+	resp, err := s.client.Do(ctx, req, &repo_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -311,7 +319,7 @@ func (s *repositoriesService) Get(repo RepoSpec, opt *RepoGetOptions) (*Repo, Re
 	return repo_, resp, nil
 }
 
-func (s *repositoriesService) GetStats(repoRev RepoRevSpec) (RepoStats, Response, error) {
+func (s *repositoriesService) GetStats(ctx context.Context, repoRev RepoRevSpec) (RepoStats, Response, error) {
 	url, err := s.client.URL(router.RepoStats, repoRev.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -323,7 +331,7 @@ func (s *repositoriesService) GetStats(repoRev RepoRevSpec) (RepoStats, Response
 	}
 
 	var stats RepoStats
-	resp, err := s.client.Do(req, &stats)
+	resp, err := s.client.Do(ctx, req, &stats)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -331,7 +339,7 @@ func (s *repositoriesService) GetStats(repoRev RepoRevSpec) (RepoStats, Response
 	return stats, resp, nil
 }
 
-func (s *repositoriesService) GetOrCreate(repo_ RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+func (s *repositoriesService) GetOrCreate(ctx context.Context, repo_ RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
 	url, err := s.client.URL(router.ReposGetOrCreate, repo_.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -343,7 +351,7 @@ func (s *repositoriesService) GetOrCreate(repo_ RepoSpec, opt *RepoGetOptions) (
 	}
 
 	var repo__ *Repo
-	resp, err := s.client.Do(req, &repo__)
+	resp, err := s.client.Do(ctx, req, &repo__)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -387,7 +395,7 @@ type RepoSettings struct {
 	UseSSHPrivateKey *bool `db:"use_ssh_private_key" json:",omitempty"`
 }
 
-func (s *repositoriesService) GetSettings(repo RepoSpec) (*RepoSettings, Response, error) {
+func (s *repositoriesService) GetSettings(ctx context.Context, repo RepoSpec) (*RepoSettings, Response, error) {
 	url, err := s.client.URL(router.RepoSettings, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -399,7 +407,7 @@ func (s *repositoriesService) GetSettings(repo RepoSpec) (*RepoSettings, Respons
 	}
 
 	var settings *RepoSettings
-	resp, err := s.client.Do(req, &settings)
+	resp, err := s.client.Do(ctx, req, &settings)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -407,7 +415,7 @@ func (s *repositoriesService) GetSettings(repo RepoSpec) (*RepoSettings, Respons
 	return settings, resp, nil
 }
 
-func (s *repositoriesService) UpdateSettings(repo RepoSpec, settings RepoSettings) (Response, error) {
+func (s *repositoriesService) UpdateSettings(ctx context.Context, repo RepoSpec, settings RepoSettings) (Response, error) {
 	url, err := s.client.URL(router.RepoSettingsUpdate, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -418,7 +426,7 @@ func (s *repositoriesService) UpdateSettings(repo RepoSpec, settings RepoSetting
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -426,7 +434,7 @@ func (s *repositoriesService) UpdateSettings(repo RepoSpec, settings RepoSetting
 	return resp, nil
 }
 
-func (s *repositoriesService) RefreshProfile(repo RepoSpec) (Response, error) {
+func (s *repositoriesService) RefreshProfile(ctx context.Context, repo RepoSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoRefreshProfile, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -437,7 +445,7 @@ func (s *repositoriesService) RefreshProfile(repo RepoSpec) (Response, error) {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -445,7 +453,7 @@ func (s *repositoriesService) RefreshProfile(repo RepoSpec) (Response, error) {
 	return resp, nil
 }
 
-func (s *repositoriesService) RefreshVCSData(repo RepoSpec) (Response, error) {
+func (s *repositoriesService) RefreshVCSData(ctx context.Context, repo RepoSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoRefreshVCSData, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -456,7 +464,7 @@ func (s *repositoriesService) RefreshVCSData(repo RepoSpec) (Response, error) {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -464,7 +472,7 @@ func (s *repositoriesService) RefreshVCSData(repo RepoSpec) (Response, error) {
 	return resp, nil
 }
 
-func (s *repositoriesService) ComputeStats(repo RepoRevSpec) (Response, error) {
+func (s *repositoriesService) ComputeStats(ctx context.Context, repo RepoRevSpec) (Response, error) {
 	url, err := s.client.URL(router.RepoComputeStats, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, err
@@ -475,7 +483,7 @@ func (s *repositoriesService) ComputeStats(repo RepoRevSpec) (Response, error) {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(req, nil)
+	resp, err := s.client.Do(ctx, req, nil)
 	if err != nil {
 		return resp, err
 	}
@@ -517,7 +525,7 @@ type RepoBuildInfo struct {
 	LastSuccessfulCommit *Commit // the commit of the LastSuccessful build
 }
 
-func (s *repositoriesService) GetBuild(repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error) {
+func (s *repositoriesService) GetBuild(ctx context.Context, repo RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error) {
 	url, err := s.client.URL(router.RepoBuild, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -529,7 +537,7 @@ func (s *repositoriesService) GetBuild(repo RepoRevSpec, opt *RepoGetBuildOption
 	}
 
 	var info *RepoBuildInfo
-	resp, err := s.client.Do(req, &info)
+	resp, err := s.client.Do(ctx, req, &info)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -542,7 +550,7 @@ type NewRepoSpec struct {
 	CloneURLStr string `json:"CloneURL"`
 }
 
-func (s *repositoriesService) Create(newRepoSpec NewRepoSpec) (*Repo, Response, error) {
+func (s *repositoriesService) Create(ctx context.Context, newRepoSpec NewRepoSpec) (*Repo, Response, error) {
 	url, err := s.client.URL(router.ReposCreate, nil, nil)
 	if err != nil {
 		return nil, nil, err
@@ -554,7 +562,7 @@ func (s *repositoriesService) Create(newRepoSpec NewRepoSpec) (*Repo, Response,
 	}
 
 	var repo_ *Repo
-	resp, err := s.client.Do(req, &repo_)
+	resp, err := s.client.Do(ctx, req, &repo_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -562,7 +570,7 @@ func (s *repositoriesService) Create(newRepoSpec NewRepoSpec) (*Repo, Response,
 	return repo_, resp, nil
 }
 
-func (s *repositoriesService) GetReadme(repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error) {
+func (s *repositoriesService) GetReadme(ctx context.Context, repo RepoRevSpec) (*vcsclient.TreeEntry, Response, error) {
 	url, err := s.client.URL(router.RepoReadme, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -574,7 +582,7 @@ func (s *repositoriesService) GetReadme(repo RepoRevSpec) (*vcsclient.TreeEntry,
 	}
 
 	var readme *vcsclient.TreeEntry
-	resp, err := s.client.Do(req, &readme)
+	resp, err := s.client.Do(ctx, req, &readme)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -608,7 +616,7 @@ type RepoListOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) List(opt *RepoListOptions) ([]*Repo, Response, error) {
+func (s *repositoriesService) List(ctx context.Context, opt *RepoListOptions) ([]*Repo, Response, error) {
 	url, err := s.client.URL(router.Repos, nil, opt)
 	if err != nil {
 		return nil, nil, err
@@ -620,7 +628,7 @@ func (s *repositoriesService) List(opt *RepoListOptions) ([]*Repo, Response, err
 	}
 
 	var repos []*Repo
-	resp, err := s.client.Do(req, &repos)
+	resp, err := s.client.Do(ctx, req, &repos)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -638,7 +646,7 @@ type RepoListCommitsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListCommits(repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error) {
+func (s *repositoriesService) ListCommits(ctx context.Context, repo RepoSpec, opt *RepoListCommitsOptions) ([]*Commit, Response, error) {
 	url, err := s.client.URL(router.RepoCommits, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -650,7 +658,7 @@ func (s *repositoriesService) ListCommits(repo RepoSpec, opt *RepoListCommitsOpt
 	}
 
 	var commits []*Commit
-	resp, err := s.client.Do(req, &commits)
+	resp, err := s.client.Do(ctx, req, &commits)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -661,7 +669,7 @@ func (s *repositoriesService) ListCommits(repo RepoSpec, opt *RepoListCommitsOpt
 type RepoGetCommitOptions struct {
 }
 
-func (s *repositoriesService) GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
+func (s *repositoriesService) GetCommit(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
 	url, err := s.client.URL(router.RepoCommit, rev.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -673,7 +681,7 @@ func (s *repositoriesService) GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptio
 	}
 
 	var commit *Commit
-	resp, err := s.client.Do(req, &commit)
+	resp, err := s.client.Do(ctx, req, &commit)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -681,11 +689,84 @@ func (s *repositoriesService) GetCommit(rev RepoRevSpec, opt *RepoGetCommitOptio
 	return commit, resp, nil
 }
 
+// RepoCompareCommitsOptions specifies options for comparing commits.
+type RepoCompareCommitsOptions struct {
+	// Base is the revspec of the base commit. It must be set.
+	Base string `url:",omitempty" json:",omitempty"`
+
+	// ListOptions paginates the commits in the range (but not the
+	// file diffstats).
+	ListOptions
+}
+
+// A CommitsComparison is the result of comparing a head commit with a
+// base commit.
+type CommitsComparison struct {
+	Base *Commit // the base commit
+	Head *Commit // the head commit
+
+	// MergeBase is the best common ancestor of Base and Head.
+	MergeBase *Commit `json:",omitempty"`
+
+	// Commits are the commits reachable from Head but not from Base
+	// (i.e., base..head), newest first.
+	Commits []*Commit
+
+	// AheadBy is the number of commits reachable from Head but not
+	// from Base, and BehindBy is the number of commits reachable from
+	// Base but not from Head.
+	AheadBy, BehindBy int
+
+	// Files are the diffstats of the files that changed between
+	// MergeBase and Head.
+	Files []*FileDiffStat
+}
+
+// A FileDiffStat is the diffstat of a single file.
+type FileDiffStat struct {
+	OrigName string // the file's name before the change ("/dev/null" if it was added)
+	NewName  string // the file's name after the change ("/dev/null" if it was deleted)
+
+	diff.Stat
+}
+
+// DiffStat returns a diffstat that is the sum of all of the files'
+// diffstats.
+func (c *CommitsComparison) DiffStat() diff.Stat {
+	ds := diff.Stat{}
+	for _, f := range c.Files {
+		ds.Added += f.Added
+		ds.Changed += f.Changed
+		ds.Deleted += f.Deleted
+	}
+	return ds
+}
+
+func (s *repositoriesService) CompareCommits(ctx context.Context, head RepoRevSpec, opt *RepoCompareCommitsOptions) (*CommitsComparison, Response, error) {
+	url, err := s.client.URL(router.RepoCompareCommits, head.RouteVars(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cmp *CommitsComparison
+	resp, err := s.client.Do(ctx, req, &cmp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cmp, resp, nil
+}
+
 type RepoListBranchesOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListBranches(repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error) {
+func (s *repositoriesService) ListBranches(ctx context.Context, repo RepoSpec, opt *RepoListBranchesOptions) ([]*vcs.Branch, Response, error) {
 	url, err := s.client.URL(router.RepoBranches, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -697,7 +778,7 @@ func (s *repositoriesService) ListBranches(repo RepoSpec, opt *RepoListBranchesO
 	}
 
 	var branches []*vcs.Branch
-	resp, err := s.client.Do(req, &branches)
+	resp, err := s.client.Do(ctx, req, &branches)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -709,7 +790,7 @@ type RepoListTagsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListTags(repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error) {
+func (s *repositoriesService) ListTags(ctx context.Context, repo RepoSpec, opt *RepoListTagsOptions) ([]*vcs.Tag, Response, error) {
 	url, err := s.client.URL(router.RepoTags, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -721,7 +802,7 @@ func (s *repositoriesService) ListTags(repo RepoSpec, opt *RepoListTagsOptions)
 	}
 
 	var tags []*vcs.Tag
-	resp, err := s.client.Do(req, &tags)
+	resp, err := s.client.Do(ctx, req, &tags)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -741,7 +822,7 @@ func (b *Badge) HTML() string {
 	return fmt.Sprintf(`<img src="%s" alt="%s">`, template.HTMLEscapeString(b.ImageURL), template.HTMLEscapeString(b.Name))
 }
 
-func (s *repositoriesService) ListBadges(repo RepoSpec) ([]*Badge, Response, error) {
+func (s *repositoriesService) ListBadges(ctx context.Context, repo RepoSpec) ([]*Badge, Response, error) {
 	url, err := s.client.URL(router.RepoBadges, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -753,7 +834,7 @@ func (s *repositoriesService) ListBadges(repo RepoSpec) ([]*Badge, Response, err
 	}
 
 	var badges []*Badge
-	resp, err := s.client.Do(req, &badges)
+	resp, err := s.client.Do(ctx, req, &badges)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -773,7 +854,7 @@ func (c *Counter) HTML() string {
 	return fmt.Sprintf(`<img src="%s" alt="%s">`, template.HTMLEscapeString(c.ImageURL), template.HTMLEscapeString(c.Name))
 }
 
-func (s *repositoriesService) ListCounters(repo RepoSpec) ([]*Counter, Response, error) {
+func (s *repositoriesService) ListCounters(ctx context.Context, repo RepoSpec) ([]*Counter, Response, error) {
 	url, err := s.client.URL(router.RepoCounters, repo.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -785,7 +866,7 @@ func (s *repositoriesService) ListCounters(repo RepoSpec) ([]*Counter, Response,
 	}
 
 	var counters []*Counter
-	resp, err := s.client.Do(req, &counters)
+	resp, err := s.client.Do(ctx, req, &counters)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -810,7 +891,7 @@ type RepoListAuthorsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListAuthors(repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error) {
+func (s *repositoriesService) ListAuthors(ctx context.Context, repo RepoRevSpec, opt *RepoListAuthorsOptions) ([]*AugmentedRepoAuthor, Response, error) {
 	url, err := s.client.URL(router.RepoAuthors, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -822,7 +903,7 @@ func (s *repositoriesService) ListAuthors(repo RepoRevSpec, opt *RepoListAuthors
 	}
 
 	var authors []*AugmentedRepoAuthor
-	resp, err := s.client.Do(req, &authors)
+	resp, err := s.client.Do(ctx, req, &authors)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -866,7 +947,7 @@ type RepoListClientsOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListClients(repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error) {
+func (s *repositoriesService) ListClients(ctx context.Context, repo RepoSpec, opt *RepoListClientsOptions) ([]*AugmentedRepoClient, Response, error) {
 	url, err := s.client.URL(router.RepoClients, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -878,7 +959,7 @@ func (s *repositoriesService) ListClients(repo RepoSpec, opt *RepoListClientsOpt
 	}
 
 	var clients []*AugmentedRepoClient
-	resp, err := s.client.Do(req, &clients)
+	resp, err := s.client.Do(ctx, req, &clients)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -899,7 +980,7 @@ type RepoListDependenciesOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListDependencies(repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error) {
+func (s *repositoriesService) ListDependencies(ctx context.Context, repo RepoRevSpec, opt *RepoListDependenciesOptions) ([]*AugmentedRepoDependency, Response, error) {
 	url, err := s.client.URL(router.RepoDependencies, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -911,7 +992,7 @@ func (s *repositoriesService) ListDependencies(repo RepoRevSpec, opt *RepoListDe
 	}
 
 	var dependencies []*AugmentedRepoDependency
-	resp, err := s.client.Do(req, &dependencies)
+	resp, err := s.client.Do(ctx, req, &dependencies)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -930,7 +1011,7 @@ type AugmentedRepoDependent struct {
 
 type RepoListDependentsOptions struct{ ListOptions }
 
-func (s *repositoriesService) ListDependents(repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error) {
+func (s *repositoriesService) ListDependents(ctx context.Context, repo RepoSpec, opt *RepoListDependentsOptions) ([]*AugmentedRepoDependent, Response, error) {
 	url, err := s.client.URL(router.RepoDependents, repo.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -942,7 +1023,7 @@ func (s *repositoriesService) ListDependents(repo RepoSpec, opt *RepoListDepende
 	}
 
 	var dependents []*AugmentedRepoDependent
-	resp, err := s.client.Do(req, &dependents)
+	resp, err := s.client.Do(ctx, req, &dependents)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -984,7 +1065,7 @@ type RepoListByContributorOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByContributor(user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error) {
+func (s *repositoriesService) ListByContributor(ctx context.Context, user UserSpec, opt *RepoListByContributorOptions) ([]*AugmentedRepoContribution, Response, error) {
 	url, err := s.client.URL(router.UserRepoContributions, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -996,7 +1077,7 @@ func (s *repositoriesService) ListByContributor(user UserSpec, opt *RepoListByCo
 	}
 
 	var repos []*AugmentedRepoContribution
-	resp, err := s.client.Do(req, &repos)
+	resp, err := s.client.Do(ctx, req, &repos)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -1029,7 +1110,7 @@ type RepoListByClientOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByClient(user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error) {
+func (s *repositoriesService) ListByClient(ctx context.Context, user UserSpec, opt *RepoListByClientOptions) ([]*AugmentedRepoUsageByClient, Response, error) {
 	url, err := s.client.URL(router.UserRepoDependencies, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -1041,7 +1122,7 @@ func (s *repositoriesService) ListByClient(user UserSpec, opt *RepoListByClientO
 	}
 
 	var repos []*AugmentedRepoUsageByClient
-	resp, err := s.client.Do(req, &repos)
+	resp, err := s.client.Do(ctx, req, &repos)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -1068,7 +1149,7 @@ type RepoListByRefdAuthorOptions struct {
 	ListOptions
 }
 
-func (s *repositoriesService) ListByRefdAuthor(user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error) {
+func (s *repositoriesService) ListByRefdAuthor(ctx context.Context, user UserSpec, opt *RepoListByRefdAuthorOptions) ([]*AugmentedRepoUsageOfAuthor, Response, error) {
 	url, err := s.client.URL(router.UserRepoDependents, user.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -1080,7 +1161,7 @@ func (s *repositoriesService) ListByRefdAuthor(user UserSpec, opt *RepoListByRef
 	}
 
 	var repos []*AugmentedRepoUsageOfAuthor
-	resp, err := s.client.Do(req, &repos)
+	resp, err := s.client.Do(ctx, req, &repos)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"fmt"
 
 	"sourcegraph.com/sourcegraph/vcsstore/vcsclient"
@@ -11,7 +12,7 @@ import (
 // RepoTreeService communicates with the Sourcegraph API endpoints that
 // fetch file and directory entries in repositories.
 type RepoTreeService interface {
-	Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
+	Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
 }
 
 type repoTreeService struct {
@@ -31,6 +32,17 @@ func (s *TreeEntrySpec) RouteVars() map[string]string {
 	return m
 }
 
+// UnmarshalTreeEntrySpec marshals a map containing route variables
+// generated by (*TreeEntrySpec).RouteVars() and returns the
+// equivalent TreeEntrySpec struct.
+func UnmarshalTreeEntrySpec(routeVars map[string]string) (TreeEntrySpec, error) {
+	repoRev, err := UnmarshalRepoRevSpec(routeVars)
+	if err != nil {
+		return TreeEntrySpec{}, err
+	}
+	return TreeEntrySpec{RepoRev: repoRev, Path: routeVars["Path"]}, nil
+}
+
 func (s TreeEntrySpec) String() string {
 	return fmt.Sprintf("%v: %s (rev %q)", s.RepoRev, s.Path, s.RepoRev.Rev)
 }
@@ -136,7 +148,7 @@ type RepoTreeGetOptions struct {
 	vcsclient.GetFileOptions
 }
 
-func (s *repoTreeService) Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
+func (s *repoTreeService) Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
 	url, err := s.client.URL(router.RepoTreeEntry, entry.RouteVars(), opt)
 	if err != nil {
 		return nil, nil, err
@@ -148,7 +160,7 @@ func (s *repoTreeService) Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*Tr
 	}
 
 	var entry_ *TreeEntry
-	resp, err := s.client.Do(req, &entry_)
+	resp, err := s.client.Do(ctx, req, &entry_)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -1,9 +1,11 @@
 package sourcegraph
 
+import "context"
+
 type MockRepoTreeService struct {
-	Get_ func(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
+	Get_ func(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error)
 }
 
-func (s MockRepoTreeService) Get(entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
-	return s.Get_(entry, opt)
+func (s MockRepoTreeService) Get(ctx context.Context, entry TreeEntrySpec, opt *RepoTreeGetOptions) (*TreeEntry, Response, error) {
+	return s.Get_(ctx, entry, opt)
 }
@@ -1,6 +1,7 @@
 package sourcegraph
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -45,7 +46,7 @@ func TestRepoTreeService_Get(t *testing.T) {
 			ExpandContextLines: 2,
 		},
 	}
-	data, _, err := client.RepoTree.Get(TreeEntrySpec{
+	data, _, err := client.RepoTree.Get(context.Background(), TreeEntrySpec{
 		RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v"},
 		Path:    "p",
 	}, opt)
@@ -0,0 +1,190 @@
+package sourcegraph
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// A RetryPolicy specifies how a Client retries requests that fail
+// because of transient errors: HTTP 502, 503, and 504 responses,
+// HTTP 429 responses, and connection resets and refusals (such as
+// those that occur while the API server is restarting).
+//
+// Only requests with idempotent methods (GET, HEAD, and PUT) are
+// retried by default. Requests to other routes (such as POST to
+// router.BuildDequeueNext) are only retried if their route name is
+// listed in Routes.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried
+	// (not counting the initial attempt).
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the exponentially increasing
+	// delay between attempts. If zero, 100ms and 10s are used,
+	// respectively. A random jitter of up to half of the delay is
+	// subtracted from each delay.
+	//
+	// If a response has a Retry-After header, its value (capped at
+	// MaxBackoff) is used as the delay instead.
+	MinBackoff, MaxBackoff time.Duration
+
+	// Routes are the names of additional routes (in package router)
+	// whose requests may be retried regardless of their method.
+	//
+	// Only list a non-idempotent route if retrying it is safe. For
+	// example, retrying router.BuildDequeueNext after the server has
+	// dequeued a build but before the client received the response
+	// leaves that build started but unworked (until it is killed for
+	// lack of a heartbeat).
+	Routes []string
+}
+
+// DefaultRetryPolicy is a RetryPolicy with reasonable defaults for
+// idempotent requests.
+var DefaultRetryPolicy = &RetryPolicy{MaxRetries: 4}
+
+// retryable returns whether req may be retried under this policy.
+func (p *RetryPolicy) retryable(c *Client, req *http.Request) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false // can't rewind body
+	}
+	switch req.Method {
+	case "GET", "HEAD", "PUT":
+		return true
+	}
+	if len(p.Routes) > 0 {
+		name := c.routeName(req)
+		for _, route := range p.Routes {
+			if route == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt, given
+// the number of the attempt that just failed (starting at 0) and its
+// response (if any).
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+
+	if resp != nil {
+		if d, ok := retryAfter(resp); ok {
+			// Don't let the server stall the client indefinitely.
+			if d > max {
+				d = max
+			}
+			return d
+		}
+	}
+
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// retryAfter returns the delay specified by resp's Retry-After
+// header, which may be either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(time.Now())
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// shouldRetry returns whether a request attempt that yielded resp
+// and err failed with a transient error.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// maxDrainBytes is the maximum number of bytes of a failed attempt's
+// response body that are read before it is closed. Larger bodies are
+// not worth reading just to reuse the connection.
+const maxDrainBytes = 64 << 10
+
+// send sends req, retrying it according to c.RetryPolicy.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	p := c.RetryPolicy
+	if p == nil || !p.retryable(c, req) {
+		return c.httpClient.Do(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := c.httpClient.Do(attemptReq)
+		if attempt >= p.MaxRetries || ctx.Err() != nil || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt, resp)
+		if resp != nil {
+			// Drain (a bounded amount of) the body so the connection
+			// can be reused.
+			io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
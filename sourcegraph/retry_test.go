@@ -0,0 +1,164 @@
+package sourcegraph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func newTestRequest(t *testing.T, method, routeName string, routeVars map[string]string, body interface{}) *http.Request {
+	url, err := client.URL(routeName, routeVars, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := client.NewRequest(method, url.String(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestRetryPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, &Repo{RID: 1})
+	})
+
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}
+
+	var repo *Repo
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	if _, err := client.Do(context.Background(), req, &repo); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+	if repo == nil || repo.RID != 1 {
+		t.Errorf("got repo %+v, want RID 1", repo)
+	}
+}
+
+func TestRetryPolicy_exhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	_, err := client.Do(context.Background(), req, nil)
+	if !IsHTTPErrorCode(err, http.StatusBadGateway) {
+		t.Errorf("got error %v, want HTTP %d", err, http.StatusBadGateway)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestRetryPolicy_POST(t *testing.T) {
+	tests := []struct {
+		routes    []string
+		wantCalls int
+	}{
+		{routes: nil, wantCalls: 1},
+		{routes: []string{router.BuildDequeueNext}, wantCalls: 2},
+	}
+	for _, test := range tests {
+		func() {
+			setup()
+			defer teardown()
+
+			var calls int
+			mux.HandleFunc(urlPath(t, router.BuildDequeueNext, nil), func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				testMethod(t, r, "POST")
+				testBody(t, r, `{"x":1}`+"\n")
+				if calls == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				writeJSON(w, &Build{BID: 1})
+			})
+
+			client.RetryPolicy = &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, Routes: test.routes}
+
+			req := newTestRequest(t, "POST", router.BuildDequeueNext, nil, map[string]int{"x": 1})
+			client.Do(context.Background(), req, nil)
+			if calls != test.wantCalls {
+				t.Errorf("routes %v: got %d calls, want %d", test.routes, calls, test.wantCalls)
+			}
+		}()
+	}
+}
+
+func TestRetryPolicy_connectionReset(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int
+	mux.HandleFunc(urlPath(t, router.Repo, map[string]string{"RepoSpec": "r.com/x"}), func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+			return
+		}
+		writeJSON(w, &Repo{RID: 1})
+	})
+
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}
+
+	req := newTestRequest(t, "GET", router.Repo, map[string]string{"RepoSpec": "r.com/x"}, nil)
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond, 200 * time.Millisecond},
+		{2, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+	for _, test := range tests {
+		if d := p.backoff(test.attempt, nil); d < test.min || d > test.max {
+			t.Errorf("attempt %d: got backoff %s, want between %s and %s", test.attempt, d, test.min, test.max)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if d := DefaultRetryPolicy.backoff(0, resp); d != 3*time.Second {
+		t.Errorf("got Retry-After backoff %s, want 3s", d)
+	}
+	if d := p.backoff(0, resp); d != time.Second {
+		t.Errorf("got Retry-After backoff %s, want it capped at MaxBackoff (1s)", d)
+	}
+}
@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 )
 
@@ -8,15 +10,15 @@ import (
 // the Sourcegraph API.
 type SearchService interface {
 	// Search searches the full index.
-	Search(opt *SearchOptions) (*SearchResults, Response, error)
+	Search(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error)
 
 	// Complete completes the token at the RawQuery's InsertionPoint.
-	Complete(q RawQuery) (*Completions, Response, error)
+	Complete(ctx context.Context, q RawQuery) (*Completions, Response, error)
 
 	// Suggest suggests queries given an existing query. It can be
 	// called with an empty query to get example queries that pertain
 	// to the current user's repositories, orgs, etc.
-	Suggest(q RawQuery) ([]*Suggestion, Response, error)
+	Suggest(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error)
 }
 
 type SearchResults struct {
@@ -73,7 +75,7 @@ type SearchOptions struct {
 	ListOptions
 }
 
-func (s *searchService) Search(opt *SearchOptions) (*SearchResults, Response, error) {
+func (s *searchService) Search(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error) {
 	url, err := s.client.URL(router.Search, nil, opt)
 	if err != nil {
 		return nil, nil, err
@@ -85,7 +87,7 @@ func (s *searchService) Search(opt *SearchOptions) (*SearchResults, Response, er
 	}
 
 	var results *SearchResults
-	resp, err := s.client.Do(req, &results)
+	resp, err := s.client.Do(ctx, req, &results)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -107,7 +109,7 @@ type Completions struct {
 	ResolutionFatal bool         `json:",omitempty"`
 }
 
-func (s *searchService) Complete(q RawQuery) (*Completions, Response, error) {
+func (s *searchService) Complete(ctx context.Context, q RawQuery) (*Completions, Response, error) {
 	url, err := s.client.URL(router.SearchComplete, nil, q)
 	if err != nil {
 		return nil, nil, err
@@ -119,7 +121,7 @@ func (s *searchService) Complete(q RawQuery) (*Completions, Response, error) {
 	}
 
 	var comps *Completions
-	resp, err := s.client.Do(req, &comps)
+	resp, err := s.client.Do(ctx, req, &comps)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -127,7 +129,7 @@ func (s *searchService) Complete(q RawQuery) (*Completions, Response, error) {
 	return comps, resp, nil
 }
 
-func (s *searchService) Suggest(q RawQuery) ([]*Suggestion, Response, error) {
+func (s *searchService) Suggest(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error) {
 	url, err := s.client.URL(router.SearchSuggestions, nil, q)
 	if err != nil {
 		return nil, nil, err
@@ -139,7 +141,7 @@ func (s *searchService) Suggest(q RawQuery) ([]*Suggestion, Response, error) {
 	}
 
 	var suggs []*Suggestion
-	resp, err := s.client.Do(req, &suggs)
+	resp, err := s.client.Do(ctx, req, &suggs)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -1,21 +1,23 @@
 package sourcegraph
 
+import "context"
+
 type MockSearchService struct {
-	Search_   func(opt *SearchOptions) (*SearchResults, Response, error)
-	Complete_ func(q RawQuery) (*Completions, Response, error)
-	Suggest_  func(q RawQuery) ([]*Suggestion, Response, error)
+	Search_   func(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error)
+	Complete_ func(ctx context.Context, q RawQuery) (*Completions, Response, error)
+	Suggest_  func(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error)
 }
 
 var _ SearchService = MockSearchService{}
 
-func (s MockSearchService) Search(opt *SearchOptions) (*SearchResults, Response, error) {
-	return s.Search_(opt)
+func (s MockSearchService) Search(ctx context.Context, opt *SearchOptions) (*SearchResults, Response, error) {
+	return s.Search_(ctx, opt)
 }
 
-func (s MockSearchService) Complete(q RawQuery) (*Completions, Response, error) {
-	return s.Complete_(q)
+func (s MockSearchService) Complete(ctx context.Context, q RawQuery) (*Completions, Response, error) {
+	return s.Complete_(ctx, q)
 }
 
-func (s MockSearchService) Suggest(q RawQuery) ([]*Suggestion, Response, error) {
-	return s.Suggest_(q)
+func (s MockSearchService) Suggest(ctx context.Context, q RawQuery) ([]*Suggestion, Response, error) {
+	return s.Suggest_(ctx, q)
 }
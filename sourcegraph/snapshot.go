@@ -0,0 +1,209 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// A Snapshot pins repository revisions to commits for the duration
+// of a session (such as rendering a page), so that all of the API
+// calls that the session makes see the same commit of each
+// repository. It resolves each repository revision to a commit ID
+// only once (with Repos.GetCommit, or Repos.GetBuild if
+// BuildOptions is set), and fills in that commit ID in the specs that
+// are passed through it. See RepoRevSpec for why this matters.
+//
+// For example:
+//
+//	snap := sourcegraph.NewSnapshot(client)
+//	entry, err := snap.TreeEntry(ctx, sourcegraph.TreeEntrySpec{RepoRev: repoRev, Path: "foo.go"})
+//	if err != nil {
+//		// ...
+//	}
+//	file, _, err := client.RepoTree.Get(ctx, entry, nil)
+//	// Later calls with repoRev (or specs for the same repository and
+//	// revision) use the same commit, even if it was pushed to.
+//
+// Revisions that are already full commit IDs, and specs whose
+// CommitID is already set, are not resolved. A spec's CommitID is
+// kept, even if its revision is pinned to another commit, and it is
+// recorded as the pinned commit if its revision isn't pinned yet.
+// Revisions are pinned by repository URI, so a repository specified
+// by RID pins the same commits as it does when specified by URI (the
+// URI of a RID-only spec is looked up once, with Repos.Get, and filled
+// in). A Snapshot is safe for concurrent use.
+type Snapshot struct {
+	// BuildOptions, if non-nil, makes the Snapshot resolve revisions
+	// to the commits of their builds (using Repos.GetBuild with these
+	// options), instead of to the commits that they currently refer
+	// to. This pins revisions to commits that have build data (such
+	// as defs and units).
+	BuildOptions *RepoGetBuildOptions
+
+	client *Client
+
+	mu      sync.Mutex
+	commits map[string]string // repo URI and revision -> commit ID
+	uris    map[int]string    // repo RID -> URI
+}
+
+// NewSnapshot returns a new Snapshot that resolves revisions using c.
+func NewSnapshot(c *Client) *Snapshot {
+	return &Snapshot{client: c, commits: map[string]string{}, uris: map[int]string{}}
+}
+
+// RepoRev returns rev with its CommitID set to the pinned commit
+// (unless it is already set; see Snapshot). If rev's Rev is empty (referring to the default branch), it is set to
+// the commit ID, because the API requires a revision with a commit
+// ID.
+func (s *Snapshot) RepoRev(ctx context.Context, rev RepoRevSpec) (RepoRevSpec, error) {
+	uri, err := s.repoURI(ctx, rev.RepoSpec)
+	if err != nil {
+		return RepoRevSpec{}, err
+	}
+	rev.URI = uri
+	key := uri + "@" + rev.Rev
+	if rev.CommitID != "" {
+		s.pin(key, rev.CommitID)
+	} else {
+		commitID, pinned := s.pinned(key)
+		if !pinned {
+			if isAbsCommitID(rev.Rev) {
+				commitID = rev.Rev
+			} else {
+				commitID, err = s.resolve(ctx, rev)
+				if err != nil {
+					return RepoRevSpec{}, err
+				}
+			}
+			commitID = s.pin(key, commitID)
+		}
+		rev.CommitID = commitID
+	}
+
+	if rev.Rev == "" {
+		rev.Rev = rev.CommitID
+	}
+	return rev, nil
+}
+
+// TreeEntry returns entry with its repository revision pinned (see
+// RepoRev).
+func (s *Snapshot) TreeEntry(ctx context.Context, entry TreeEntrySpec) (TreeEntrySpec, error) {
+	repoRev, err := s.RepoRev(ctx, entry.RepoRev)
+	if err != nil {
+		return TreeEntrySpec{}, err
+	}
+	entry.RepoRev = repoRev
+	return entry, nil
+}
+
+// Unit returns unit with its repository revision pinned (see
+// RepoRev).
+func (s *Snapshot) Unit(ctx context.Context, unit UnitSpec) (UnitSpec, error) {
+	repoRev, err := s.RepoRev(ctx, unit.RepoRevSpec)
+	if err != nil {
+		return UnitSpec{}, err
+	}
+	unit.RepoRevSpec = repoRev
+	return unit, nil
+}
+
+// Def returns def with its CommitID set to the pinned commit. A def's
+// CommitID may be a revision (or empty, for the default branch), which
+// is resolved like RepoRev's.
+func (s *Snapshot) Def(ctx context.Context, def DefSpec) (DefSpec, error) {
+	rev := RepoRevSpec{RepoSpec: RepoSpec{URI: def.Repo}, Rev: def.CommitID}
+	repoRev, err := s.RepoRev(ctx, rev)
+	if err != nil {
+		return DefSpec{}, err
+	}
+	def.CommitID = repoRev.CommitID
+	return def, nil
+}
+
+// Delta returns delta with its base and head repository revisions
+// pinned (see RepoRev).
+func (s *Snapshot) Delta(ctx context.Context, delta DeltaSpec) (DeltaSpec, error) {
+	base, err := s.RepoRev(ctx, delta.Base)
+	if err != nil {
+		return DeltaSpec{}, err
+	}
+	head, err := s.RepoRev(ctx, delta.Head)
+	if err != nil {
+		return DeltaSpec{}, err
+	}
+	delta.Base, delta.Head = base, head
+	return delta, nil
+}
+
+// repoURI returns repo's URI, getting it with Repos.Get (once per
+// RID) if repo has only a RID.
+func (s *Snapshot) repoURI(ctx context.Context, repo RepoSpec) (string, error) {
+	if repo.URI != "" {
+		return repo.URI, nil
+	}
+	if repo.RID == 0 {
+		return "", errors.New("Snapshot: RepoSpec has no URI or RID")
+	}
+	s.mu.Lock()
+	uri, ok := s.uris[repo.RID]
+	s.mu.Unlock()
+	if ok {
+		return uri, nil
+	}
+
+	r, _, err := s.client.Repos.Get(ctx, repo, nil)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uris[repo.RID] = r.URI
+	return r.URI, nil
+}
+
+// pinned returns the commit ID that key is pinned to, if any.
+func (s *Snapshot) pinned(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	commitID, ok := s.commits[key]
+	return commitID, ok
+}
+
+// pin pins key to commitID, unless it was already pinned (by a
+// concurrent call), and returns the commit ID that key is pinned to.
+func (s *Snapshot) pin(key, commitID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pinned, ok := s.commits[key]; ok {
+		return pinned
+	}
+	s.commits[key] = commitID
+	return commitID
+}
+
+// resolve resolves rev to a commit ID using the API.
+func (s *Snapshot) resolve(ctx context.Context, rev RepoRevSpec) (string, error) {
+	if s.BuildOptions != nil {
+		info, _, err := s.client.Repos.GetBuild(ctx, rev, s.BuildOptions)
+		if err != nil {
+			return "", err
+		}
+		build := info.Exact
+		if build == nil {
+			build = info.LastSuccessful
+		}
+		if build == nil {
+			return "", ErrNoRepoBuild
+		}
+		return build.CommitID, nil
+	}
+
+	commit, _, err := s.client.Repos.GetCommit(ctx, rev, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(commit.ID), nil
+}
@@ -0,0 +1,189 @@
+package sourcegraph
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-vcs/vcs"
+)
+
+func TestSnapshot(t *testing.T) {
+	const (
+		c1 = "1111111111111111111111111111111111111111"
+		c2 = "2222222222222222222222222222222222222222"
+		c3 = "3333333333333333333333333333333333333333"
+	)
+	var mu sync.Mutex
+	head := c1 // the commit that every revision currently refers to
+	calls := map[string]int{}
+	c := NewMockClient()
+	c.Repos = &MockReposService{
+		GetCommit_: func(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[rev.URI+"@"+rev.Rev]++
+			return &Commit{&vcs.Commit{ID: vcs.CommitID(head)}}, nil, nil
+		},
+	}
+	ctx := context.Background()
+	s := NewSnapshot(c)
+	repoRev := RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "master"}
+
+	got, err := s.RepoRev(ctx, repoRev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "master", CommitID: c1}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Someone pushes, but the session still sees the pinned commit.
+	mu.Lock()
+	head = c2
+	mu.Unlock()
+
+	entry, err := s.TreeEntry(ctx, TreeEntrySpec{RepoRev: repoRev, Path: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.RepoRev.CommitID != c1 {
+		t.Errorf("got tree entry commit ID %q, want %q", entry.RepoRev.CommitID, c1)
+	}
+	unit, err := s.Unit(ctx, UnitSpec{RepoRevSpec: repoRev, UnitType: "t", Unit: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit.CommitID != c1 {
+		t.Errorf("got unit commit ID %q, want %q", unit.CommitID, c1)
+	}
+	def, err := s.Def(ctx, DefSpec{Repo: "r.com/a", CommitID: "master", UnitType: "t", Unit: "u", Path: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (DefSpec{Repo: "r.com/a", CommitID: c1, UnitType: "t", Unit: "u", Path: "p"}); def != want {
+		t.Errorf("got def %+v, want %+v", def, want)
+	}
+
+	// Other revisions are resolved (once) when they're first used.
+	delta, err := s.Delta(ctx, DeltaSpec{
+		Base: repoRev,
+		Head: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "feature"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.Base.CommitID != c1 || delta.Head.CommitID != c2 {
+		t.Errorf("got delta %+v, want base %q and head %q", delta, c1, c2)
+	}
+
+	// Revisions that are commit IDs, and specs with commit IDs, need
+	// no resolution.
+	if got, _ := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: c3}); got.CommitID != c3 {
+		t.Errorf("got commit ID %q, want %q", got.CommitID, c3)
+	}
+	if got, _ := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "v1", CommitID: c3}); got.CommitID != c3 {
+		t.Errorf("got commit ID %q, want %q", got.CommitID, c3)
+	}
+
+	// An explicit commit ID is kept even if the revision is pinned to
+	// another commit, and it doesn't change the pin.
+	if got, _ := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "master", CommitID: c3}); got.CommitID != c3 {
+		t.Errorf("got commit ID %q, want the explicit %q", got.CommitID, c3)
+	}
+	if got, _ := s.RepoRev(ctx, repoRev); got.CommitID != c1 {
+		t.Errorf("got commit ID %q, want the pinned %q", got.CommitID, c1)
+	}
+
+	// The default branch is pinned with the commit ID as its revision.
+	if got, _ := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}}); got.Rev != c2 || got.CommitID != c2 {
+		t.Errorf("got %+v, want Rev and CommitID %q", got, c2)
+	}
+
+	if want := map[string]int{"r.com/a@master": 1, "r.com/a@feature": 1, "r.com/a@": 1}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got GetCommit calls %v, want %v", calls, want)
+	}
+}
+
+func TestSnapshot_RID(t *testing.T) {
+	const c1, c2 = "1111111111111111111111111111111111111111", "2222222222222222222222222222222222222222"
+	head := c1
+	var gets int
+	c := NewMockClient()
+	c.Repos = &MockReposService{
+		Get_: func(ctx context.Context, repo RepoSpec, opt *RepoGetOptions) (*Repo, Response, error) {
+			gets++
+			if repo.RID != 1 {
+				return nil, nil, ErrNotExist
+			}
+			return &Repo{RID: 1, URI: "r.com/a"}, nil, nil
+		},
+		GetCommit_: func(ctx context.Context, rev RepoRevSpec, opt *RepoGetCommitOptions) (*Commit, Response, error) {
+			if rev.URI != "r.com/a" {
+				t.Errorf("got GetCommit for %+v, want URI r.com/a", rev.RepoSpec)
+			}
+			return &Commit{&vcs.Commit{ID: vcs.CommitID(head)}}, nil, nil
+		},
+	}
+	ctx := context.Background()
+	s := NewSnapshot(c)
+
+	got, err := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{RID: 1}, Rev: "master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a", RID: 1}, Rev: "master", CommitID: c1}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// The URI form of the same repository sees the same commit (as
+	// does the RID form again, without another Repos.Get).
+	head = c2
+	for _, repo := range []RepoSpec{{URI: "r.com/a"}, {URI: "r.com/a", RID: 1}, {RID: 1}} {
+		got, err := s.RepoRev(ctx, RepoRevSpec{RepoSpec: repo, Rev: "master"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.CommitID != c1 {
+			t.Errorf("%+v: got commit ID %q, want %q", repo, got.CommitID, c1)
+		}
+	}
+	if gets != 1 {
+		t.Errorf("got %d Repos.Get calls, want 1", gets)
+	}
+
+	if _, err := s.RepoRev(ctx, RepoRevSpec{RepoSpec: RepoSpec{RID: 2}, Rev: "master"}); err != ErrNotExist {
+		t.Errorf("got error %v, want %v", err, ErrNotExist)
+	}
+	if _, err := s.RepoRev(ctx, RepoRevSpec{Rev: "master"}); err == nil {
+		t.Error("got no error for empty RepoSpec, want error")
+	}
+}
+
+func TestSnapshot_BuildOptions(t *testing.T) {
+	const commitID = "1111111111111111111111111111111111111111"
+	c := NewMockClient()
+	c.Repos = &MockReposService{
+		GetBuild_: func(ctx context.Context, rev RepoRevSpec, opt *RepoGetBuildOptions) (*RepoBuildInfo, Response, error) {
+			if rev.URI == "r.com/a" {
+				return &RepoBuildInfo{LastSuccessful: &Build{CommitID: commitID}}, nil, nil
+			}
+			return &RepoBuildInfo{}, nil, nil
+		},
+	}
+	s := NewSnapshot(c)
+	s.BuildOptions = &RepoGetBuildOptions{}
+
+	got, err := s.RepoRev(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/a"}, Rev: "master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CommitID != commitID {
+		t.Errorf("got commit ID %q, want %q", got.CommitID, commitID)
+	}
+
+	if _, err := s.RepoRev(context.Background(), RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/b"}, Rev: "master"}); err != ErrNoRepoBuild {
+		t.Errorf("got error %v, want %v", err, ErrNoRepoBuild)
+	}
+}
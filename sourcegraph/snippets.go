@@ -0,0 +1,107 @@
+package sourcegraph
+
+import (
+	"context"
+	"errors"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+// SnippetsService communicates with the snippet-related endpoints in
+// the Sourcegraph API.
+type SnippetsService interface {
+	// Annotate links and syntax-highlights a snippet of code. The
+	// snippet is either the file data in snippet.FileData (which need
+	// only contain the Raw contents and the repository and file that
+	// they came from) or the source of the def specified by
+	// snippet.Def. The returned FileData's Annotated field contains
+	// the linked and highlighted HTML.
+	Annotate(ctx context.Context, snippet *SnippetRequestBody) (*FileData, Response, error)
+
+	// FileSourcebox returns an embeddable sourcebox for a file (or
+	// range of lines in a file).
+	FileSourcebox(ctx context.Context, entry TreeEntrySpec, opt *SourceboxFileOptions) (*Sourcebox, Response, error)
+
+	// DefSourcebox returns an embeddable sourcebox for a def.
+	DefSourcebox(ctx context.Context, def DefSpec) (*Sourcebox, Response, error)
+}
+
+type snippetsService struct {
+	client *Client
+}
+
+var _ SnippetsService = &snippetsService{}
+
+// SnippetRequestBody is the snippet to annotate in a call to
+// (SnippetsService).Annotate. Exactly one of FileData and Def must
+// be set.
+type SnippetRequestBody struct {
+	FileData *FileData `json:",omitempty"`
+	Def      *DefSpec  `json:",omitempty"`
+}
+
+// SourceboxFileOptions specifies options for
+// (SnippetsService).FileSourcebox.
+type SourceboxFileOptions struct {
+	// StartLine and EndLine, if set, restrict the sourcebox to the
+	// given (1-indexed, inclusive) range of lines in the file.
+	StartLine int `url:",omitempty"`
+	EndLine   int `url:",omitempty"`
+}
+
+func (s *snippetsService) Annotate(ctx context.Context, snippet *SnippetRequestBody) (*FileData, Response, error) {
+	if (snippet.FileData == nil) == (snippet.Def == nil) {
+		return nil, nil, errors.New("exactly one of FileData and Def must be set in snippet")
+	}
+
+	url, err := s.client.URL(router.Snippet, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("POST", url.String(), snippet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var data FileData
+	resp, err := s.client.Do(ctx, req, &data)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &data, resp, nil
+}
+
+func (s *snippetsService) FileSourcebox(ctx context.Context, entry TreeEntrySpec, opt *SourceboxFileOptions) (*Sourcebox, Response, error) {
+	url, err := s.client.URL(router.RepoTreeSourcebox, entry.RouteVars(), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.getSourcebox(ctx, url.String())
+}
+
+func (s *snippetsService) DefSourcebox(ctx context.Context, def DefSpec) (*Sourcebox, Response, error) {
+	url, err := s.client.URL(router.DefSourcebox, def.RouteVars(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.getSourcebox(ctx, url.String())
+}
+
+// getSourcebox fetches the sourcebox at urlStr (a RepoTreeSourcebox
+// or DefSourcebox route URL).
+func (s *snippetsService) getSourcebox(ctx context.Context, urlStr string) (*Sourcebox, Response, error) {
+	req, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sourcebox Sourcebox
+	resp, err := s.client.Do(ctx, req, &sourcebox)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &sourcebox, resp, nil
+}
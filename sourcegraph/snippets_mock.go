@@ -0,0 +1,21 @@
+package sourcegraph
+
+import "context"
+
+type MockSnippetsService struct {
+	Annotate_      func(ctx context.Context, snippet *SnippetRequestBody) (*FileData, Response, error)
+	FileSourcebox_ func(ctx context.Context, entry TreeEntrySpec, opt *SourceboxFileOptions) (*Sourcebox, Response, error)
+	DefSourcebox_  func(ctx context.Context, def DefSpec) (*Sourcebox, Response, error)
+}
+
+func (s MockSnippetsService) Annotate(ctx context.Context, snippet *SnippetRequestBody) (*FileData, Response, error) {
+	return s.Annotate_(ctx, snippet)
+}
+
+func (s MockSnippetsService) FileSourcebox(ctx context.Context, entry TreeEntrySpec, opt *SourceboxFileOptions) (*Sourcebox, Response, error) {
+	return s.FileSourcebox_(ctx, entry, opt)
+}
+
+func (s MockSnippetsService) DefSourcebox(ctx context.Context, def DefSpec) (*Sourcebox, Response, error) {
+	return s.DefSourcebox_(ctx, def)
+}
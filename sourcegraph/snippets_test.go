@@ -0,0 +1,130 @@
+package sourcegraph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+)
+
+func TestSnippetsService_Annotate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &SnippetRequestBody{
+		FileData: &FileData{
+			RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v1"},
+			File:    "f.go",
+			Raw:     []byte("package f"),
+		},
+	}
+	want := &FileData{File: "f.go", Annotated: `<span class="kwd">package</span> f`}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.Snippet, nil), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "POST")
+
+		var body SnippetRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(&body, input) {
+			t.Errorf("got body %+v, want %+v", body, input)
+		}
+
+		writeJSON(w, want)
+	})
+
+	data, _, err := client.Snippets.Annotate(context.Background(), input)
+	if err != nil {
+		t.Errorf("Snippets.Annotate returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Snippets.Annotate returned %+v, want %+v", data, want)
+	}
+}
+
+func TestSnippetsService_Annotate_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, snippet := range []*SnippetRequestBody{
+		{},
+		{FileData: &FileData{}, Def: &DefSpec{}},
+	} {
+		if _, _, err := client.Snippets.Annotate(context.Background(), snippet); err == nil {
+			t.Errorf("%+v: got nil error, want error", snippet)
+		}
+	}
+}
+
+func TestSnippetsService_FileSourcebox(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &Sourcebox{HTML: "<div>f</div>", StylesheetURL: "s.css", ScriptURL: "s.js"}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.RepoTreeSourcebox, map[string]string{"RepoSpec": "r.com/x", "Rev": "v1", "Path": "f.go"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"StartLine": "3",
+			"EndLine":   "5",
+		})
+
+		writeJSON(w, want)
+	})
+
+	entry := TreeEntrySpec{RepoRev: RepoRevSpec{RepoSpec: RepoSpec{URI: "r.com/x"}, Rev: "v1"}, Path: "f.go"}
+	sourcebox, _, err := client.Snippets.FileSourcebox(context.Background(), entry, &SourceboxFileOptions{StartLine: 3, EndLine: 5})
+	if err != nil {
+		t.Errorf("Snippets.FileSourcebox returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	if !reflect.DeepEqual(sourcebox, want) {
+		t.Errorf("Snippets.FileSourcebox returned %+v, want %+v", sourcebox, want)
+	}
+}
+
+func TestSnippetsService_DefSourcebox(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &Sourcebox{HTML: "<div>d</div>"}
+
+	var called bool
+	mux.HandleFunc(urlPath(t, router.DefSourcebox, map[string]string{"RepoSpec": "r.com/x", "Rev": "c", "UnitType": "t", "Unit": "u", "Path": "p"}), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		testMethod(t, r, "GET")
+
+		writeJSON(w, want)
+	})
+
+	def := DefSpec{Repo: "r.com/x", CommitID: "c", UnitType: "t", Unit: "u", Path: "p"}
+	sourcebox, _, err := client.Snippets.DefSourcebox(context.Background(), def)
+	if err != nil {
+		t.Errorf("Snippets.DefSourcebox returned error: %v", err)
+	}
+
+	if !called {
+		t.Fatal("!called")
+	}
+
+	if !reflect.DeepEqual(sourcebox, want) {
+		t.Errorf("Snippets.DefSourcebox returned %+v, want %+v", sourcebox, want)
+	}
+}
@@ -5,10 +5,9 @@ import "html/template"
 // A Sourcebox represents an embeddable code snippet, either for a
 // file (or portion thereof) or for a def.
 //
-// It is not currently returned by any API client methods, but it is
-// available by changing the ".js" suffix in a Sourcebox embed URL to
-// ".json". This struct is provided for clients that manually
-// construct this URL to decode the response JSON.
+// Sourceboxes are fetched using (SnippetsService).FileSourcebox and
+// (SnippetsService).DefSourcebox. (They are also available by
+// changing the ".js" suffix in a Sourcebox embed URL to ".json".)
 type Sourcebox struct {
 	// HTML is the fully linked and rendered HTML of the sourcebox
 	// contents and surrounding box UI element.
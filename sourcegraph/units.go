@@ -1,6 +1,8 @@
 package sourcegraph
 
 import (
+	"context"
+
 	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
 	"sourcegraph.com/sourcegraph/srclib/unit"
 )
@@ -9,10 +11,10 @@ import (
 // the Sourcegraph API.
 type UnitsService interface {
 	// Get fetches a unit.
-	Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
+	Get(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error)
 
 	// List units.
-	List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
+	List(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error)
 }
 
 // UnitSpec specifies a source unit.
@@ -82,7 +84,7 @@ type UnitListOptions struct {
 	ListOptions
 }
 
-func (s *unitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
+func (s *unitsService) Get(ctx context.Context, spec UnitSpec) (*unit.RepoSourceUnit, Response, error) {
 	url, err := s.client.URL(router.Unit, spec.RouteVars(), nil)
 	if err != nil {
 		return nil, nil, err
@@ -94,7 +96,7 @@ func (s *unitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error
 	}
 
 	var u unit.RepoSourceUnit
-	resp, err := s.client.Do(req, &u)
+	resp, err := s.client.Do(ctx, req, &u)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -102,7 +104,7 @@ func (s *unitsService) Get(spec UnitSpec) (*unit.RepoSourceUnit, Response, error
 	return &u, resp, nil
 }
 
-func (s *unitsService) List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
+func (s *unitsService) List(ctx context.Context, opt *UnitListOptions) ([]*unit.RepoSourceUnit, Response, error) {
 	url, err := s.client.URL(router.Units, nil, opt)
 	if err != nil {
 		return nil, nil, err
@@ -114,7 +116,7 @@ func (s *unitsService) List(opt *UnitListOptions) ([]*unit.RepoSourceUnit, Respo
 	}
 
 	var units []*unit.RepoSourceUnit
-	resp, err := s.client.Do(req, &units)
+	resp, err := s.client.Do(ctx, req, &units)
 	if err != nil {
 		return nil, resp, err
 	}
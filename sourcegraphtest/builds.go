@@ -0,0 +1,395 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/db_common"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddBuild adds a build to the server. If build.BID is 0, a new BID
+// is assigned to it. It returns build.
+func (s *Server) AddBuild(build *sourcegraph.Build) *sourcegraph.Build {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addBuild(build)
+	return build
+}
+
+func (s *Server) addBuild(build *sourcegraph.Build) {
+	if build.BID == 0 {
+		s.nextBID++
+		build.BID = s.nextBID
+	} else if build.BID > s.nextBID {
+		s.nextBID = build.BID
+	}
+	if build.CreatedAt.IsZero() {
+		build.CreatedAt = time.Now()
+	}
+	s.builds = append(s.builds, build)
+}
+
+// AddBuildTask adds a build task to the server. If task.TaskID is 0,
+// a new TaskID is assigned to it. It returns task.
+func (s *Server) AddBuildTask(task *sourcegraph.BuildTask) *sourcegraph.BuildTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addBuildTask(task)
+	return task
+}
+
+func (s *Server) addBuildTask(task *sourcegraph.BuildTask) {
+	if task.TaskID == 0 {
+		s.nextTask++
+		task.TaskID = s.nextTask
+	} else if task.TaskID > s.nextTask {
+		s.nextTask = task.TaskID
+	}
+	if !task.CreatedAt.Valid {
+		task.CreatedAt = db_common.NullTime{Time: time.Now(), Valid: true}
+	}
+	s.tasks = append(s.tasks, task)
+}
+
+// AppendBuildLog appends lines to a build's log.
+func (s *Server) AppendBuildLog(build sourcegraph.BuildSpec, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[build.IDString()] = append(s.logs[build.IDString()], lines...)
+}
+
+// AppendTaskLog appends lines to a build task's log.
+func (s *Server) AppendTaskLog(task sourcegraph.TaskSpec, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[task.IDString()] = append(s.logs[task.IDString()], lines...)
+}
+
+// getBuild returns the build specified by spec. The caller must hold
+// s.mu.
+func (s *Server) getBuild(spec sourcegraph.BuildSpec) (*sourcegraph.Build, error) {
+	for _, build := range s.builds {
+		if build.BID == spec.BID {
+			return build, nil
+		}
+	}
+	return nil, sourcegraph.ErrBuildNotFound
+}
+
+// getTask returns the build task specified by spec. The caller must
+// hold s.mu.
+func (s *Server) getTask(spec sourcegraph.TaskSpec) (*sourcegraph.BuildTask, error) {
+	for _, task := range s.tasks {
+		if task.BID == spec.BID && task.TaskID == spec.TaskID {
+			return task, nil
+		}
+	}
+	return nil, httpapi.ErrNotFound
+}
+
+func (s *Server) serveBuilds(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.BuildListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var builds []*sourcegraph.Build
+	for _, b := range s.builds {
+		if opt.Queued && !(b.Queue && !b.StartedAt.Valid) {
+			continue
+		}
+		if opt.Active && !(b.StartedAt.Valid && !b.EndedAt.Valid) {
+			continue
+		}
+		if opt.Ended && !b.EndedAt.Valid {
+			continue
+		}
+		if opt.Succeeded && !b.Success {
+			continue
+		}
+		if opt.Failed && !b.Failure {
+			continue
+		}
+		if opt.Purged && !b.Purged {
+			continue
+		}
+		if opt.Repo != "" && (b.RepoURI == nil || *b.RepoURI != opt.Repo) {
+			continue
+		}
+		if opt.CommitID != "" && b.CommitID != opt.CommitID {
+			continue
+		}
+		builds = append(builds, b)
+	}
+	return writeJSON(w, paginate(w, builds, opt.ListOptions))
+}
+
+func (s *Server) serveBuild(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	build, err := s.getBuild(spec)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, build)
+}
+
+func (s *Server) serveBuildUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var info sourcegraph.BuildUpdate
+	if err := httpapi.ReadJSON(r, &info); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.getBuild(spec)
+	if err != nil {
+		return err
+	}
+	if info.StartedAt != nil {
+		b.StartedAt = db_common.NullTime{Time: *info.StartedAt, Valid: true}
+	}
+	if info.EndedAt != nil {
+		b.EndedAt = db_common.NullTime{Time: *info.EndedAt, Valid: true}
+	}
+	if info.HeartbeatAt != nil {
+		b.HeartbeatAt = db_common.NullTime{Time: *info.HeartbeatAt, Valid: true}
+	}
+	if info.Host != nil {
+		b.Host = *info.Host
+	}
+	if info.Success != nil {
+		b.Success = *info.Success
+	}
+	if info.Purged != nil {
+		b.Purged = *info.Purged
+	}
+	if info.Failure != nil {
+		b.Failure = *info.Failure
+	}
+	if info.Killed != nil {
+		b.Killed = *info.Killed
+	}
+	if info.Priority != nil {
+		b.Priority = *info.Priority
+	}
+	return writeJSON(w, b)
+}
+
+// serveBuildDequeueNext starts and returns the queued build with the
+// highest priority (and then the lowest BID).
+func (s *Server) serveBuildDequeueNext(w http.ResponseWriter, r *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var queued []*sourcegraph.Build
+	for _, b := range s.builds {
+		if b.Queue && !b.StartedAt.Valid {
+			queued = append(queued, b)
+		}
+	}
+	if len(queued) == 0 {
+		return httpapi.ErrNotFound
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].BID < queued[j].BID
+	})
+	b := queued[0]
+	b.StartedAt = db_common.NullTime{Time: time.Now(), Valid: true}
+	return writeJSON(w, b)
+}
+
+func (s *Server) serveRepoBuildsCreate(w http.ResponseWriter, r *http.Request) error {
+	repoRev, err := httpapi.RepoRevSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildCreateOptions
+	if err := httpapi.ReadJSON(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, err := s.getRepo(repoRev.RepoSpec)
+	if err != nil {
+		return err
+	}
+	commitID := repoRev.CommitID
+	if commitID == "" {
+		commitID = repoRev.Rev
+	}
+
+	if !opt.Force {
+		for _, b := range s.builds {
+			if b.Repo == repo.RID && b.CommitID == commitID && b.BuildConfig == opt.BuildConfig {
+				b.BuildMeta = opt.BuildMeta
+				return writeJSON(w, b)
+			}
+		}
+	}
+
+	uri := repo.URI
+	b := &sourcegraph.Build{
+		Repo:        repo.RID,
+		RepoURI:     &uri,
+		CommitID:    commitID,
+		BuildConfig: opt.BuildConfig,
+		BuildMeta:   opt.BuildMeta,
+	}
+	s.addBuild(b)
+	return writeJSON(w, b)
+}
+
+func (s *Server) serveBuildTasks(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.BuildTaskListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getBuild(spec); err != nil {
+		return err
+	}
+	var tasks []*sourcegraph.BuildTask
+	for _, task := range s.tasks {
+		if task.BID == spec.BID {
+			tasks = append(tasks, task)
+		}
+	}
+	return writeJSON(w, paginate(w, tasks, opt.ListOptions))
+}
+
+func (s *Server) serveBuildTasksCreate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var tasks []*sourcegraph.BuildTask
+	if err := httpapi.ReadJSON(r, &tasks); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getBuild(spec); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.BID = spec.BID
+		task.TaskID = 0
+		s.addBuildTask(task)
+	}
+	return writeJSON(w, tasks)
+}
+
+func (s *Server) serveBuildTaskUpdate(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.TaskSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var info sourcegraph.TaskUpdate
+	if err := httpapi.ReadJSON(r, &info); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, err := s.getTask(spec)
+	if err != nil {
+		return err
+	}
+	if info.StartedAt != nil {
+		task.StartedAt = db_common.NullTime{Time: *info.StartedAt, Valid: true}
+	}
+	if info.EndedAt != nil {
+		task.EndedAt = db_common.NullTime{Time: *info.EndedAt, Valid: true}
+	}
+	if info.Success != nil {
+		task.Success = *info.Success
+	}
+	if info.Failure != nil {
+		task.Failure = *info.Failure
+	}
+	if info.Killed != nil {
+		task.Killed = *info.Killed
+	}
+	return writeJSON(w, task)
+}
+
+func (s *Server) serveBuildLog(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.BuildSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getBuild(spec); err != nil {
+		return err
+	}
+	return s.writeLog(w, r, spec.IDString())
+}
+
+func (s *Server) serveBuildTaskLog(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.TaskSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.getTask(spec); err != nil {
+		return err
+	}
+	return s.writeLog(w, r, spec.IDString())
+}
+
+// writeLog writes the log entries after the request's MinID. Log
+// entry IDs are the 1-based line numbers of the entries. The caller
+// must hold s.mu.
+func (s *Server) writeLog(w http.ResponseWriter, r *http.Request, id string) error {
+	var opt sourcegraph.BuildGetLogOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	lines := s.logs[id]
+	minID := 0
+	if opt.MinID != "" {
+		var err error
+		minID, err = strconv.Atoi(opt.MinID)
+		if err != nil {
+			return &httpapi.BadRequestError{Err: err}
+		}
+	}
+	if minID > len(lines) {
+		minID = len(lines)
+	}
+	return writeJSON(w, &sourcegraph.LogEntries{
+		MaxID:   strconv.Itoa(len(lines)),
+		Entries: append([]string{}, lines[minID:]...),
+	})
+}
@@ -0,0 +1,74 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddDef adds a def to the server. It returns def.
+func (s *Server) AddDef(def *sourcegraph.Def) *sourcegraph.Def {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defs = append(s.defs, def)
+	return def
+}
+
+func (s *Server) serveDefs(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.DefListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+	var repos []string
+	for _, repoRev := range opt.RepoRevs {
+		repo, _ := sourcegraph.ParseRepoAndCommitID(repoRev)
+		repos = append(repos, repo)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var defs []*sourcegraph.Def
+	for _, def := range s.defs {
+		switch {
+		case opt.Name != "" && def.Name != opt.Name:
+		case opt.Query != "" && !strings.Contains(strings.ToLower(def.Name), strings.ToLower(opt.Query)):
+		case len(repos) > 0 && !contains(repos, def.Repo):
+		case opt.UnitType != "" && def.UnitType != opt.UnitType:
+		case opt.Unit != "" && def.Unit != opt.Unit:
+		case opt.Path != "" && def.Path != opt.Path:
+		case opt.File != "" && def.File != path.Clean(opt.File):
+		case opt.FilePathPrefix != "" && !strings.HasPrefix(def.File, path.Clean(opt.FilePathPrefix)):
+		case len(opt.Kinds) > 0 && !contains(opt.Kinds, def.Kind):
+		case opt.Exported && !def.Exported:
+		case opt.Nonlocal && def.Local:
+		case !opt.IncludeTest && def.Test:
+		default:
+			defs = append(defs, def)
+		}
+	}
+	return writeJSON(w, paginate(w, defs, opt.ListOptions))
+}
+
+func (s *Server) serveDef(w http.ResponseWriter, r *http.Request) error {
+	vars := mux.Vars(r)
+	repoRev, err := httpapi.RepoRevSpecFromVars(vars)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, def := range s.defs {
+		if def.Repo == repoRev.URI && def.UnitType == vars["UnitType"] && def.Unit == vars["Unit"] && def.Path == vars["Path"] {
+			if repoRev.CommitID != "" && def.CommitID != "" && def.CommitID != repoRev.CommitID {
+				continue
+			}
+			return writeJSON(w, def)
+		}
+	}
+	return httpapi.ErrNotFound
+}
@@ -0,0 +1,113 @@
+package sourcegraphtest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/srclib/graph"
+)
+
+// TestDefFilters checks that DefListOptions.DefFilters selects the
+// same defs, in the same order, as the API does.
+//
+// The fake API can't verify everything that DefFilters does: it
+// ignores Sort and Direction (returning defs in the order they were
+// added), Fuzzy (matching Query as a substring), and the commit IDs
+// of RepoRevs. Those are only covered by the sourcegraph package's
+// TestDefListOptions_DefFilters, so they aren't compared here.
+func TestDefFilters(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	commitID := "0123456789012345678901234567890123456789"
+	commitID2 := "9876543210987654321098765432109876543210"
+	def := func(repo, commitID, unitType, unit, path, name, kind, file string, exported, local, test bool) *graph.Def {
+		return &graph.Def{
+			DefKey:   graph.DefKey{Repo: repo, CommitID: commitID, UnitType: unitType, Unit: unit, Path: path},
+			Name:     name,
+			Kind:     kind,
+			File:     file,
+			Exported: exported,
+			Local:    local,
+			Test:     test,
+		}
+	}
+	fixtures := []*graph.Def{
+		def("r.com/a", commitID, "GoPackage", "a", "T/a", "NewClient", "func", "a/a.go", true, false, false),
+		def("r.com/a", commitID, "GoPackage", "a", "T/b", "Client", "type", "a/a.go", false, false, false),
+		def("r.com/a", commitID, "GoPackage", "a/b", "T/c", "close", "var", "a/b/c.go", false, true, false),
+		def("r.com/a", commitID, "GoPackage", "ab", "T/d", "Dial", "func", "ab/d.go", true, false, false),
+		def("r.com/a", commitID, "GoPackage", "a", "T/e", "TestClient", "func", "a/a_test.go", true, false, true),
+		def("r.com/a", commitID2, "GoPackage", "a", "T/a", "NewClient", "func", "a/a.go", true, false, false),
+		def("r.com/b", commitID, "PipPackage", "a", "T/f", "Conn", "type", "a/f.py", true, false, false),
+		def("r.com/b", commitID, "PipPackage", "b", "T/a", "client", "func", "b/a.py", false, false, false),
+	}
+	for _, d := range fixtures {
+		s.AddDef(&sourcegraph.Def{Def: *d})
+	}
+
+	tests := []sourcegraph.DefListOptions{
+		{},
+		{Name: "Client"},
+		{Query: "CLIENT"},
+		{Query: "client", Sort: "name", Direction: "desc"}, // Sort is ignored for queries
+		{RepoRevs: []string{"r.com/b"}},
+		{RepoRevs: []string{"r.com/a"}},
+		{RepoRevs: []string{"r.com/a", "r.com/b"}},
+		{UnitType: "GoPackage"},
+		{UnitType: "PipPackage", Unit: "a"},
+		{Unit: "a"},
+		{Path: "T/a"},
+		{File: "a/a.go"},
+		{FilePathPrefix: "a"},
+		{FilePathPrefix: "a/"},
+		{FilePathPrefix: "a/b"},
+		{FilePathPrefix: "a/b/"},
+		{FilePathPrefix: "ab"},
+		{FilePathPrefix: "./a/b"},
+		{Kinds: []string{"func", "var"}},
+		{Exported: true},
+		{Nonlocal: true},
+		{IncludeTest: true},
+		{Exported: true, IncludeTest: true, Kinds: []string{"func"}, FilePathPrefix: "a"},
+	}
+	for _, opt := range tests {
+		opt.PerPage = len(fixtures)
+		apiDefs, _, err := s.Client.Defs.List(context.Background(), &opt)
+		if err != nil {
+			t.Errorf("%+v: List: %s", opt, err)
+			continue
+		}
+		var want []graph.DefKey
+		for _, d := range apiDefs {
+			want = append(want, d.DefKey)
+		}
+
+		if err := opt.Validate(); err != nil {
+			t.Errorf("%+v: Validate: %s", opt, err)
+			continue
+		}
+		fs := opt.DefFilters()
+		var defs []*graph.Def
+	defs:
+		for _, d := range fixtures {
+			for _, f := range fs {
+				if !f.SelectDef(d) {
+					continue defs
+				}
+			}
+			defs = append(defs, d)
+		}
+		sourcegraph.SortDefs(defs, fs)
+		var got []graph.DefKey
+		for _, d := range defs {
+			got = append(got, d.DefKey)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%+v: got defs %v, want (from the API) %v", opt, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddIssue adds an issue to the repository. The issue's Number must
+// be set. It returns issue.
+func (s *Server) AddIssue(repo sourcegraph.RepoSpec, issue *sourcegraph.Issue) *sourcegraph.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issues[sourcegraph.IssueSpec{Repo: sourcegraph.RepoSpec{URI: repo.URI}, Number: *issue.Number}] = issue
+	return issue
+}
+
+func (s *Server) serveRepoIssues(w http.ResponseWriter, r *http.Request) error {
+	repo, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.IssueListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var issues []*sourcegraph.Issue
+	for spec, issue := range s.issues {
+		if spec.Repo.URI == repo.URI && matchesState(opt.State, issue.State) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return *issues[i].Number > *issues[j].Number })
+	return writeJSON(w, paginate(w, issues, opt.ListOptions))
+}
+
+func (s *Server) serveRepoIssue(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.IssueSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, present := s.issues[spec]
+	if !present {
+		return httpapi.ErrNotFound
+	}
+	return writeJSON(w, issue)
+}
+
+// matchesState returns whether an issue or pull request whose state
+// is state matches the state filter (which is "open" if empty, like
+// the GitHub API).
+func matchesState(filter string, state *string) bool {
+	if filter == "" {
+		filter = "open"
+	}
+	if filter == "all" {
+		return true
+	}
+	return state != nil && *state == filter
+}
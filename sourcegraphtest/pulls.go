@@ -0,0 +1,57 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddPullRequest adds a pull request to the (base) repository. The
+// pull request's Number must be set. It returns pull.
+func (s *Server) AddPullRequest(repo sourcegraph.RepoSpec, pull *sourcegraph.PullRequest) *sourcegraph.PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pulls[sourcegraph.PullRequestSpec{Repo: sourcegraph.RepoSpec{URI: repo.URI}, Number: *pull.Number}] = pull
+	return pull
+}
+
+func (s *Server) serveRepoPullRequests(w http.ResponseWriter, r *http.Request) error {
+	repo, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	var opt sourcegraph.PullRequestListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pulls []*sourcegraph.PullRequest
+	for spec, pull := range s.pulls {
+		if spec.Repo.URI == repo.URI && matchesState(opt.State, pull.State) {
+			pulls = append(pulls, pull)
+		}
+	}
+	sort.Slice(pulls, func(i, j int) bool { return *pulls[i].Number > *pulls[j].Number })
+	return writeJSON(w, paginate(w, pulls, opt.ListOptions))
+}
+
+func (s *Server) serveRepoPullRequest(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.PullRequestSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+	spec.Repo = sourcegraph.RepoSpec{URI: spec.Repo.URI}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pull, present := s.pulls[spec]
+	if !present {
+		return httpapi.ErrNotFound
+	}
+	return writeJSON(w, pull)
+}
@@ -0,0 +1,133 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddRepo adds a repository to the server. If repo.RID is 0, a new
+// RID is assigned to it. It returns repo.
+func (s *Server) AddRepo(repo *sourcegraph.Repo) *sourcegraph.Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addRepo(repo)
+	return repo
+}
+
+func (s *Server) addRepo(repo *sourcegraph.Repo) {
+	if repo.RID == 0 {
+		repo.RID = len(s.repos) + 1
+	}
+	if repo.Name == "" {
+		repo.Name = path.Base(repo.URI)
+	}
+	s.repos = append(s.repos, repo)
+}
+
+// RenameRepo records that the repository oldURI was renamed to
+// newURI. Subsequent requests for oldURI fail with
+// sourcegraph.ErrRenamed.
+func (s *Server) RenameRepo(oldURI, newURI string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repoAliases[oldURI] = newURI
+	for _, repo := range s.repos {
+		if repo.URI == oldURI {
+			repo.URI = newURI
+			repo.Name = path.Base(newURI)
+		}
+	}
+}
+
+// getRepo returns the repository specified by spec. The caller must
+// hold s.mu.
+func (s *Server) getRepo(spec sourcegraph.RepoSpec) (*sourcegraph.Repo, error) {
+	if newURI, renamed := s.repoAliases[spec.URI]; renamed {
+		return nil, sourcegraph.ErrRenamed{OldURI: spec.URI, NewURI: newURI}
+	}
+	for _, repo := range s.repos {
+		if (spec.RID != 0 && repo.RID == spec.RID) || (spec.URI != "" && repo.URI == spec.URI) {
+			return repo, nil
+		}
+	}
+	return nil, sourcegraph.ErrNotExist
+}
+
+func (s *Server) serveRepos(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.RepoListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var repos []*sourcegraph.Repo
+	for _, repo := range s.repos {
+		if opt.Name != "" && repo.Name != opt.Name {
+			continue
+		}
+		if opt.Query != "" && !strings.Contains(repo.URI, opt.Query) {
+			continue
+		}
+		if len(opt.URIs) > 0 && !contains(opt.URIs, repo.URI) {
+			continue
+		}
+		repos = append(repos, repo)
+	}
+	return writeJSON(w, paginate(w, repos, opt.ListOptions))
+}
+
+func (s *Server) serveReposCreate(w http.ResponseWriter, r *http.Request) error {
+	var newRepo sourcegraph.NewRepoSpec
+	if err := httpapi.ReadJSON(r, &newRepo); err != nil {
+		return err
+	}
+	cloneURL, err := url.Parse(newRepo.CloneURLStr)
+	if err != nil || cloneURL.Host == "" {
+		return &httpapi.BadRequestError{Err: sourcegraph.ErrNonStandardURI}
+	}
+	uri := cloneURL.Host + strings.TrimSuffix(cloneURL.Path, ".git")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, err := s.getRepo(sourcegraph.RepoSpec{URI: uri})
+	if err == sourcegraph.ErrNotExist {
+		repo = &sourcegraph.Repo{URI: uri, VCS: newRepo.Type, HTTPCloneURL: newRepo.CloneURLStr}
+		s.addRepo(repo)
+	} else if err != nil {
+		return err
+	}
+	return writeJSON(w, repo)
+}
+
+// serveRepo serves both the Repo and ReposGetOrCreate routes. The
+// fake server never implicitly creates repositories.
+func (s *Server) serveRepo(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.RepoSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, err := s.getRepo(spec)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, repo)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
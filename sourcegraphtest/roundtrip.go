@@ -0,0 +1,386 @@
+package sourcegraphtest
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// A SpecRoundTrip describes how a spec type (such as
+// sourcegraph.DefSpec) is encoded in and decoded from the route
+// variables of API routes. Its Check and CheckRandom methods verify
+// that specs survive the trip through a URL built by
+// sourcegraph.Router and matched by it again.
+type SpecRoundTrip struct {
+	// Name is the name of the spec type, such as "DefSpec".
+	Name string
+
+	// Routes are the names of the API routes whose route variables
+	// encode the spec.
+	Routes []string
+
+	// Rand returns a random valid spec.
+	Rand func(r *rand.Rand) interface{}
+
+	// RouteVars returns the route variables that encode spec.
+	RouteVars func(spec interface{}) map[string]string
+
+	// Unmarshal decodes a spec from the route variables of a matched
+	// route.
+	Unmarshal func(vars map[string]string) (interface{}, error)
+}
+
+// Check builds the URL of each of c's routes for spec, matches the
+// URL against sourcegraph.Router (as a server would), and decodes the
+// matched route variables. It returns an error if a URL can't be
+// built, matches a different route, or decodes to a spec that is not
+// equal to spec.
+func (c SpecRoundTrip) Check(spec interface{}) error {
+	for _, route := range c.Routes {
+		if err := c.check(route, spec); err != nil {
+			return fmt.Errorf("%s %+v: route %s: %s", c.Name, spec, route, err)
+		}
+	}
+	return nil
+}
+
+func (c SpecRoundTrip) check(route string, spec interface{}) error {
+	u, err := sourcegraph.URL(route, c.RouteVars(spec), nil)
+	if err != nil {
+		return fmt.Errorf("building URL: %s", err)
+	}
+	vars, err := matchRoute(route, u.String())
+	if err != nil {
+		return err
+	}
+	got, err := c.Unmarshal(vars)
+	if err != nil {
+		return fmt.Errorf("URL %s: unmarshaling route vars %v: %s", u, vars, err)
+	}
+	if !reflect.DeepEqual(got, spec) {
+		return fmt.Errorf("URL %s: got %+v back (route vars %v)", u, got, vars)
+	}
+	return nil
+}
+
+// CheckRandom calls Check on n random specs generated by c.Rand. It
+// returns the first error.
+func (c SpecRoundTrip) CheckRandom(r *rand.Rand, n int) error {
+	for i := 0; i < n; i++ {
+		if err := c.Check(c.Rand(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routeMethods are the HTTP methods that API routes are registered
+// for.
+var routeMethods = []string{"GET", "PUT", "POST", "PATCH", "DELETE"}
+
+// matchRoute matches the URL urlStr against sourcegraph.Router and
+// returns the variables of the route named route. Routes with the
+// same path are distinguished by HTTP method, so it tries each method
+// in turn.
+func matchRoute(route, urlStr string) (map[string]string, error) {
+	var others []string
+	for _, method := range routeMethods {
+		req, err := http.NewRequest(method, "http://example.com"+urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		var m mux.RouteMatch
+		if !sourcegraph.Router.Match(req, &m) {
+			continue
+		}
+		if name := m.Route.GetName(); name != route {
+			others = append(others, method+" "+name)
+			continue
+		}
+		return m.Vars, nil
+	}
+	if len(others) == 0 {
+		return nil, fmt.Errorf("URL %s matches no route", urlStr)
+	}
+	return nil, fmt.Errorf("URL %s matches %s", urlStr, strings.Join(others, ", "))
+}
+
+// SpecRoundTrips describes every spec type that is encoded in route
+// variables. Its random specs include awkward names and paths (with
+// dots, "@", "?", "===", ".def", etc.) that have broken route
+// encoding before.
+var SpecRoundTrips = []SpecRoundTrip{
+	{
+		Name: "RepoSpec",
+		Routes: []string{
+			router.Repo, router.ReposGetOrCreate, router.RepoClients, router.RepoDependents,
+			router.RepoRefreshProfile, router.RepoRefreshVCSData, router.RepoSettings,
+			router.RepoSettingsUpdate, router.RepoCommits, router.RepoBranches, router.RepoTags,
+			router.RepoBadges, router.RepoCounters, router.RepoPullRequests, router.RepoIssues,
+			router.DeltasIncoming,
+		},
+		Rand:      func(r *rand.Rand) interface{} { return randRepoSpec(r) },
+		RouteVars: func(spec interface{}) map[string]string { return spec.(sourcegraph.RepoSpec).RouteVars() },
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalRepoSpec(vars) },
+	},
+	{
+		Name: "RepoRevSpec",
+		Routes: []string{
+			router.RepoStats, router.RepoComputeStats, router.RepoCombinedStatus,
+			router.RepoStatusCreate, router.RepoAuthors, router.RepoReadme, router.RepoBuild,
+			router.RepoBuildsCreate, router.RepoDependencies,
+		},
+		Rand:      func(r *rand.Rand) interface{} { return randRepoRevSpec(r, false) },
+		RouteVars: func(spec interface{}) map[string]string { return spec.(sourcegraph.RepoRevSpec).RouteVars() },
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalRepoRevSpec(vars) },
+	},
+	{
+		Name: "DefSpec",
+		Routes: []string{
+			router.Def, router.DefRefs, router.DefExamples, router.DefAuthors,
+			router.DefClients, router.DefDependents, router.DefVersions, router.DefSourcebox,
+		},
+		Rand: func(r *rand.Rand) interface{} {
+			s := sourcegraph.DefSpec{
+				Repo:     randRepoURI(r),
+				UnitType: randUnitType(r),
+				Unit:     randPath(r, false),
+				Path:     randPath(r, false),
+			}
+			if r.Intn(2) == 0 {
+				s.CommitID = randCommitID(r)
+			}
+			return s
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			s := spec.(sourcegraph.DefSpec)
+			return s.RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalDefSpec(vars) },
+	},
+	{
+		Name: "DeltaSpec",
+		Routes: []string{
+			router.Delta, router.DeltaUnits, router.DeltaDefs, router.DeltaDependencies,
+			router.DeltaFiles, router.DeltaAffectedAuthors, router.DeltaAffectedClients,
+			router.DeltaAffectedDependents, router.DeltaReviewers,
+		},
+		Rand: func(r *rand.Rand) interface{} {
+			s := sourcegraph.DeltaSpec{Base: randRepoRevSpec(r, true), Head: randRepoRevSpec(r, true)}
+			if r.Intn(2) == 0 {
+				s.Head.RepoSpec = s.Base.RepoSpec
+			}
+			return s
+		},
+		RouteVars: func(spec interface{}) map[string]string { return spec.(sourcegraph.DeltaSpec).RouteVars() },
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalDeltaSpec(vars) },
+	},
+	{
+		Name:   "UnitSpec",
+		Routes: []string{router.Unit},
+		Rand: func(r *rand.Rand) interface{} {
+			return sourcegraph.UnitSpec{
+				RepoRevSpec: randRepoRevSpec(r, false),
+				UnitType:    randUnitType(r),
+				Unit:        randPath(r, false),
+			}
+		},
+		RouteVars: func(spec interface{}) map[string]string { return spec.(sourcegraph.UnitSpec).RouteVars() },
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalUnitSpec(vars) },
+	},
+	{
+		Name:   "TreeEntrySpec",
+		Routes: []string{router.RepoTreeEntry, router.RepoTreeSourcebox},
+		Rand: func(r *rand.Rand) interface{} {
+			return sourcegraph.TreeEntrySpec{RepoRev: randRepoRevSpec(r, false), Path: randPath(r, true)}
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			s := spec.(sourcegraph.TreeEntrySpec)
+			return s.RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalTreeEntrySpec(vars) },
+	},
+	{
+		Name:   "IssueSpec",
+		Routes: []string{router.RepoIssue, router.RepoIssueComments, router.RepoIssueCommentsCreate},
+		Rand: func(r *rand.Rand) interface{} {
+			return sourcegraph.IssueSpec{Repo: randRepoSpec(r), Number: 1 + r.Intn(10000)}
+		},
+		RouteVars: func(spec interface{}) map[string]string { return spec.(sourcegraph.IssueSpec).RouteVars() },
+		Unmarshal: func(vars map[string]string) (interface{}, error) { return sourcegraph.UnmarshalIssueSpec(vars) },
+	},
+	{
+		Name:   "PullRequestCommentSpec",
+		Routes: []string{router.RepoPullRequestCommentsEdit, router.RepoPullRequestCommentsDelete},
+		Rand: func(r *rand.Rand) interface{} {
+			return sourcegraph.PullRequestCommentSpec{
+				Pull:    sourcegraph.PullRequestSpec{Repo: randRepoSpec(r), Number: 1 + r.Intn(10000)},
+				Comment: 1 + r.Intn(1000000),
+			}
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			return spec.(sourcegraph.PullRequestCommentSpec).RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) {
+			return sourcegraph.UnmarshalPullRequestCommentSpec(vars)
+		},
+	},
+	{
+		Name:   "PersonSpec",
+		Routes: []string{router.Person},
+		Rand: func(r *rand.Rand) interface{} {
+			switch r.Intn(3) {
+			case 0:
+				return sourcegraph.PersonSpec{Email: randLogin(r) + "@" + randHost(r)}
+			case 1:
+				return sourcegraph.PersonSpec{Login: randLogin(r)}
+			default:
+				return sourcegraph.PersonSpec{UID: randID(r)}
+			}
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			s := spec.(sourcegraph.PersonSpec)
+			return s.RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) {
+			return sourcegraph.ParsePersonSpec(vars["PersonSpec"])
+		},
+	},
+	{
+		Name: "UserSpec",
+		Routes: []string{
+			router.User, router.UserOrgs, router.UserClients, router.UserAuthors,
+			router.UserEmails, router.UserRepoContributions, router.UserRepoDependencies,
+			router.UserRepoDependents, router.UserRefreshProfile, router.UserComputeStats,
+			router.UserSettings, router.UserSettingsUpdate,
+		},
+		Rand: func(r *rand.Rand) interface{} {
+			if r.Intn(2) == 0 {
+				return sourcegraph.UserSpec{Login: randLogin(r)}
+			}
+			return sourcegraph.UserSpec{UID: randID(r)}
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			s := spec.(sourcegraph.UserSpec)
+			return s.RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) {
+			return sourcegraph.ParseUserSpec(vars["UserSpec"])
+		},
+	},
+	{
+		Name:   "OrgSpec",
+		Routes: []string{router.Org, router.OrgSettings, router.OrgSettingsUpdate, router.OrgMembers},
+		Rand: func(r *rand.Rand) interface{} {
+			if r.Intn(2) == 0 {
+				return sourcegraph.OrgSpec{Org: randLogin(r)}
+			}
+			return sourcegraph.OrgSpec{UID: randID(r)}
+		},
+		RouteVars: func(spec interface{}) map[string]string {
+			s := spec.(sourcegraph.OrgSpec)
+			return s.RouteVars()
+		},
+		Unmarshal: func(vars map[string]string) (interface{}, error) {
+			return sourcegraph.ParseOrgSpec(vars["OrgSpec"])
+		},
+	},
+}
+
+// The random specs are built from these atoms. Each list only
+// contains atoms that are valid anywhere in a name of its kind (for
+// example, git forbids ".." and "?" in revs, and repo URIs can't
+// contain "@").
+var (
+	hostAtoms     = []string{"github.com", "sourcegraph.com", "example.org", "a-b.co"}
+	repoAtoms     = []string{"a", "Foo", "x_y", "b-1", "v1.2", "a.def", "go.tools"}
+	revAtoms      = []string{"master", "v1.2", "feature", "a-b", "x_y", "@", "a@b", "a.def"}
+	unitTypeAtoms = []string{"GoPackage", "JavaArtifact", "CommonJSPackage", "pip.package"}
+	loginAtoms    = []string{"alice", "Bob", "x_y", "b-1", "a.b", "x?y", "===", "a b"}
+	pathAtoms     = []string{"a", "Foo", "x_y", "b-1", "a.go", "@", "a@b", "?", "x?y", "===", ".def", "a.def", "%3F", "a b", "...", ".sourcebox.json"}
+)
+
+func randAtoms(r *rand.Rand, atoms []string, max int) string {
+	n := 1 + r.Intn(max)
+	s := make([]string, n)
+	for i := range s {
+		s[i] = atoms[r.Intn(len(atoms))]
+	}
+	return strings.Join(s, "")
+}
+
+func randHost(r *rand.Rand) string { return hostAtoms[r.Intn(len(hostAtoms))] }
+
+// randRepoURI returns a random repo URI with 1 or 2 path components
+// after the host. (Longer GitHub URIs can collide with the
+// RedirectOldRepoBadgesAndCounters route.)
+func randRepoURI(r *rand.Rand) string {
+	uri := randHost(r)
+	for i := 1 + r.Intn(2); i > 0; i-- {
+		uri += "/" + randAtoms(r, repoAtoms, 3)
+	}
+	return uri
+}
+
+func randRepoSpec(r *rand.Rand) sourcegraph.RepoSpec {
+	if r.Intn(4) == 0 {
+		return sourcegraph.RepoSpec{RID: randID(r)}
+	}
+	return sourcegraph.RepoSpec{URI: randRepoURI(r)}
+}
+
+// randRepoRevSpec returns a random RepoRevSpec. If needRev is false,
+// its Rev may be empty.
+func randRepoRevSpec(r *rand.Rand, needRev bool) sourcegraph.RepoRevSpec {
+	s := sourcegraph.RepoRevSpec{RepoSpec: randRepoSpec(r)}
+	if !needRev && r.Intn(3) == 0 {
+		return s
+	}
+	s.Rev = randAtoms(r, revAtoms, 3)
+	if r.Intn(2) == 0 {
+		s.Rev += "/" + randAtoms(r, revAtoms, 2)
+	}
+	if r.Intn(2) == 0 {
+		s.CommitID = randCommitID(r)
+	}
+	return s
+}
+
+func randCommitID(r *rand.Rand) string {
+	const hex = "0123456789abcdef"
+	b := make([]byte, 40)
+	for i := range b {
+		b[i] = hex[r.Intn(len(hex))]
+	}
+	return string(b)
+}
+
+func randUnitType(r *rand.Rand) string { return unitTypeAtoms[r.Intn(len(unitTypeAtoms))] }
+
+// randPath returns "." or a random clean slash-separated path. If
+// leadingDots is false, no path component starts with ".".
+func randPath(r *rand.Rand, leadingDots bool) string {
+	if r.Intn(5) == 0 {
+		return "."
+	}
+	c := make([]string, 1+r.Intn(3))
+	for i := range c {
+		c[i] = randAtoms(r, pathAtoms, 3)
+		if !leadingDots && strings.HasPrefix(c[i], ".") {
+			c[i] = "x" + c[i]
+		}
+	}
+	return strings.Join(c, "/")
+}
+
+// randLogin returns a random user or org login, which never contains
+// "@" or "/" and never starts with "$".
+func randLogin(r *rand.Rand) string { return randAtoms(r, loginAtoms, 3) }
+
+func randID(r *rand.Rand) int { return 1 + r.Intn(1000000) }
@@ -0,0 +1,16 @@
+package sourcegraphtest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSpecRoundTrips(t *testing.T) {
+	for _, c := range SpecRoundTrips {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := c.CheckRandom(rand.New(rand.NewSource(1)), 500); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package sourcegraphtest provides an in-process fake Sourcegraph API
+// server for testing code that uses the sourcegraph client package.
+//
+// Unlike the mock services returned by sourcegraph.NewMockClient, the
+// fake server exercises the whole HTTP path: requests are built from
+// the API router's routes, sent over HTTP, and their responses are
+// decoded and checked (by sourcegraph.CheckResponse) as usual. The
+// server's data (repos, builds, tasks, logs, defs, issues, pull
+// requests, and users) is held in memory.
+//
+// The package also provides a round-trip conformance harness for the
+// spec types that are encoded in API route variables (see
+// SpecRoundTrips).
+package sourcegraphtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// A Server is a fake Sourcegraph API server. Its exported methods
+// add data to it, and may be called at any time (including while the
+// server is handling requests).
+//
+// Only a subset of the API's routes are implemented. Requests to
+// other routes fail with HTTP 501 Not Implemented.
+type Server struct {
+	// Server is the underlying HTTP test server.
+	*httptest.Server
+
+	// Client is a client that is configured to talk to the server.
+	Client *sourcegraph.Client
+
+	mu sync.Mutex
+
+	repos       []*sourcegraph.Repo
+	repoAliases map[string]string // old URI -> new URI
+
+	builds   []*sourcegraph.Build
+	tasks    []*sourcegraph.BuildTask
+	logs     map[string][]string // build or task ID string -> log lines
+	nextBID  int64
+	nextTask int64
+
+	defs   []*sourcegraph.Def
+	issues map[sourcegraph.IssueSpec]*sourcegraph.Issue
+	pulls  map[sourcegraph.PullRequestSpec]*sourcegraph.PullRequest
+	users  []*sourcegraph.User
+}
+
+// NewServer starts and returns a new fake API server with no data.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		repoAliases: map[string]string{},
+		logs:        map[string][]string{},
+		issues:      map[sourcegraph.IssueSpec]*sourcegraph.Issue{},
+		pulls:       map[sourcegraph.PullRequestSpec]*sourcegraph.PullRequest{},
+	}
+
+	r := router.NewAPIRouter(nil)
+	for name, h := range map[string]httpapi.HandlerFunc{
+		router.Repos:            s.serveRepos,
+		router.ReposCreate:      s.serveReposCreate,
+		router.Repo:             s.serveRepo,
+		router.ReposGetOrCreate: s.serveRepo,
+
+		router.Builds:           s.serveBuilds,
+		router.Build:            s.serveBuild,
+		router.BuildUpdate:      s.serveBuildUpdate,
+		router.BuildDequeueNext: s.serveBuildDequeueNext,
+		router.RepoBuildsCreate: s.serveRepoBuildsCreate,
+		router.BuildTasks:       s.serveBuildTasks,
+		router.BuildTasksCreate: s.serveBuildTasksCreate,
+		router.BuildTaskUpdate:  s.serveBuildTaskUpdate,
+		router.BuildLog:         s.serveBuildLog,
+		router.BuildTaskLog:     s.serveBuildTaskLog,
+
+		router.Defs: s.serveDefs,
+		router.Def:  s.serveDef,
+
+		router.RepoIssues:       s.serveRepoIssues,
+		router.RepoIssue:        s.serveRepoIssue,
+		router.RepoPullRequests: s.serveRepoPullRequests,
+		router.RepoPullRequest:  s.serveRepoPullRequest,
+
+		router.Users: s.serveUsers,
+		router.User:  s.serveUser,
+	} {
+		r.Get(name).Handler(h)
+	}
+	s.Server = httptest.NewServer(httpapi.NotImplementedHandler{Router: r})
+
+	s.Client = sourcegraph.NewClient(nil)
+	s.Client.BaseURL, _ = url.Parse(s.Server.URL + "/")
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	w.Header().Set("content-type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(v)
+}
+
+// paginate returns the page of items specified by opt, and sets the
+// response's total count header.
+func paginate[T any](w http.ResponseWriter, items []T, opt sourcegraph.ListOptions) []T {
+	w.Header().Set("x-total-count", strconv.Itoa(len(items)))
+	start := opt.Offset()
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + opt.PerPageOrDefault()
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
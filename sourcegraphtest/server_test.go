@@ -0,0 +1,192 @@
+package sourcegraphtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-github/github"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+func TestServer_repos(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	want := s.AddRepo(&sourcegraph.Repo{URI: "r.com/x"})
+	s.AddRepo(&sourcegraph.Repo{URI: "r.com/y"})
+
+	repo, _, err := s.Client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("got repo %+v, want %+v", repo, want)
+	}
+
+	repos, resp, err := s.Client.Repos.List(ctx, &sourcegraph.RepoListOptions{ListOptions: sourcegraph.ListOptions{PerPage: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].URI != "r.com/x" {
+		t.Errorf("got repos %+v, want [r.com/x]", repos)
+	}
+	if n := resp.TotalCount(); n != 2 {
+		t.Errorf("got total count %d, want 2", n)
+	}
+
+	if _, _, err := s.Client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/z"}, nil); !sourcegraph.IsNotPresent(err) {
+		t.Errorf("got error %v, want IsNotPresent", err)
+	}
+}
+
+func TestServer_renamedRepo(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	s.AddRepo(&sourcegraph.Repo{URI: "r.com/x"})
+	s.RenameRepo("r.com/x", "r.com/y")
+
+	_, _, err := s.Client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	var renamed sourcegraph.ErrRenamed
+	if !errors.As(err, &renamed) || renamed.NewURI != "r.com/y" {
+		t.Fatalf("got error %v, want ErrRenamed to r.com/y", err)
+	}
+
+	s.Client.FollowRepoRenames = true
+	repo, _, err := s.Client.Repos.Get(ctx, sourcegraph.RepoSpec{URI: "r.com/x"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.URI != "r.com/y" {
+		t.Errorf("got repo URI %q, want %q", repo.URI, "r.com/y")
+	}
+}
+
+func TestServer_builds(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	s.AddRepo(&sourcegraph.Repo{URI: "r.com/x"})
+	repoRev := sourcegraph.RepoRevSpec{RepoSpec: sourcegraph.RepoSpec{URI: "r.com/x"}, Rev: "c", CommitID: "c"}
+	build, _, err := s.Client.Builds.Create(ctx, repoRev, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.CommitID != "c" {
+		t.Errorf("got CommitID %q, want %q", build.CommitID, "c")
+	}
+
+	dequeued, _, err := s.Client.Builds.DequeueNext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dequeued == nil || dequeued.BID != build.BID || !dequeued.StartedAt.Valid {
+		t.Fatalf("got dequeued build %+v, want started build %d", dequeued, build.BID)
+	}
+	if dequeued, _, err := s.Client.Builds.DequeueNext(ctx); err != nil || dequeued != nil {
+		t.Errorf("got dequeued build %+v and error %v, want nil and nil (empty queue)", dequeued, err)
+	}
+
+	tasks, _, err := s.Client.Builds.CreateTasks(ctx, build.Spec(), []*sourcegraph.BuildTask{{Op: "graph"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].TaskID == 0 || tasks[0].BID != build.BID {
+		t.Fatalf("got tasks %+v, want 1 task in build %d", tasks, build.BID)
+	}
+
+	success, now := true, time.Now()
+	task, _, err := s.Client.Builds.UpdateTask(ctx, tasks[0].Spec(), sourcegraph.TaskUpdate{EndedAt: &now, Success: &success})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.Success || !task.EndedAt.Valid {
+		t.Errorf("got task %+v, want ended successfully", task)
+	}
+
+	s.AppendTaskLog(tasks[0].Spec(), "a", "b")
+	entries, _, err := s.Client.Builds.GetTaskLog(ctx, tasks[0].Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sourcegraph.LogEntries{MaxID: "2", Entries: []string{"a", "b"}}); !reflect.DeepEqual(entries, want) {
+		t.Errorf("got log %+v, want %+v", entries, want)
+	}
+	s.AppendTaskLog(tasks[0].Spec(), "c")
+	entries, _, err = s.Client.Builds.GetTaskLog(ctx, tasks[0].Spec(), &sourcegraph.BuildGetLogOptions{MinID: entries.MaxID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sourcegraph.LogEntries{MaxID: "3", Entries: []string{"c"}}); !reflect.DeepEqual(entries, want) {
+		t.Errorf("got log %+v, want %+v", entries, want)
+	}
+
+	if _, _, err := s.Client.Builds.Get(ctx, sourcegraph.BuildSpec{BID: 123}, nil); !errors.Is(err, sourcegraph.ErrBuildNotFound) {
+		t.Errorf("got error %v, want ErrBuildNotFound", err)
+	}
+}
+
+func TestServer_issuesAndPulls(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	repo := sourcegraph.RepoSpec{URI: "r.com/x"}
+	s.AddIssue(repo, &sourcegraph.Issue{Issue: github.Issue{Number: github.Int(1), State: github.String("open")}})
+	s.AddIssue(repo, &sourcegraph.Issue{Issue: github.Issue{Number: github.Int(2), State: github.String("closed")}})
+	s.AddPullRequest(repo, &sourcegraph.PullRequest{PullRequest: github.PullRequest{Number: github.Int(3), State: github.String("open")}})
+
+	issues, _, err := s.Client.Issues.ListByRepo(ctx, repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || *issues[0].Number != 1 {
+		t.Errorf("got issues %+v, want only open issue #1", issues)
+	}
+
+	pull, _, err := s.Client.PullRequests.Get(ctx, sourcegraph.PullRequestSpec{Repo: repo, Number: 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *pull.Number != 3 {
+		t.Errorf("got pull #%d, want #3", *pull.Number)
+	}
+}
+
+func TestServer_users(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	want := s.AddUser(&sourcegraph.User{Login: "alice"})
+
+	user, _, err := s.Client.Users.Get(ctx, sourcegraph.UserSpec{UID: want.UID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Login != "alice" {
+		t.Errorf("got user %q, want %q", user.Login, "alice")
+	}
+
+	if _, _, err := s.Client.Users.Get(ctx, sourcegraph.UserSpec{Login: "bob"}, nil); !errors.Is(err, sourcegraph.ErrUserNotExist) {
+		t.Errorf("got error %v, want ErrUserNotExist", err)
+	}
+}
+
+func TestServer_notImplemented(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	_, _, err := s.Client.Markdown.Render(context.Background(), []byte("x"), sourcegraph.MarkdownOpt{})
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotImplemented) {
+		t.Errorf("got error %v, want HTTP %d for route %q", err, http.StatusNotImplemented, router.Markdown)
+	}
+}
@@ -0,0 +1,55 @@
+package sourcegraphtest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/mux"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/internal/httpapi"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// AddUser adds a user to the server. If user.UID is 0, a new UID is
+// assigned to it. It returns user.
+func (s *Server) AddUser(user *sourcegraph.User) *sourcegraph.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user.UID == 0 {
+		user.UID = len(s.users) + 1
+	}
+	s.users = append(s.users, user)
+	return user
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request) error {
+	var opt sourcegraph.UsersListOptions
+	if err := httpapi.DecodeOptions(r, &opt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []*sourcegraph.User
+	for _, user := range s.users {
+		if strings.HasPrefix(user.Login, opt.Query) {
+			users = append(users, user)
+		}
+	}
+	return writeJSON(w, paginate(w, users, opt.ListOptions))
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request) error {
+	spec, err := httpapi.UserSpecFromVars(mux.Vars(r))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if (spec.UID != 0 && user.UID == spec.UID) || (spec.Login != "" && user.Login == spec.Login) {
+			return writeJSON(w, user)
+		}
+	}
+	return sourcegraph.ErrUserNotExist
+}
@@ -0,0 +1,189 @@
+// Package srclibstore implements the sourcegraph package's service
+// interfaces on top of srclib stores on disk (such as the build data
+// that BuildDataService fetches, opened with srclib's store package),
+// so that code can be browsed and searched without access to the
+// Sourcegraph API.
+//
+// The services can be used in place of a sourcegraph.Client's
+// services, or served over HTTP with the server package:
+//
+//	c := sourcegraph.NewClient(nil)
+//	c.Defs = srclibstore.NewDefsService(store.NewFSMultiRepoStore(fs, nil))
+package srclibstore
+
+import (
+	"context"
+	"errors"
+	"html/template"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/srclib/graph"
+	"sourcegraph.com/sourcegraph/srclib/store"
+	"sourcegraph.com/sourcegraph/srclib/unit"
+)
+
+// ErrNotSupported is returned by service methods that can't be
+// answered from srclib stores (such as those that need the
+// Sourcegraph API's global reference and authorship data).
+var ErrNotSupported = errors.New("not supported by srclib store services")
+
+// A DefStore is the part of a srclib store (such as a
+// store.MultiRepoStore, store.RepoStore, or store.TreeStore) that
+// DefsService uses.
+type DefStore interface {
+	// Defs returns the defs that all of the filters select.
+	Defs(...store.DefFilter) ([]*graph.Def, error)
+}
+
+// NewDefsService returns a DefsService that answers Get and List from
+// the defs in s, with the same semantics as the Sourcegraph API
+// (including Fuzzy queries, sorting, and pagination; see
+// DefListOptions.DefFilters). Def statistics are not available, so
+// the Stats options are ignored. The other methods return
+// ErrNotSupported.
+func NewDefsService(s DefStore) sourcegraph.DefsService {
+	return &defsService{store: s}
+}
+
+// defsService implements sourcegraph.DefsService.
+type defsService struct {
+	store DefStore
+}
+
+var _ sourcegraph.DefsService = &defsService{}
+
+// Get returns the def specified by def. If def.CommitID is empty, the
+// def may be from any commit of the repository. If the def does not
+// exist, it returns an error that is both sourcegraph.ErrNotExist and
+// graph.ErrDefNotExist to errors.Is.
+func (s *defsService) Get(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefGetOptions) (*sourcegraph.Def, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.DefGetOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	fs := []store.DefFilter{
+		store.ByUnits(unit.ID2{Type: def.UnitType, Name: def.Unit}),
+		store.DefFilterFunc(func(d *graph.Def) bool {
+			return d.Repo == def.Repo && d.Path == def.Path
+		}),
+	}
+	if def.CommitID != "" {
+		fs = append(fs, store.ByRepoCommitIDs(store.Version{Repo: def.Repo, CommitID: def.CommitID}))
+	}
+	defs, err := s.store.Defs(fs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(defs) == 0 {
+		return nil, nil, defNotExistError{}
+	}
+	return newDef(defs[0], opt.Doc), nil, nil
+}
+
+// List returns the defs that match opt. Its Response's TotalCount is
+// the number of matching defs on all pages.
+func (s *defsService) List(ctx context.Context, opt *sourcegraph.DefListOptions) ([]*sourcegraph.Def, sourcegraph.Response, error) {
+	if opt == nil {
+		opt = &sourcegraph.DefListOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, nil, err
+	}
+	fs := opt.DefFilters()
+	defs, err := s.store.Defs(fs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	sourcegraph.SortDefs(defs, fs)
+
+	total := len(defs)
+	if start := opt.Offset(); start < len(defs) {
+		defs = defs[start:]
+	} else {
+		defs = nil
+	}
+	if limit := opt.Limit(); limit < len(defs) {
+		defs = defs[:limit]
+	}
+
+	results := make([]*sourcegraph.Def, len(defs))
+	for i, def := range defs {
+		results[i] = newDef(def, opt.Doc)
+	}
+	return results, totalCount(total), nil
+}
+
+func (s *defsService) ListRefs(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListRefsOptions) ([]*sourcegraph.Ref, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (s *defsService) ListExamples(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListExamplesOptions) ([]*sourcegraph.Example, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (s *defsService) ListAuthors(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListAuthorsOptions) ([]*sourcegraph.AugmentedDefAuthor, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (s *defsService) ListClients(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListClientsOptions) ([]*sourcegraph.AugmentedDefClient, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (s *defsService) ListDependents(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListDependentsOptions) ([]*sourcegraph.AugmentedDefDependent, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (s *defsService) ListVersions(ctx context.Context, def sourcegraph.DefSpec, opt *sourcegraph.DefListVersionsOptions) ([]*sourcegraph.Def, sourcegraph.Response, error) {
+	return nil, nil, ErrNotSupported
+}
+
+// defNotExistError is the error that Get returns when the def doesn't
+// exist. It has graph.ErrDefNotExist's message, as the API's error
+// does, and it is sourcegraph.ErrNotExist to errors.Is so that it is
+// served (by package server) with HTTP 404 Not Found.
+type defNotExistError struct{}
+
+func (defNotExistError) Error() string { return graph.ErrDefNotExist.Error() }
+
+func (defNotExistError) Is(target error) bool {
+	return target == sourcegraph.ErrNotExist || target == graph.ErrDefNotExist
+}
+
+// newDef returns the API representation of def. If doc is true, its
+// DocHTML is set from def's HTML (or plain text) docs.
+func newDef(def *graph.Def, doc bool) *sourcegraph.Def {
+	d := &sourcegraph.Def{Def: *def}
+	if doc {
+		d.DocHTML = docHTML(def.Docs)
+	}
+	return d
+}
+
+// docHTML returns the HTML of the first HTML doc in docs, or the
+// escaped text of the first plain text doc if there is none.
+func docHTML(docs []*graph.DefDoc) string {
+	var text string
+	for _, doc := range docs {
+		switch doc.Format {
+		case "text/html":
+			return doc.Data
+		case "text/plain":
+			if text == "" {
+				text = template.HTMLEscapeString(doc.Data)
+			}
+		}
+	}
+	return text
+}
+
+// totalCount is a sourcegraph.Response for lists of results.
+type totalCount int
+
+func (n totalCount) TotalCount() int { return int(n) }
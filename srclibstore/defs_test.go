@@ -0,0 +1,194 @@
+package srclibstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/server"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/srclib/graph"
+	"sourcegraph.com/sourcegraph/srclib/store"
+)
+
+// memStore is a DefStore that holds defs in memory.
+type memStore []*graph.Def
+
+func (s memStore) Defs(fs ...store.DefFilter) ([]*graph.Def, error) {
+	var defs []*graph.Def
+	for _, def := range s {
+		selected := true
+		for _, f := range fs {
+			if !f.SelectDef(def) {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			defs = append(defs, def)
+		}
+	}
+	return defs, nil
+}
+
+const commitID = "0123456789012345678901234567890123456789"
+
+func newTestDefsService() (sourcegraph.DefsService, memStore) {
+	key := func(repo, path string) graph.DefKey {
+		return graph.DefKey{Repo: repo, CommitID: commitID, UnitType: "t", Unit: "u", Path: path}
+	}
+	defs := memStore{
+		{DefKey: key("r.com/a", "NewClient"), Name: "NewClient", Kind: "func", File: "a/client.go", Exported: true, Docs: []*graph.DefDoc{{Format: "text/plain", Data: "a<b"}}},
+		{DefKey: key("r.com/a", "Client"), Name: "Client", Kind: "type", File: "a/client.go", Exported: true, Docs: []*graph.DefDoc{{Format: "text/plain", Data: "x"}, {Format: "text/html", Data: "<p>y</p>"}}},
+		{DefKey: key("r.com/a", "newClient"), Name: "newClient", Kind: "func", File: "a/client.go"},
+		{DefKey: key("r.com/a", "TestClient"), Name: "TestClient", Kind: "func", File: "a/client_test.go", Exported: true, Test: true},
+		{DefKey: key("r.com/b", "Close"), Name: "Close", Kind: "func", File: "b.go", Exported: true},
+	}
+	return NewDefsService(defs), defs
+}
+
+func TestDefsService_Get(t *testing.T) {
+	s, defs := newTestDefsService()
+	ctx := context.Background()
+
+	spec := sourcegraph.DefSpec{Repo: "r.com/a", UnitType: "t", Unit: "u", Path: "Client"}
+	def, _, err := s.Get(ctx, spec, &sourcegraph.DefGetOptions{Doc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&sourcegraph.Def{Def: *defs[1], DocHTML: "<p>y</p>"}); !reflect.DeepEqual(def, want) {
+		t.Errorf("got def %+v, want %+v", def, want)
+	}
+
+	spec.CommitID = commitID
+	if _, _, err := s.Get(ctx, spec, nil); err != nil {
+		t.Errorf("with commit ID: %s", err)
+	}
+
+	spec.CommitID = "1123456789012345678901234567890123456789"
+	_, _, err = s.Get(ctx, spec, nil)
+	if !errors.Is(err, sourcegraph.ErrNotExist) || !errors.Is(err, graph.ErrDefNotExist) {
+		t.Errorf("with other commit ID: got error %v, want sourcegraph.ErrNotExist and graph.ErrDefNotExist", err)
+	}
+}
+
+func TestDefsService_List(t *testing.T) {
+	s, _ := newTestDefsService()
+	repoRevs := []string{"r.com/a@" + commitID}
+
+	tests := map[string]struct {
+		opt       *sourcegraph.DefListOptions
+		want      []string // def names
+		wantTotal int
+	}{
+		"nil options": {
+			opt:       nil,
+			want:      []string{"NewClient", "Client", "newClient", "Close"},
+			wantTotal: 4,
+		},
+		"filters": {
+			opt:       &sourcegraph.DefListOptions{RepoRevs: repoRevs, Kinds: []string{"func"}, Exported: true, IncludeTest: true},
+			want:      []string{"NewClient", "TestClient"},
+			wantTotal: 2,
+		},
+		"sort by name": {
+			opt:       &sourcegraph.DefListOptions{Sort: "name"},
+			want:      []string{"Client", "Close", "NewClient", "newClient"},
+			wantTotal: 4,
+		},
+		"sort by name descending, paginated": {
+			opt:       &sourcegraph.DefListOptions{Sort: "name", Direction: "desc", ListOptions: sourcegraph.ListOptions{PerPage: 3, Page: 1}},
+			want:      []string{"newClient", "NewClient", "Close"},
+			wantTotal: 4,
+		},
+		"last page": {
+			opt:       &sourcegraph.DefListOptions{Sort: "name", ListOptions: sourcegraph.ListOptions{PerPage: 3, Page: 2}},
+			want:      []string{"newClient"},
+			wantTotal: 4,
+		},
+		"past last page": {
+			opt:       &sourcegraph.DefListOptions{ListOptions: sourcegraph.ListOptions{PerPage: 3, Page: 3}},
+			want:      []string{},
+			wantTotal: 4,
+		},
+		"fuzzy": {
+			opt:       &sourcegraph.DefListOptions{Query: "clnt", Fuzzy: true, Sort: "name"},
+			want:      []string{"Client", "NewClient", "newClient"},
+			wantTotal: 3,
+		},
+		"fuzzy exact match first": {
+			opt:       &sourcegraph.DefListOptions{Query: "newclient", Fuzzy: true},
+			want:      []string{"NewClient", "newClient"},
+			wantTotal: 2,
+		},
+	}
+	for label, test := range tests {
+		defs, resp, err := s.List(context.Background(), test.opt)
+		if err != nil {
+			t.Errorf("%s: List: %s", label, err)
+			continue
+		}
+		names := make([]string, len(defs))
+		for i, def := range defs {
+			names[i] = def.Name
+		}
+		if !reflect.DeepEqual(names, test.want) {
+			t.Errorf("%s: got defs %v, want %v", label, names, test.want)
+		}
+		if total := resp.TotalCount(); total != test.wantTotal {
+			t.Errorf("%s: got total count %d, want %d", label, total, test.wantTotal)
+		}
+	}
+}
+
+func TestDefsService_List_invalid(t *testing.T) {
+	s, _ := newTestDefsService()
+	_, _, err := s.List(context.Background(), &sourcegraph.DefListOptions{Sort: "xrefs"})
+	if _, ok := err.(*sourcegraph.InvalidOptionsError); !ok {
+		t.Errorf("got error %v, want *sourcegraph.InvalidOptionsError", err)
+	}
+}
+
+// TestDefsService_HTTP checks that the service's errors are served
+// with the same HTTP status codes as the API's.
+func TestDefsService_HTTP(t *testing.T) {
+	defs, _ := newTestDefsService()
+	hs := httptest.NewServer(server.NewHandler(nil, server.Services{Defs: defs}))
+	defer hs.Close()
+	c := sourcegraph.NewClient(nil)
+	c.BaseURL, _ = url.Parse(hs.URL + "/")
+
+	_, _, err := c.Defs.List(context.Background(), &sourcegraph.DefListOptions{Sort: "xrefs"})
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusBadRequest) {
+		t.Errorf("List with invalid options: got error %v, want HTTP 400", err)
+	}
+
+	_, _, err = c.Defs.Get(context.Background(), sourcegraph.DefSpec{Repo: "r.com/a", CommitID: commitID, UnitType: "t", Unit: "u", Path: "x"}, nil)
+	if !sourcegraph.IsHTTPErrorCode(err, http.StatusNotFound) || !sourcegraph.IsDefError(err) {
+		t.Errorf("Get of nonexistent def: got error %v, want HTTP 404 with the def error message", err)
+	}
+}
+
+func TestDefsService_List_doc(t *testing.T) {
+	s, _ := newTestDefsService()
+	defs, _, err := s.List(context.Background(), &sourcegraph.DefListOptions{Name: "NewClient", Doc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 1 || defs[0].DocHTML != "a&lt;b" {
+		t.Errorf("got defs %+v, want NewClient with DocHTML %q", defs, "a&lt;b")
+	}
+}
+
+func TestDefsService_canceled(t *testing.T) {
+	s, _ := newTestDefsService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := s.List(ctx, nil); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/go-github/github"
+)
+
+// Event types, as sent in the X-GitHub-Event header.
+const (
+	PingEventType        = "ping"
+	PushEventType        = "push"
+	PullRequestEventType = "pull_request"
+	IssuesEventType      = "issues"
+	StatusEventType      = "status"
+)
+
+// A PingEvent is sent when a webhook is first created.
+type PingEvent struct {
+	Zen    string `json:"zen"`
+	HookID int    `json:"hook_id"`
+}
+
+// A PushEvent is sent when commits are pushed to a branch or tag of
+// a repository (or when a branch or tag is created or deleted).
+type PushEvent struct {
+	// Ref is the full name of the ref that was pushed (e.g.,
+	// "refs/heads/master").
+	Ref string `json:"ref"`
+
+	// Before and After are the commit IDs that Ref pointed to before
+	// and after the push. If the ref was created, Before is all
+	// zeroes; if it was deleted, After is all zeroes.
+	Before string `json:"before"`
+	After  string `json:"after"`
+
+	Created bool `json:"created"`
+	Deleted bool `json:"deleted"`
+	Forced  bool `json:"forced"`
+
+	Commits    []*PushEventCommit `json:"commits"`
+	HeadCommit *PushEventCommit   `json:"head_commit"`
+
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
+// Rev returns the branch or tag name of e.Ref, without the
+// "refs/heads/" or "refs/tags/" prefix.
+func (e *PushEvent) Rev() string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if strings.HasPrefix(e.Ref, prefix) {
+			return strings.TrimPrefix(e.Ref, prefix)
+		}
+	}
+	return e.Ref
+}
+
+// A PushEventCommit is a commit in a PushEvent.
+type PushEventCommit struct {
+	ID       string   `json:"id"`
+	Message  string   `json:"message"`
+	Distinct bool     `json:"distinct"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// A PullRequestEvent is sent when a pull request is opened, closed,
+// reopened, synchronized (pushed to), etc.
+type PullRequestEvent struct {
+	Action      string              `json:"action"`
+	Number      int                 `json:"number"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+	Repo        *github.Repository  `json:"repository"`
+	Sender      *github.User        `json:"sender"`
+}
+
+// An IssuesEvent is sent when an issue is opened, closed, reopened,
+// assigned, labeled, etc.
+type IssuesEvent struct {
+	Action string             `json:"action"`
+	Issue  *github.Issue      `json:"issue"`
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
+// A StatusEvent is sent when the status of a commit changes.
+type StatusEvent struct {
+	github.RepoStatus
+
+	// SHA is the commit ID whose status changed.
+	SHA string `json:"sha"`
+
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
+// ParseEvent decodes a webhook payload of the given event type (from
+// the X-GitHub-Event header) into a *PingEvent, *PushEvent,
+// *PullRequestEvent, *IssuesEvent, or *StatusEvent.
+//
+// If the event type is not one of these, ParseEvent returns an
+// *UnknownEventError.
+func ParseEvent(eventType string, payload []byte) (interface{}, error) {
+	var event interface{}
+	switch eventType {
+	case PingEventType:
+		event = &PingEvent{}
+	case PushEventType:
+		event = &PushEvent{}
+	case PullRequestEventType:
+		event = &PullRequestEvent{}
+	case IssuesEventType:
+		event = &IssuesEvent{}
+	case StatusEventType:
+		event = &StatusEvent{}
+	default:
+		return nil, &UnknownEventError{eventType}
+	}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("decoding %s event payload: %s", eventType, err)
+	}
+	return event, nil
+}
+
+// An UnknownEventError is returned by ParseEvent when the event type
+// is not supported.
+type UnknownEventError struct {
+	EventType string
+}
+
+func (e *UnknownEventError) Error() string {
+	return fmt.Sprintf("unknown GitHub webhook event type %q", e.EventType)
+}
+
+// RepoURI returns the Sourcegraph repository URI of a GitHub
+// repository in an event payload (e.g., "github.com/alice/myrepo").
+func RepoURI(repo *github.Repository) string {
+	if repo == nil || repo.FullName == nil {
+		return ""
+	}
+	return "github.com/" + *repo.FullName
+}
@@ -0,0 +1,254 @@
+// Package webhook receives GitHub webhook events (which GitHub sends
+// to the router.ExtGitHubReceiveWebhook route) and dispatches them to
+// registered handlers.
+//
+// A Receiver verifies each request's X-Hub-Signature, decodes its
+// payload into a typed event (such as a *PushEvent), and calls the
+// handlers registered for that event type:
+//
+//	rcv := &webhook.Receiver{Secret: []byte(secret)}
+//	rcv.OnPush(webhook.RefreshOnPush(client, &sourcegraph.BuildCreateOptions{
+//		BuildConfig: sourcegraph.BuildConfig{Import: true, Queue: true},
+//	}))
+//	apiRouter.Get(router.ExtGitHubReceiveWebhook).Handler(rcv)
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// maxPayloadSize is the maximum size of a webhook payload. GitHub
+// caps payloads at 25 MB.
+const maxPayloadSize = 25 << 20
+
+// A Receiver is an http.Handler that receives GitHub webhook events
+// and dispatches them to the handlers registered for their event
+// type.
+//
+// Handlers must be registered before the Receiver begins serving
+// requests.
+type Receiver struct {
+	// Secret is the webhook's secret, used to verify the
+	// X-Hub-Signature of each request. If empty, all requests are
+	// rejected (unless InsecureSkipSignature is set).
+	Secret []byte
+
+	// InsecureSkipSignature, if set, makes the Receiver accept
+	// requests without verifying their X-Hub-Signature when Secret is
+	// empty. Anyone who can reach the Receiver can then send it
+	// events, so this should only be used in tests.
+	InsecureSkipSignature bool
+
+	// Log, if set, is used to log events that fail to be handled.
+	Log *log.Logger
+
+	push        []func(context.Context, *PushEvent) error
+	pullRequest []func(context.Context, *PullRequestEvent) error
+	issues      []func(context.Context, *IssuesEvent) error
+	status      []func(context.Context, *StatusEvent) error
+}
+
+// OnPush registers a handler for push events.
+func (rcv *Receiver) OnPush(h func(context.Context, *PushEvent) error) {
+	rcv.push = append(rcv.push, h)
+}
+
+// OnPullRequest registers a handler for pull_request events.
+func (rcv *Receiver) OnPullRequest(h func(context.Context, *PullRequestEvent) error) {
+	rcv.pullRequest = append(rcv.pullRequest, h)
+}
+
+// OnIssues registers a handler for issues events.
+func (rcv *Receiver) OnIssues(h func(context.Context, *IssuesEvent) error) {
+	rcv.issues = append(rcv.issues, h)
+}
+
+// OnStatus registers a handler for status events.
+func (rcv *Receiver) OnStatus(h func(context.Context, *StatusEvent) error) {
+	rcv.status = append(rcv.status, h)
+}
+
+// ServeHTTP implements http.Handler.
+//
+// It responds with HTTP 403 if the request's signature is invalid (or
+// if no Secret is set), HTTP 400 if its payload can't be decoded, and
+// HTTP 500 if a handler returns an error (the error is logged, not
+// sent in the response). Events of unsupported types (and events with no
+// registered handlers) are acknowledged and ignored.
+func (rcv *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		payload []byte
+		err     error
+	)
+	if len(rcv.Secret) == 0 && rcv.InsecureSkipSignature {
+		payload, err = readPayload(r)
+	} else {
+		payload, err = ValidatePayload(r, rcv.Secret)
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == ErrInvalidSignature || err == ErrNoSecret {
+			status = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	eventType := r.Header.Get("X-GitHub-Event")
+	event, err := ParseEvent(eventType, payload)
+	if err != nil {
+		if _, ok := err.(*UnknownEventError); ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := rcv.Dispatch(r.Context(), event); err != nil {
+		if rcv.Log != nil {
+			rcv.Log.Printf("Error handling GitHub %s webhook event (delivery %s): %s", eventType, r.Header.Get("X-GitHub-Delivery"), err)
+		}
+		http.Error(w, "error handling GitHub webhook event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Dispatch calls the handlers registered for event's type (in the
+// order they were registered) and returns the first error that a
+// handler returns. The event must be one of the types returned by
+// ParseEvent.
+func (rcv *Receiver) Dispatch(ctx context.Context, event interface{}) error {
+	switch e := event.(type) {
+	case *PingEvent:
+		return nil
+	case *PushEvent:
+		for _, h := range rcv.push {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	case *PullRequestEvent:
+		for _, h := range rcv.pullRequest {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	case *IssuesEvent:
+		for _, h := range rcv.issues {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	case *StatusEvent:
+		for _, h := range rcv.status {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported GitHub webhook event %T", event)
+	}
+	return nil
+}
+
+// ErrInvalidSignature is returned by ValidatePayload when a request's
+// X-Hub-Signature is missing or does not match its payload.
+var ErrInvalidSignature = errors.New("invalid GitHub webhook signature")
+
+// ErrNoSecret is returned by ValidatePayload when it is called with
+// an empty secret, because the request's signature can't be verified.
+var ErrNoSecret = errors.New("no GitHub webhook secret is configured")
+
+// ValidatePayload reads and returns the payload of a webhook request
+// after verifying its X-Hub-Signature (an HMAC-SHA1 of the payload,
+// keyed by secret). It returns ErrInvalidSignature if the signature
+// is missing or incorrect, and ErrNoSecret if secret is empty.
+func ValidatePayload(r *http.Request, secret []byte) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, ErrNoSecret
+	}
+	payload, err := readPayload(r)
+	if err != nil {
+		return nil, err
+	}
+	if !validSignature(r.Header.Get("X-Hub-Signature"), payload, secret) {
+		return nil, ErrInvalidSignature
+	}
+	return payload, nil
+}
+
+// readPayload reads the payload of a webhook request, without
+// verifying its signature.
+func readPayload(r *http.Request) ([]byte, error) {
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > maxPayloadSize {
+		return nil, errors.New("GitHub webhook payload is too large")
+	}
+	return payload, nil
+}
+
+// validSignature returns whether sig (of the form "sha1=hexdigest")
+// is the HMAC-SHA1 of payload keyed by secret.
+func validSignature(sig string, payload, secret []byte) bool {
+	const prefix = "sha1="
+	if !strings.HasPrefix(sig, prefix) {
+		return false
+	}
+	got, err := hex.DecodeString(sig[len(prefix):])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// RefreshOnPush returns a push event handler that updates the pushed
+// repository's VCS data (with Repos.RefreshVCSData) and, if buildOpt
+// is non-nil, creates a build of the pushed commit (with
+// Builds.Create). Pushes that delete a ref are ignored.
+func RefreshOnPush(c *sourcegraph.Client, buildOpt *sourcegraph.BuildCreateOptions) func(context.Context, *PushEvent) error {
+	return func(ctx context.Context, e *PushEvent) error {
+		if e.Deleted {
+			return nil
+		}
+		repo := sourcegraph.RepoSpec{URI: RepoURI(e.Repo)}
+		if repo.URI == "" {
+			return errors.New("push event has no repository")
+		}
+
+		if _, err := c.Repos.RefreshVCSData(ctx, repo); err != nil {
+			return fmt.Errorf("refreshing VCS data for %s: %s", repo.URI, err)
+		}
+
+		if buildOpt != nil {
+			repoRev := sourcegraph.RepoRevSpec{RepoSpec: repo, Rev: e.Rev(), CommitID: e.After}
+			if _, _, err := c.Builds.Create(ctx, repoRev, buildOpt); err != nil {
+				return fmt.Errorf("creating build for %s@%s: %s", repo.URI, e.After, err)
+			}
+		}
+		return nil
+	}
+}
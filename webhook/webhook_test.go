@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-github/github"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+var testSecret = []byte("s3cret")
+
+func sign(payload, secret []byte) string {
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(payload)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newRequest(eventType, payload, sig string) *http.Request {
+	req, _ := http.NewRequest("POST", "/ext/github/webhook", bytes.NewReader([]byte(payload)))
+	req.Header.Set("X-GitHub-Event", eventType)
+	if sig != "" {
+		req.Header.Set("X-Hub-Signature", sig)
+	}
+	return req
+}
+
+const pushPayload = `{
+  "ref": "refs/heads/master",
+  "before": "aaa",
+  "after": "bbb",
+  "commits": [{"id": "bbb", "message": "m", "added": ["f"]}],
+  "repository": {"name": "r", "full_name": "alice/r"},
+  "sender": {"login": "alice"}
+}`
+
+func TestReceiver_signature(t *testing.T) {
+	tests := map[string]struct {
+		secret     []byte
+		insecure   bool
+		sig        string
+		wantStatus int
+	}{
+		"valid":                     {secret: testSecret, sig: sign([]byte(pushPayload), testSecret), wantStatus: http.StatusNoContent},
+		"wrong secret":              {secret: testSecret, sig: sign([]byte(pushPayload), []byte("x")), wantStatus: http.StatusForbidden},
+		"missing":                   {secret: testSecret, wantStatus: http.StatusForbidden},
+		"malformed":                 {secret: testSecret, sig: "sha1=zz", wantStatus: http.StatusForbidden},
+		"wrong algo":                {secret: testSecret, sig: "md5=" + sign([]byte(pushPayload), testSecret)[5:], wantStatus: http.StatusForbidden},
+		"no secret":                 {wantStatus: http.StatusForbidden},
+		"no secret, sig":            {sig: sign([]byte(pushPayload), nil), wantStatus: http.StatusForbidden},
+		"insecure":                  {insecure: true, wantStatus: http.StatusNoContent},
+		"insecure, sig":             {insecure: true, sig: "sha1=00", wantStatus: http.StatusNoContent},
+		"secret, insecure, missing": {secret: testSecret, insecure: true, wantStatus: http.StatusForbidden},
+	}
+	for label, test := range tests {
+		var called bool
+		rcv := &Receiver{Secret: test.secret, InsecureSkipSignature: test.insecure}
+		rcv.OnPush(func(ctx context.Context, e *PushEvent) error {
+			called = true
+			return nil
+		})
+
+		rw := httptest.NewRecorder()
+		rcv.ServeHTTP(rw, newRequest("push", pushPayload, test.sig))
+		if rw.Code != test.wantStatus {
+			t.Errorf("%s: got status %d, want %d", label, rw.Code, test.wantStatus)
+		}
+		if wantCalled := test.wantStatus == http.StatusNoContent; called != wantCalled {
+			t.Errorf("%s: got called %v, want %v", label, called, wantCalled)
+		}
+	}
+}
+
+func TestReceiver_dispatch(t *testing.T) {
+	rcv := &Receiver{Secret: testSecret}
+
+	var pushes []*PushEvent
+	for i := 0; i < 2; i++ {
+		rcv.OnPush(func(ctx context.Context, e *PushEvent) error {
+			pushes = append(pushes, e)
+			return nil
+		})
+	}
+	var prs []*PullRequestEvent
+	rcv.OnPullRequest(func(ctx context.Context, e *PullRequestEvent) error {
+		prs = append(prs, e)
+		return nil
+	})
+	var issues []*IssuesEvent
+	rcv.OnIssues(func(ctx context.Context, e *IssuesEvent) error {
+		issues = append(issues, e)
+		return nil
+	})
+	var statuses []*StatusEvent
+	rcv.OnStatus(func(ctx context.Context, e *StatusEvent) error {
+		statuses = append(statuses, e)
+		return nil
+	})
+
+	send := func(eventType, payload string) int {
+		rw := httptest.NewRecorder()
+		rcv.ServeHTTP(rw, newRequest(eventType, payload, sign([]byte(payload), testSecret)))
+		return rw.Code
+	}
+
+	if status := send("push", pushPayload); status != http.StatusNoContent {
+		t.Fatalf("push: got status %d", status)
+	}
+	if len(pushes) != 2 || pushes[0] != pushes[1] {
+		t.Fatalf("got %d push events, want 2 (one per handler)", len(pushes))
+	}
+	wantPush := &PushEvent{
+		Ref:     "refs/heads/master",
+		Before:  "aaa",
+		After:   "bbb",
+		Commits: []*PushEventCommit{{ID: "bbb", Message: "m", Added: []string{"f"}}},
+		Repo:    &github.Repository{Name: github.String("r"), FullName: github.String("alice/r")},
+		Sender:  &github.User{Login: github.String("alice")},
+	}
+	if !reflect.DeepEqual(pushes[0], wantPush) {
+		t.Errorf("got push event %+v, want %+v", pushes[0], wantPush)
+	}
+
+	send("pull_request", `{"action": "opened", "number": 3, "pull_request": {"number": 3}, "repository": {"full_name": "alice/r"}}`)
+	if len(prs) != 1 || prs[0].Action != "opened" || *prs[0].PullRequest.Number != 3 || RepoURI(prs[0].Repo) != "github.com/alice/r" {
+		t.Errorf("got pull request events %+v", prs)
+	}
+
+	send("issues", `{"action": "closed", "issue": {"number": 4}}`)
+	if len(issues) != 1 || issues[0].Action != "closed" || *issues[0].Issue.Number != 4 {
+		t.Errorf("got issues events %+v", issues)
+	}
+
+	send("status", `{"sha": "ccc", "state": "success", "target_url": "http://example.com"}`)
+	if len(statuses) != 1 || statuses[0].SHA != "ccc" || *statuses[0].State != "success" || *statuses[0].TargetURL != "http://example.com" {
+		t.Errorf("got status events %+v", statuses)
+	}
+
+	if status := send("ping", `{"zen": "z", "hook_id": 1}`); status != http.StatusNoContent {
+		t.Errorf("ping: got status %d, want %d", status, http.StatusNoContent)
+	}
+	if status := send("watch", `{}`); status != http.StatusNoContent {
+		t.Errorf("unknown event: got status %d, want %d", status, http.StatusNoContent)
+	}
+	if status := send("push", `{`); status != http.StatusBadRequest {
+		t.Errorf("bad payload: got status %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestReceiver_handlerError(t *testing.T) {
+	var logBuf bytes.Buffer
+	rcv := &Receiver{InsecureSkipSignature: true, Log: log.New(&logBuf, "", 0)}
+	var calledSecond bool
+	rcv.OnPush(func(ctx context.Context, e *PushEvent) error { return errors.New("internal detail") })
+	rcv.OnPush(func(ctx context.Context, e *PushEvent) error {
+		calledSecond = true
+		return nil
+	})
+
+	rw := httptest.NewRecorder()
+	rcv.ServeHTTP(rw, newRequest("push", pushPayload, ""))
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rw.Code, http.StatusInternalServerError)
+	}
+	if calledSecond {
+		t.Error("handler after failed handler was called")
+	}
+	if strings.Contains(rw.Body.String(), "internal detail") {
+		t.Errorf("got response body %q, want it not to contain the handler's error", rw.Body.String())
+	}
+	if !strings.Contains(logBuf.String(), "internal detail") {
+		t.Errorf("got log %q, want it to contain the handler's error", logBuf.String())
+	}
+}
+
+func TestRefreshOnPush(t *testing.T) {
+	var refreshed []sourcegraph.RepoSpec
+	var built []sourcegraph.RepoRevSpec
+	c := &sourcegraph.Client{
+		Repos: &sourcegraph.MockReposService{
+			RefreshVCSData_: func(ctx context.Context, repo sourcegraph.RepoSpec) (sourcegraph.Response, error) {
+				refreshed = append(refreshed, repo)
+				return nil, nil
+			},
+		},
+		Builds: &sourcegraph.MockBuildsService{
+			Create_: func(ctx context.Context, repoRev sourcegraph.RepoRevSpec, opt *sourcegraph.BuildCreateOptions) (*sourcegraph.Build, sourcegraph.Response, error) {
+				if !opt.Queue {
+					t.Errorf("got opt %+v, want Queue", opt)
+				}
+				built = append(built, repoRev)
+				return &sourcegraph.Build{}, nil, nil
+			},
+		},
+	}
+
+	h := RefreshOnPush(c, &sourcegraph.BuildCreateOptions{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+	repo := &github.Repository{FullName: github.String("alice/r")}
+	if err := h(context.Background(), &PushEvent{Ref: "refs/tags/v1", After: "bbb", Repo: repo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h(context.Background(), &PushEvent{Ref: "refs/heads/b", Deleted: true, Repo: repo}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRepo := sourcegraph.RepoSpec{URI: "github.com/alice/r"}
+	if want := []sourcegraph.RepoSpec{wantRepo}; !reflect.DeepEqual(refreshed, want) {
+		t.Errorf("got refreshed %+v, want %+v", refreshed, want)
+	}
+	if want := []sourcegraph.RepoRevSpec{{RepoSpec: wantRepo, Rev: "v1", CommitID: "bbb"}}; !reflect.DeepEqual(built, want) {
+		t.Errorf("got built %+v, want %+v", built, want)
+	}
+}
@@ -0,0 +1,316 @@
+// Package worker implements a build worker that dequeues builds from
+// the Sourcegraph API and performs them.
+//
+// See the documentation for sourcegraph.Build for an explanation of
+// builds, tasks, and workers.
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/auth"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+)
+
+// A Worker dequeues builds and performs their tasks, keeping the
+// builds' and tasks' status up to date on the server.
+type Worker struct {
+	// Client is used to dequeue builds. It must be set.
+	Client *sourcegraph.Client
+
+	// Transport is the underlying HTTP transport used by each
+	// Build's Client. If nil, Client's transport is used.
+	Transport http.RoundTripper
+
+	// Plan returns the tasks to perform for a build. It must be set.
+	Plan func(ctx context.Context, b *Build) ([]*Task, error)
+
+	// TaskLog, if set, returns the writer that a task's output is
+	// written to. The writer is closed after the task ends. If nil,
+	// task output is discarded.
+	TaskLog func(task *sourcegraph.BuildTask) (io.WriteCloser, error)
+
+	// Host is the hostname reported for builds that this worker
+	// performs. If empty, os.Hostname is used.
+	Host string
+
+	// PollInterval is how long to wait before dequeuing again when
+	// the queue is empty. If zero, 5s is used.
+	PollInterval time.Duration
+
+	// HeartbeatInterval is how often to update a build's HeartbeatAt
+	// while it is being performed. If zero, 15s is used.
+	HeartbeatInterval time.Duration
+}
+
+// A Build is a build that a Worker is performing.
+type Build struct {
+	*sourcegraph.Build
+
+	// Client is a client that is authenticated with the tickets that
+	// the server granted for this build (which permit, e.g.,
+	// uploading build data for the build's repository), in addition
+	// to the Worker's Client's credentials. It is otherwise
+	// configured like the Worker's Client (see
+	// sourcegraph.Client.WithTransport).
+	Client *sourcegraph.Client
+}
+
+// A Task is a task to perform as part of a build.
+type Task struct {
+	// BuildTask describes the task. Its TaskID and BID are set when
+	// the task is created.
+	//
+	// Tasks are performed in increasing Order, and tasks with the
+	// same Order are performed in the order they were returned by
+	// Plan. If Queue is true, the task is created but not performed
+	// by this worker (a queue worker on the server performs it), and
+	// its outcome doesn't affect the build's outcome.
+	sourcegraph.BuildTask
+
+	// Run performs the task, writing its output to log. It is not
+	// called for queued tasks.
+	Run func(ctx context.Context, log io.Writer) error
+}
+
+// finishTimeout is how long a Worker waits for the final status
+// updates of a build to complete after its context is canceled.
+const finishTimeout = 10 * time.Second
+
+// Run dequeues and performs builds until ctx is canceled, and then
+// returns ctx's error. A build that is being performed when ctx is
+// canceled is marked as killed.
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		performed, err := w.RunOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || !performed {
+			t := time.NewTimer(durationOrDefault(w.PollInterval, 5*time.Second))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// RunOnce dequeues a single build and performs it. It returns whether
+// a build was dequeued. A build that fails is not considered an error
+// (it is reported on the server); only errors that prevent the build
+// from being performed or its status from being updated are returned.
+func (w *Worker) RunOnce(ctx context.Context) (performed bool, err error) {
+	build, resp, err := w.Client.Builds.DequeueNext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if build == nil {
+		return false, nil
+	}
+	var tickets []string
+	if resp, ok := resp.(*sourcegraph.HTTPResponse); ok && resp != nil {
+		tickets = auth.GetSignedTicketStrings(resp.Header)
+	}
+	return true, w.perform(ctx, &Build{Build: build, Client: w.buildClient(tickets)})
+}
+
+// buildClient returns a copy of w.Client that also authenticates its
+// requests with the given tickets.
+func (w *Worker) buildClient(tickets []string) *sourcegraph.Client {
+	return w.Client.WithTransport(func(t http.RoundTripper) http.RoundTripper {
+		if w.Transport != nil {
+			t = w.Transport
+		}
+		return &auth.TicketAuthedTransport{SignedTicketStrings: tickets, Transport: t}
+	})
+}
+
+// perform performs a dequeued build and updates its status.
+func (w *Worker) perform(ctx context.Context, b *Build) error {
+	host := w.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	now := time.Now()
+	if _, _, err := b.Client.Builds.Update(ctx, b.Spec(), sourcegraph.BuildUpdate{Host: &host, HeartbeatAt: &now}); err != nil {
+		// The build was started when it was dequeued, so end it
+		// instead of leaving it started until it is killed for lack
+		// of a heartbeat.
+		w.finish(ctx, b, err)
+		return err
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		w.heartbeat(heartbeatCtx, b)
+	}()
+	buildErr := w.performTasks(ctx, b)
+	stopHeartbeat()
+	<-heartbeatDone
+
+	return w.finish(ctx, b, buildErr)
+}
+
+// finish ends the build, marking it as failed if buildErr is non-nil
+// (and as killed if ctx was canceled).
+func (w *Worker) finish(ctx context.Context, b *Build, buildErr error) error {
+	// Report the outcome even if ctx was canceled.
+	finishCtx, cancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancel()
+	now := time.Now()
+	success, failure, killed := buildErr == nil, buildErr != nil, ctx.Err() != nil
+	info := sourcegraph.BuildUpdate{EndedAt: &now, Success: &success, Failure: &failure}
+	if killed {
+		info.Killed = &killed
+	}
+	_, _, err := b.Client.Builds.Update(finishCtx, b.Spec(), info)
+	return err
+}
+
+// heartbeat periodically updates the build's HeartbeatAt until ctx is
+// done.
+func (w *Worker) heartbeat(ctx context.Context, b *Build) {
+	t := time.NewTicker(durationOrDefault(w.HeartbeatInterval, 15*time.Second))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			now := time.Now()
+			// Ignore errors; a missed heartbeat is not fatal, and the
+			// next one may succeed.
+			b.Client.Builds.Update(ctx, b.Spec(), sourcegraph.BuildUpdate{HeartbeatAt: &now})
+		}
+	}
+}
+
+// performTasks plans, creates, and performs the build's tasks. It
+// returns the first error that caused the build to fail.
+func (w *Worker) performTasks(ctx context.Context, b *Build) error {
+	tasks, err := w.Plan(ctx, b)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Order < tasks[j].Order })
+
+	bts := make([]*sourcegraph.BuildTask, len(tasks))
+	for i, task := range tasks {
+		bt := task.BuildTask
+		bt.BID = b.BID
+		bts[i] = &bt
+	}
+	created, _, err := b.Client.Builds.CreateTasks(ctx, b.Spec(), bts)
+	if err != nil {
+		return err
+	}
+	if len(created) != len(tasks) {
+		return fmt.Errorf("created %d tasks, want %d", len(created), len(tasks))
+	}
+	for i, task := range tasks {
+		task.BuildTask = *created[i]
+	}
+
+	var buildErr error
+	for _, task := range tasks {
+		if task.Queue {
+			continue
+		}
+		if buildErr == nil {
+			buildErr = ctx.Err()
+		}
+		if buildErr != nil {
+			// End the tasks that won't be performed, instead of
+			// leaving them created but never ended.
+			w.endTask(ctx, b, task, buildErr)
+			continue
+		}
+		buildErr = w.performTask(ctx, b, task)
+	}
+	if buildErr == nil {
+		buildErr = ctx.Err()
+	}
+	return buildErr
+}
+
+// performTask performs a single (non-queued) task and updates its
+// status.
+func (w *Worker) performTask(ctx context.Context, b *Build, task *Task) error {
+	if task.Run == nil {
+		err := fmt.Errorf("task %s (%s) has no Run func", task.Spec().IDString(), task.Op)
+		w.endTask(ctx, b, task, err)
+		return err
+	}
+
+	now := time.Now()
+	if _, _, err := b.Client.Builds.UpdateTask(ctx, task.Spec(), sourcegraph.TaskUpdate{StartedAt: &now}); err != nil {
+		// The task may have been started anyway, so end it.
+		w.endTask(ctx, b, task, err)
+		return err
+	}
+
+	runErr := w.runTask(ctx, task)
+	if err := w.endTask(ctx, b, task, runErr); err != nil && runErr == nil {
+		return err
+	}
+	return runErr
+}
+
+// runTask runs the task, writing its output to the task's log.
+func (w *Worker) runTask(ctx context.Context, task *Task) error {
+	var log io.WriteCloser = nopCloser{ioutil.Discard}
+	if w.TaskLog != nil {
+		var err error
+		log, err = w.TaskLog(&task.BuildTask)
+		if err != nil {
+			return err
+		}
+	}
+	runErr := task.Run(ctx, log)
+	if runErr != nil {
+		fmt.Fprintf(log, "task failed: %s\n", runErr)
+	}
+	if err := log.Close(); err != nil && runErr == nil {
+		runErr = err
+	}
+	return runErr
+}
+
+// endTask ends the task, marking it as failed if taskErr is non-nil
+// (and as killed if ctx was also canceled).
+func (w *Worker) endTask(ctx context.Context, b *Build, task *Task, taskErr error) error {
+	// Report the outcome even if ctx was canceled.
+	finishCtx, cancel := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancel()
+	now := time.Now()
+	success, failure, killed := taskErr == nil, taskErr != nil, taskErr != nil && ctx.Err() != nil
+	info := sourcegraph.TaskUpdate{EndedAt: &now, Success: &success, Failure: &failure}
+	if killed {
+		info.Killed = &killed
+	}
+	_, _, err := b.Client.Builds.UpdateTask(finishCtx, task.Spec(), info)
+	return err
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,353 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sourcegraph.com/sourcegraph/go-sourcegraph/auth"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/router"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraph"
+	"sourcegraph.com/sourcegraph/go-sourcegraph/sourcegraphtest"
+)
+
+// newTestServer returns a fake API server with a single queued
+// build. Its dequeue responses grant the ticket "t", and the
+// authorization headers of all other build requests are recorded in
+// *authHdrs.
+func newTestServer() (s *sourcegraphtest.Server, build *sourcegraph.Build, authHdrs *[]string) {
+	s = sourcegraphtest.NewServer()
+	s.AddRepo(&sourcegraph.Repo{URI: "r.com/x"})
+	build = s.AddBuild(&sourcegraph.Build{BuildConfig: sourcegraph.BuildConfig{Queue: true}})
+
+	var mu sync.Mutex
+	authHdrs = new([]string)
+	h := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/builds/next") {
+			w.Header().Set("authorization", auth.TicketAuthScheme+"t")
+		} else if strings.Contains(r.URL.Path, "/builds/") {
+			mu.Lock()
+			*authHdrs = append(*authHdrs, r.Header.Get("authorization"))
+			mu.Unlock()
+		}
+		h.ServeHTTP(w, r)
+	})
+	return s, build, authHdrs
+}
+
+type testLogs struct {
+	mu   sync.Mutex
+	logs map[string]*bytes.Buffer // op -> log
+}
+
+func (l *testLogs) open(task *sourcegraph.BuildTask) (io.WriteCloser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.logs == nil {
+		l.logs = map[string]*bytes.Buffer{}
+	}
+	l.logs[task.Op] = new(bytes.Buffer)
+	return nopCloser{l.logs[task.Op]}, nil
+}
+
+// getTasks returns the build's tasks, by op.
+func getTasks(t *testing.T, s *sourcegraphtest.Server, build *sourcegraph.Build) map[string]*sourcegraph.BuildTask {
+	tasks, _, err := s.Client.Builds.ListBuildTasks(context.Background(), build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byOp := make(map[string]*sourcegraph.BuildTask, len(tasks))
+	for _, task := range tasks {
+		byOp[task.Op] = task
+	}
+	return byOp
+}
+
+func TestWorker_RunOnce(t *testing.T) {
+	s, build, authHdrs := newTestServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	var ran []string
+	run := func(op string) func(context.Context, io.Writer) error {
+		return func(ctx context.Context, log io.Writer) error {
+			ran = append(ran, op)
+			io.WriteString(log, op)
+			return nil
+		}
+	}
+	var logs testLogs
+	w := &Worker{
+		Client: s.Client,
+		Host:   "h",
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			if b.BID != build.BID {
+				t.Errorf("got build %d, want %d", b.BID, build.BID)
+			}
+			return []*Task{
+				{BuildTask: sourcegraph.BuildTask{Op: "b", Order: 2}, Run: run("b")},
+				{BuildTask: sourcegraph.BuildTask{Op: "a", Order: 1}, Run: run("a")},
+				{BuildTask: sourcegraph.BuildTask{Op: sourcegraph.ImportTaskOp, Order: 3, Queue: true}},
+			}, nil
+		},
+		TaskLog: logs.open,
+	}
+
+	performed, err := w.RunOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !performed {
+		t.Fatal("!performed")
+	}
+	workerAuthHdrs := append([]string{}, *authHdrs...)
+	if want := []string{"a", "b"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("got tasks run %v, want %v", ran, want)
+	}
+	if got := logs.logs["a"].String(); got != "a" {
+		t.Errorf("got task log %q, want %q", got, "a")
+	}
+
+	b, _, err := s.Client.Builds.Get(ctx, build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Success || b.Failure || b.Killed || !b.EndedAt.Valid || !b.HeartbeatAt.Valid || b.Host != "h" {
+		t.Errorf("got build %+v, want successfully ended build on host h", b)
+	}
+
+	tasks, _, err := s.Client.Builds.ListBuildTasks(ctx, build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.Queue {
+			if task.StartedAt.Valid || task.Success || task.Failure {
+				t.Errorf("queued task %+v was performed, want it left to the queue", task)
+			}
+		} else if !task.Success || !task.EndedAt.Valid {
+			t.Errorf("got task %+v, want successfully ended", task)
+		}
+	}
+
+	if len(workerAuthHdrs) == 0 {
+		t.Fatal("no build requests recorded")
+	}
+	for _, hdr := range workerAuthHdrs {
+		if want := auth.TicketAuthScheme + "t"; hdr != want {
+			t.Errorf("got authorization header %q, want %q", hdr, want)
+		}
+	}
+}
+
+func TestWorker_RunOnce_emptyQueue(t *testing.T) {
+	s := sourcegraphtest.NewServer()
+	defer s.Close()
+
+	w := &Worker{Client: s.Client}
+	performed, err := w.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if performed {
+		t.Error("performed, want no build performed")
+	}
+}
+
+func TestWorker_RunOnce_taskFailure(t *testing.T) {
+	s, build, _ := newTestServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	var ranB bool
+	w := &Worker{
+		Client: s.Client,
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			return []*Task{
+				{BuildTask: sourcegraph.BuildTask{Op: "a", Order: 1}, Run: func(context.Context, io.Writer) error { return errors.New("x") }},
+				{BuildTask: sourcegraph.BuildTask{Op: "b", Order: 2}, Run: func(context.Context, io.Writer) error { ranB = true; return nil }},
+			}, nil
+		},
+	}
+	if _, err := w.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ranB {
+		t.Error("task b ran after task a failed")
+	}
+
+	b, _, err := s.Client.Builds.Get(ctx, build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Success || !b.Failure || b.Killed {
+		t.Errorf("got build %+v, want failed (not killed)", b)
+	}
+	for op, task := range getTasks(t, s, build) {
+		if task.Success || !task.Failure || task.Killed || !task.EndedAt.Valid {
+			t.Errorf("got task %s %+v, want failed (not killed)", op, task)
+		}
+	}
+}
+
+func TestWorker_RunOnce_taskLogError(t *testing.T) {
+	s, build, _ := newTestServer()
+	defer s.Close()
+	ctx := context.Background()
+
+	w := &Worker{
+		Client: s.Client,
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			return []*Task{{
+				BuildTask: sourcegraph.BuildTask{Op: "a"},
+				Run: func(context.Context, io.Writer) error {
+					t.Error("task ran without a log")
+					return nil
+				},
+			}}, nil
+		},
+		TaskLog: func(*sourcegraph.BuildTask) (io.WriteCloser, error) {
+			return nil, errors.New("x")
+		},
+	}
+	if _, err := w.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _, err := s.Client.Builds.Get(ctx, build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Success || !b.Failure || !b.EndedAt.Valid {
+		t.Errorf("got build %+v, want failed", b)
+	}
+	task := getTasks(t, s, build)["a"]
+	if task == nil || task.Success || !task.Failure || !task.StartedAt.Valid || !task.EndedAt.Valid {
+		t.Errorf("got task %+v, want started and failed", task)
+	}
+}
+
+func TestWorker_RunOnce_killed(t *testing.T) {
+	s, build, _ := newTestServer()
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Worker{
+		Client:            s.Client,
+		HeartbeatInterval: time.Millisecond,
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			return []*Task{
+				{
+					BuildTask: sourcegraph.BuildTask{Op: "a", Order: 1},
+					Run: func(ctx context.Context, log io.Writer) error {
+						time.Sleep(20 * time.Millisecond) // let some heartbeats happen
+						cancel()
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				},
+				{BuildTask: sourcegraph.BuildTask{Op: "b", Order: 2}, Run: func(context.Context, io.Writer) error { return nil }},
+			}, nil
+		},
+	}
+	if _, err := w.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _, err := s.Client.Builds.Get(context.Background(), build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Success || !b.Failure || !b.Killed || !b.EndedAt.Valid {
+		t.Errorf("got build %+v, want killed", b)
+	}
+	if !b.HeartbeatAt.Time.After(b.StartedAt.Time) {
+		t.Errorf("got HeartbeatAt %s, want after StartedAt %s", b.HeartbeatAt.Time, b.StartedAt.Time)
+	}
+	for op, task := range getTasks(t, s, build) {
+		if task.Success || !task.Failure || !task.Killed || !task.EndedAt.Valid {
+			t.Errorf("got task %s %+v, want killed", op, task)
+		}
+	}
+}
+
+func TestWorker_RunOnce_clientConfig(t *testing.T) {
+	s, _, _ := newTestServer()
+	defer s.Close()
+
+	var mu sync.Mutex
+	var routes []string
+	s.Client.Middleware = []sourcegraph.Middleware{func(ctx context.Context, info *sourcegraph.RequestInfo, req *http.Request, send sourcegraph.Sender) (*sourcegraph.HTTPResponse, error) {
+		mu.Lock()
+		routes = append(routes, info.Route)
+		mu.Unlock()
+		return send(ctx, req)
+	}}
+	w := &Worker{
+		Client: s.Client,
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			return nil, nil
+		},
+	}
+	if _, err := w.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The build's client must use the worker's client's middleware.
+	var updates int
+	for _, route := range routes {
+		if route == router.BuildUpdate {
+			updates++
+		}
+	}
+	if updates < 2 {
+		t.Errorf("got routes %v, want the build's updates to pass through the worker's client's middleware", routes)
+	}
+}
+
+func TestWorker_RunOnce_startFailure(t *testing.T) {
+	s, build, _ := newTestServer()
+	defer s.Close()
+
+	// Fail the first update (which sets the build's host).
+	var failed bool
+	h := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && !failed {
+			failed = true
+			http.Error(w, "error", http.StatusInternalServerError)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+
+	w := &Worker{
+		Client: s.Client,
+		Plan: func(ctx context.Context, b *Build) ([]*Task, error) {
+			t.Error("build was performed")
+			return nil, nil
+		},
+	}
+	if _, err := w.RunOnce(context.Background()); err == nil {
+		t.Fatal("got nil error, want the update's error")
+	}
+
+	b, _, err := s.Client.Builds.Get(context.Background(), build.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Success || !b.Failure || !b.EndedAt.Valid {
+		t.Errorf("got build %+v, want failed", b)
+	}
+}